@@ -0,0 +1,195 @@
+// Package alertmanager converts Prometheus Alertmanager webhook payloads to Slack Manager alerts.
+//
+// Example:
+//
+//	alerts, err := alertmanager.ToAlerts(body, nil)
+//	if err != nil {
+//		// handle error
+//	}
+//
+//	for _, alert := range alerts {
+//		alert.Clean()
+//		// send alert
+//	}
+package alertmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/adapters/internal/labels"
+)
+
+const (
+	// StatusFiring is the status of a firing Alertmanager alert.
+	StatusFiring = labels.StatusFiring
+
+	// StatusResolved is the status of a resolved Alertmanager alert.
+	StatusResolved = labels.StatusResolved
+
+	// DefaultSeverityLabel is the default name of the label holding the alert severity.
+	DefaultSeverityLabel = labels.DefaultSeverityLabel
+
+	// DefaultRouteKeyLabel is the default name of the label holding the Slack Manager route key.
+	DefaultRouteKeyLabel = labels.DefaultRouteKeyLabel
+
+	// DefaultAutoResolve is the default duration after which issues are automatically resolved,
+	// if no resolved notification is received from Alertmanager.
+	DefaultAutoResolve = labels.DefaultAutoResolve
+)
+
+// Message is an Alertmanager webhook payload (version 4).
+type Message struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []*Alert          `json:"alerts"`
+}
+
+// Alert is a single alert in an Alertmanager webhook payload.
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Config defines how Alertmanager alerts are converted to Slack Manager alerts.
+// Unset label names are not mapped, and zero or negative AutoResolve values are replaced by DefaultAutoResolve.
+type Config = labels.Config
+
+// DefaultConfig returns a new Config with the default label names and settings.
+func DefaultConfig() *Config {
+	return labels.DefaultConfig()
+}
+
+// Decode decodes an Alertmanager webhook payload.
+// An error is returned if the payload is not valid JSON, or if the payload version is not supported.
+func Decode(body []byte) (*Message, error) {
+	var msg Message
+
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("failed to decode alertmanager webhook payload: %w", err)
+	}
+
+	if msg.Version != "" && msg.Version != "4" {
+		return nil, fmt.Errorf("alertmanager webhook payload version '%s' is not supported, expected '4'", msg.Version)
+	}
+
+	return &msg, nil
+}
+
+// ToAlerts decodes an Alertmanager webhook payload, and converts it to one Slack Manager alert per Alertmanager alert.
+// The default config is used if cfg is nil.
+func ToAlerts(body []byte, cfg *Config) ([]*types.Alert, error) {
+	msg, err := Decode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.ToAlerts(cfg)
+}
+
+// ToAlerts converts the message to one Slack Manager alert per Alertmanager alert.
+// The default config is used if cfg is nil.
+//
+// The returned alerts are not cleaned. Call Alert.Clean before validating or sending them.
+func (m *Message) ToAlerts(cfg *Config) ([]*types.Alert, error) {
+	if m == nil {
+		return nil, errors.New("message is nil")
+	}
+
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	alerts := make([]*types.Alert, 0, len(m.Alerts))
+
+	for i, a := range m.Alerts {
+		if a == nil {
+			return nil, fmt.Errorf("alerts[%d] is nil", i)
+		}
+
+		alerts = append(alerts, m.toAlert(a, cfg))
+	}
+
+	return alerts, nil
+}
+
+func (m *Message) toAlert(a *Alert, cfg *Config) *types.Alert {
+	alertName := a.Labels[labels.AlertNameLabel]
+	summary := a.Annotations["summary"]
+
+	header := summary
+	if header == "" {
+		header = alertName
+	}
+
+	if header == "" {
+		header = "Alertmanager alert"
+	}
+
+	alert := types.NewAlert(cfg.Severity(a.Status, a.Labels))
+	alert.CorrelationID = a.Fingerprint
+	alert.Header = ":status: " + header
+	alert.Text = a.Annotations["description"]
+	alert.FallbackText = header
+	alert.Link = a.GeneratorURL
+	alert.IssueFollowUpEnabled = true
+	alert.AutoResolveSeconds = cfg.AutoResolveSeconds()
+
+	if !a.StartsAt.IsZero() {
+		alert.Timestamp = a.StartsAt
+	}
+
+	if a.Status == StatusResolved && !a.EndsAt.IsZero() {
+		alert.Timestamp = a.EndsAt
+	}
+
+	alert.RouteKey = cfg.RouteKey(a.Labels)
+	alert.Fields = labelFields(a.Labels, cfg)
+
+	alert.Metadata["source"] = "alertmanager"
+	alert.Metadata["fingerprint"] = a.Fingerprint
+	alert.Metadata["status"] = a.Status
+	alert.Metadata["receiver"] = m.Receiver
+	alert.Metadata["groupKey"] = m.GroupKey
+	alert.Metadata["labels"] = labels.ToAnyMap(a.Labels)
+	alert.Metadata["annotations"] = labels.ToAnyMap(a.Annotations)
+
+	return alert
+}
+
+// labelFields returns the alert labels as fields, sorted by label name.
+// The alertname, severity and route key labels are excluded, since they are already part of the alert.
+func labelFields(alertLabels map[string]string, cfg *Config) []*types.Field {
+	var fields []*types.Field
+
+	for _, name := range slices.Sorted(maps.Keys(alertLabels)) {
+		if cfg.IsMapped(name) {
+			continue
+		}
+
+		if len(fields) == types.MaxFieldCount {
+			break
+		}
+
+		fields = append(fields, &types.Field{Title: name, Value: alertLabels[name]})
+	}
+
+	return fields
+}
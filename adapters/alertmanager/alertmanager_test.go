@@ -0,0 +1,136 @@
+package alertmanager_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/adapters/alertmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	return body
+}
+
+func TestToAlerts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("firing alerts", func(t *testing.T) {
+		t.Parallel()
+
+		alerts, err := alertmanager.ToAlerts(readFixture(t, "firing.json"), nil)
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+
+		a := alerts[0]
+		assert.Equal(t, types.AlertError, a.Severity)
+		assert.Equal(t, "c5a1e8d1f2b3a4c5", a.CorrelationID)
+		assert.Equal(t, ":status: Disk usage above 90% on db-1", a.Header)
+		assert.Equal(t, "Disk usage on `/data` is 95%.", a.Text)
+		assert.Equal(t, "https://prometheus.example.com/graph?g0.expr=disk_usage", a.Link)
+		assert.Equal(t, "storage", a.RouteKey)
+		assert.True(t, a.IssueFollowUpEnabled)
+		assert.Equal(t, int(alertmanager.DefaultAutoResolve/time.Second), a.AutoResolveSeconds)
+		assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), a.Timestamp)
+		assert.Equal(t, []*types.Field{
+			{Title: "instance", Value: "db-1:9100"},
+			{Title: "mountpoint", Value: "/data"},
+		}, a.Fields)
+		assert.Equal(t, "firing", a.Metadata["status"])
+		assert.Equal(t, "storage-team", a.Metadata["receiver"])
+		assert.Equal(t, map[string]any{
+			"alertname":  "DiskUsageHigh",
+			"instance":   "db-1:9100",
+			"mountpoint": "/data",
+			"route_key":  "storage",
+			"severity":   "critical",
+		}, a.Metadata["labels"])
+
+		b := alerts[1]
+		assert.Equal(t, types.AlertWarning, b.Severity)
+		assert.Equal(t, ":status: DiskUsageHigh", b.Header)
+		assert.Empty(t, b.RouteKey)
+
+		for _, alert := range alerts {
+			alert.Clean()
+			require.NoError(t, alert.Validate())
+		}
+	})
+
+	t.Run("resolved alerts", func(t *testing.T) {
+		t.Parallel()
+
+		alerts, err := alertmanager.ToAlerts(readFixture(t, "resolved.json"), nil)
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, types.AlertResolved, alerts[0].Severity)
+		assert.Equal(t, "c5a1e8d1f2b3a4c5", alerts[0].CorrelationID)
+		assert.Equal(t, time.Date(2026, 1, 2, 4, 0, 0, 0, time.UTC), alerts[0].Timestamp)
+
+		alerts[0].Clean()
+		require.NoError(t, alerts[0].Validate())
+	})
+
+	t.Run("custom label names", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := alertmanager.DefaultConfig()
+		cfg.SeverityLabel = "priority"
+		cfg.RouteKeyLabel = "instance"
+		cfg.DefaultSeverity = types.AlertWarning
+		cfg.AutoResolve = time.Hour
+
+		alerts, err := alertmanager.ToAlerts(readFixture(t, "firing.json"), cfg)
+		require.NoError(t, err)
+
+		a := alerts[0]
+		assert.Equal(t, types.AlertWarning, a.Severity)
+		assert.Equal(t, "db-1:9100", a.RouteKey)
+		assert.Equal(t, 3600, a.AutoResolveSeconds)
+		assert.Equal(t, []*types.Field{
+			{Title: "mountpoint", Value: "/data"},
+			{Title: "route_key", Value: "storage"},
+			{Title: "severity", Value: "critical"},
+		}, a.Fields)
+	})
+
+	t.Run("partial config should use defaults for unset settings", func(t *testing.T) {
+		t.Parallel()
+
+		alerts, err := alertmanager.ToAlerts(readFixture(t, "firing.json"), &alertmanager.Config{SeverityLabel: "severity"})
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+
+		a := alerts[0]
+		assert.Equal(t, types.AlertError, a.Severity)
+		assert.Empty(t, a.RouteKey)
+		assert.Equal(t, int(alertmanager.DefaultAutoResolve/time.Second), a.AutoResolveSeconds)
+
+		for _, alert := range alerts {
+			alert.Clean()
+			require.NoError(t, alert.Validate())
+		}
+	})
+
+	t.Run("invalid payloads should return an error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := alertmanager.ToAlerts([]byte("not json"), nil)
+		require.Error(t, err)
+
+		_, err = alertmanager.ToAlerts([]byte(`{"version":"3"}`), nil)
+		require.ErrorContains(t, err, "version '3' is not supported")
+
+		_, err = alertmanager.ToAlerts([]byte(`{"version":"4","alerts":[null]}`), nil)
+		require.ErrorContains(t, err, "alerts[0] is nil")
+	})
+}
@@ -0,0 +1,243 @@
+// Package grafana converts Grafana unified alerting (contact point) webhook payloads to Slack Manager alerts.
+//
+// Example:
+//
+//	alerts, err := grafana.ToAlerts(body, nil)
+//	if err != nil {
+//		// handle error
+//	}
+//
+//	for _, alert := range alerts {
+//		alert.Clean()
+//		// send alert
+//	}
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/adapters/internal/labels"
+)
+
+const (
+	// StatusFiring is the status of a firing Grafana alert.
+	StatusFiring = labels.StatusFiring
+
+	// StatusResolved is the status of a resolved Grafana alert.
+	StatusResolved = labels.StatusResolved
+
+	// DefaultSeverityLabel is the default name of the label holding the alert severity.
+	DefaultSeverityLabel = labels.DefaultSeverityLabel
+
+	// DefaultRouteKeyLabel is the default name of the label holding the Slack Manager route key.
+	DefaultRouteKeyLabel = labels.DefaultRouteKeyLabel
+
+	// DefaultAutoResolve is the default duration after which issues are automatically resolved,
+	// if no resolved notification is received from Grafana.
+	DefaultAutoResolve = labels.DefaultAutoResolve
+)
+
+// Message is a Grafana unified alerting webhook payload.
+type Message struct {
+	Version           string            `json:"version"`
+	OrgID             int64             `json:"orgId"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	State             string            `json:"state"`
+	Receiver          string            `json:"receiver"`
+	Title             string            `json:"title"`
+	Message           string            `json:"message"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []*Alert          `json:"alerts"`
+}
+
+// Alert is a single alert in a Grafana webhook payload.
+type Alert struct {
+	Status       string             `json:"status"`
+	Labels       map[string]string  `json:"labels"`
+	Annotations  map[string]string  `json:"annotations"`
+	StartsAt     time.Time          `json:"startsAt"`
+	EndsAt       time.Time          `json:"endsAt"`
+	GeneratorURL string             `json:"generatorURL"`
+	Fingerprint  string             `json:"fingerprint"`
+	SilenceURL   string             `json:"silenceURL"`
+	DashboardURL string             `json:"dashboardURL"`
+	PanelURL     string             `json:"panelURL"`
+	Values       map[string]float64 `json:"values"`
+	ValueString  string             `json:"valueString"`
+}
+
+// Config defines how Grafana alerts are converted to Slack Manager alerts.
+// Unset label names are not mapped, and zero or negative AutoResolve values are replaced by DefaultAutoResolve.
+type Config = labels.Config
+
+// DefaultConfig returns a new Config with the default label names and settings.
+func DefaultConfig() *Config {
+	return labels.DefaultConfig()
+}
+
+// Decode decodes a Grafana webhook payload.
+// An error is returned if the payload is not valid JSON, or if the payload version is not supported.
+func Decode(body []byte) (*Message, error) {
+	var msg Message
+
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("failed to decode grafana webhook payload: %w", err)
+	}
+
+	if msg.Version != "" && msg.Version != "1" {
+		return nil, fmt.Errorf("grafana webhook payload version '%s' is not supported, expected '1'", msg.Version)
+	}
+
+	return &msg, nil
+}
+
+// ToAlerts decodes a Grafana webhook payload, and converts it to one Slack Manager alert per Grafana alert.
+// The default config is used if cfg is nil.
+func ToAlerts(body []byte, cfg *Config) ([]*types.Alert, error) {
+	msg, err := Decode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.ToAlerts(cfg)
+}
+
+// ToAlerts converts the message to one Slack Manager alert per Grafana alert.
+// The default config is used if cfg is nil.
+//
+// The panel URL (or dashboard URL, or generator URL) is used as the alert link. The dashboard, panel and silence
+// URLs are added as link fields, unless they are too long to fit in a field value, and are always available in the
+// alert metadata. The query values are added as a field, followed by the alert labels.
+//
+// The returned alerts are not cleaned. Call Alert.Clean before validating or sending them.
+func (m *Message) ToAlerts(cfg *Config) ([]*types.Alert, error) {
+	if m == nil {
+		return nil, errors.New("message is nil")
+	}
+
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	alerts := make([]*types.Alert, 0, len(m.Alerts))
+
+	for i, a := range m.Alerts {
+		if a == nil {
+			return nil, fmt.Errorf("alerts[%d] is nil", i)
+		}
+
+		alerts = append(alerts, m.toAlert(a, cfg))
+	}
+
+	return alerts, nil
+}
+
+func (m *Message) toAlert(a *Alert, cfg *Config) *types.Alert {
+	header := a.Annotations["summary"]
+
+	if header == "" {
+		header = a.Labels[labels.AlertNameLabel]
+	}
+
+	if header == "" {
+		header = "Grafana alert"
+	}
+
+	alert := types.NewAlert(cfg.Severity(a.Status, a.Labels))
+	alert.CorrelationID = a.Fingerprint
+	alert.Header = ":status: " + header
+	alert.Text = a.Annotations["description"]
+	alert.FallbackText = header
+	alert.IssueFollowUpEnabled = true
+	alert.AutoResolveSeconds = cfg.AutoResolveSeconds()
+
+	switch {
+	case a.PanelURL != "":
+		alert.Link = a.PanelURL
+	case a.DashboardURL != "":
+		alert.Link = a.DashboardURL
+	default:
+		alert.Link = a.GeneratorURL
+	}
+
+	if !a.StartsAt.IsZero() {
+		alert.Timestamp = a.StartsAt
+	}
+
+	if a.Status == StatusResolved && !a.EndsAt.IsZero() {
+		alert.Timestamp = a.EndsAt
+	}
+
+	alert.RouteKey = cfg.RouteKey(a.Labels)
+	alert.Fields = fields(a, cfg)
+
+	alert.Metadata["source"] = "grafana"
+	alert.Metadata["orgId"] = m.OrgID
+	alert.Metadata["fingerprint"] = a.Fingerprint
+	alert.Metadata["status"] = a.Status
+	alert.Metadata["receiver"] = m.Receiver
+	alert.Metadata["groupKey"] = m.GroupKey
+	alert.Metadata["generatorURL"] = a.GeneratorURL
+	alert.Metadata["silenceURL"] = a.SilenceURL
+	alert.Metadata["dashboardURL"] = a.DashboardURL
+	alert.Metadata["panelURL"] = a.PanelURL
+	alert.Metadata["labels"] = labels.ToAnyMap(a.Labels)
+	alert.Metadata["annotations"] = labels.ToAnyMap(a.Annotations)
+
+	return alert
+}
+
+// fields returns the link fields, the values field and the label fields for the alert, limited to MaxFieldCount fields.
+// The alertname, severity and route key labels are excluded, since they are already part of the alert.
+func fields(a *Alert, cfg *Config) []*types.Field {
+	var result []*types.Field
+
+	addLink := func(title, url string) {
+		value := fmt.Sprintf("<%s|Open>", url)
+		if url != "" && utf8.RuneCountInString(value) <= types.MaxFieldValueLength {
+			result = append(result, &types.Field{Title: title, Value: value})
+		}
+	}
+
+	addLink("Dashboard", a.DashboardURL)
+	addLink("Panel", a.PanelURL)
+	addLink("Silence", a.SilenceURL)
+
+	if len(a.Values) > 0 {
+		values := make([]string, 0, len(a.Values))
+
+		for _, name := range slices.Sorted(maps.Keys(a.Values)) {
+			values = append(values, name+"="+strconv.FormatFloat(a.Values[name], 'g', -1, 64))
+		}
+
+		result = append(result, &types.Field{Title: "Values", Value: strings.Join(values, ", ")})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(a.Labels)) {
+		if cfg.IsMapped(name) {
+			continue
+		}
+
+		result = append(result, &types.Field{Title: name, Value: a.Labels[name]})
+	}
+
+	if len(result) > types.MaxFieldCount {
+		result = result[:types.MaxFieldCount]
+	}
+
+	return result
+}
@@ -0,0 +1,126 @@
+package grafana_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/adapters/grafana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	return body
+}
+
+func TestToAlerts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("firing alerts", func(t *testing.T) {
+		t.Parallel()
+
+		alerts, err := grafana.ToAlerts(readFixture(t, "firing.json"), nil)
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+
+		a := alerts[0]
+		assert.Equal(t, types.AlertWarning, a.Severity)
+		assert.Equal(t, "57c6d9296de2ad39", a.CorrelationID)
+		assert.Equal(t, ":status: Disk usage above 90% on db-1", a.Header)
+		assert.Equal(t, "Disk usage on `/data` is 95%.", a.Text)
+		assert.Equal(t, "https://grafana.example.com/d/storage?orgId=1&viewPanel=2", a.Link)
+		assert.Equal(t, "storage", a.RouteKey)
+		assert.True(t, a.IssueFollowUpEnabled)
+		assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), a.Timestamp)
+		assert.Equal(t, []*types.Field{
+			{Title: "Dashboard", Value: "<https://grafana.example.com/d/storage?orgId=1|Open>"},
+			{Title: "Panel", Value: "<https://grafana.example.com/d/storage?orgId=1&viewPanel=2|Open>"},
+			{Title: "Silence", Value: "<https://grafana.example.com/alerting/silence/new?alertmanager=grafana&matcher=alertname%3DHigh+disk+usage&matcher=instance%3Ddb-1|Open>"},
+			{Title: "Values", Value: "B=95.2, C=1"},
+			{Title: "grafana_folder", Value: "Storage"},
+			{Title: "instance", Value: "db-1"},
+		}, a.Fields)
+		assert.Equal(t, int64(1), a.Metadata["orgId"])
+
+		// Silence URL is too long for a field value, and no severity label is set
+		b := alerts[1]
+		assert.Equal(t, types.AlertError, b.Severity)
+		assert.Equal(t, ":status: DatasourceNoData", b.Header)
+		assert.Equal(t, "https://grafana.example.com/alerting/grafana/abc123/view?orgId=1", b.Link)
+		for _, f := range b.Fields {
+			assert.NotEqual(t, "Silence", f.Title)
+		}
+		assert.Contains(t, b.Metadata["silenceURL"], "matcher=rulename")
+
+		for _, alert := range alerts {
+			alert.Clean()
+			require.NoError(t, alert.Validate())
+		}
+	})
+
+	t.Run("resolved alerts", func(t *testing.T) {
+		t.Parallel()
+
+		alerts, err := grafana.ToAlerts(readFixture(t, "resolved.json"), nil)
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, types.AlertResolved, alerts[0].Severity)
+		assert.Equal(t, "57c6d9296de2ad39", alerts[0].CorrelationID)
+		assert.Equal(t, time.Date(2026, 1, 2, 4, 0, 0, 0, time.UTC), alerts[0].Timestamp)
+
+		alerts[0].Clean()
+		require.NoError(t, alerts[0].Validate())
+	})
+
+	t.Run("custom config", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := grafana.DefaultConfig()
+		cfg.RouteKeyLabel = "grafana_folder"
+		cfg.DefaultSeverity = types.AlertPanic
+		cfg.AutoResolve = time.Hour
+
+		alerts, err := grafana.ToAlerts(readFixture(t, "firing.json"), cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "Storage", alerts[0].RouteKey)
+		assert.Equal(t, 3600, alerts[0].AutoResolveSeconds)
+		assert.Equal(t, types.AlertPanic, alerts[1].Severity)
+	})
+
+	t.Run("partial config should use defaults for unset settings", func(t *testing.T) {
+		t.Parallel()
+
+		alerts, err := grafana.ToAlerts(readFixture(t, "firing.json"), &grafana.Config{SeverityLabel: "severity"})
+		require.NoError(t, err)
+		require.NotEmpty(t, alerts)
+
+		assert.Empty(t, alerts[0].RouteKey)
+		assert.Equal(t, int(grafana.DefaultAutoResolve/time.Second), alerts[0].AutoResolveSeconds)
+
+		for _, alert := range alerts {
+			alert.Clean()
+			require.NoError(t, alert.Validate())
+		}
+	})
+
+	t.Run("invalid payloads should return an error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := grafana.ToAlerts([]byte("not json"), nil)
+		require.Error(t, err)
+
+		_, err = grafana.ToAlerts([]byte(`{"version":"2"}`), nil)
+		require.ErrorContains(t, err, "version '2' is not supported")
+
+		_, err = grafana.ToAlerts([]byte(`{"version":"1","alerts":[null]}`), nil)
+		require.ErrorContains(t, err, "alerts[0] is nil")
+	})
+}
@@ -0,0 +1,117 @@
+// Package labels maps label based alerts, such as Prometheus Alertmanager and Grafana alerts, to Slack Manager alerts.
+// It holds the configuration and mapping logic shared by the adapters subpackages.
+package labels
+
+import (
+	"strings"
+	"time"
+
+	"github.com/slackmgr/types"
+)
+
+const (
+	// StatusFiring is the status of a firing alert.
+	StatusFiring = "firing"
+
+	// StatusResolved is the status of a resolved alert.
+	StatusResolved = "resolved"
+
+	// AlertNameLabel is the name of the label holding the alert name.
+	AlertNameLabel = "alertname"
+
+	// DefaultSeverityLabel is the default name of the label holding the alert severity.
+	DefaultSeverityLabel = "severity"
+
+	// DefaultRouteKeyLabel is the default name of the label holding the Slack Manager route key.
+	DefaultRouteKeyLabel = "route_key"
+
+	// DefaultAutoResolve is the default duration after which issues are automatically resolved,
+	// if no resolved notification is received from the alert source.
+	DefaultAutoResolve = 24 * time.Hour
+)
+
+// Config defines how label based alerts are converted to Slack Manager alerts.
+type Config struct {
+	// SeverityLabel is the name of the label holding the alert severity, such as 'critical' or 'warning'.
+	// Values are matched case-insensitively against the AlertSeverity values, and 'critical' is mapped to 'error'.
+	SeverityLabel string `json:"severityLabel"`
+
+	// RouteKeyLabel is the name of the label holding the Slack Manager route key.
+	// If the label is missing, the route key is left empty.
+	RouteKeyLabel string `json:"routeKeyLabel"`
+
+	// DefaultSeverity is used for firing alerts without a (known) severity label value.
+	DefaultSeverity types.AlertSeverity `json:"defaultSeverity"`
+
+	// AutoResolve is the duration after which issues are automatically resolved,
+	// if no resolved notification is received from the alert source. DefaultAutoResolve is used if zero or negative.
+	AutoResolve time.Duration `json:"autoResolve"`
+}
+
+// DefaultConfig returns a new Config with the default label names and settings.
+func DefaultConfig() *Config {
+	return &Config{
+		SeverityLabel:   DefaultSeverityLabel,
+		RouteKeyLabel:   DefaultRouteKeyLabel,
+		DefaultSeverity: types.AlertError,
+		AutoResolve:     DefaultAutoResolve,
+	}
+}
+
+// Severity returns the Slack Manager severity for an alert with the given status and labels.
+func (c *Config) Severity(status string, labels map[string]string) types.AlertSeverity {
+	if status == StatusResolved {
+		return types.AlertResolved
+	}
+
+	value := types.AlertSeverity(strings.ToLower(strings.TrimSpace(labels[c.SeverityLabel])))
+
+	if value == "critical" {
+		return types.AlertError
+	}
+
+	if types.SeverityIsValid(value) && value != types.AlertResolved {
+		return value
+	}
+
+	if c.DefaultSeverity == "" {
+		return types.AlertError
+	}
+
+	return c.DefaultSeverity
+}
+
+// RouteKey returns the Slack Manager route key from the labels, or an empty string if no route key label is configured.
+func (c *Config) RouteKey(labels map[string]string) string {
+	if c.RouteKeyLabel == "" {
+		return ""
+	}
+
+	return labels[c.RouteKeyLabel]
+}
+
+// AutoResolveSeconds returns the configured auto-resolve duration in seconds, or DefaultAutoResolve if it is not set.
+func (c *Config) AutoResolveSeconds() int {
+	if c.AutoResolve <= 0 {
+		return int(DefaultAutoResolve / time.Second)
+	}
+
+	return int(c.AutoResolve / time.Second)
+}
+
+// IsMapped reports whether the label is mapped to an alert property, i.e. the alert name, severity or route key label.
+// Mapped labels are not added as fields, since they are already part of the alert.
+func (c *Config) IsMapped(name string) bool {
+	return name == AlertNameLabel || name == c.SeverityLabel || name == c.RouteKeyLabel
+}
+
+// ToAnyMap converts a label (or annotation) map to a map that can be used as alert metadata.
+func ToAnyMap(m map[string]string) map[string]any {
+	result := make(map[string]any, len(m))
+
+	for k, v := range m {
+		result[k] = v
+	}
+
+	return result
+}
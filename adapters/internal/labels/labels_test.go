@@ -0,0 +1,59 @@
+package labels_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/adapters/internal/labels"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("severity should be mapped from the severity label", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := labels.DefaultConfig()
+
+		assert.Equal(t, types.AlertError, cfg.Severity(labels.StatusFiring, map[string]string{"severity": "Critical"}))
+		assert.Equal(t, types.AlertWarning, cfg.Severity(labels.StatusFiring, map[string]string{"severity": "warning"}))
+		assert.Equal(t, types.AlertError, cfg.Severity(labels.StatusFiring, map[string]string{"severity": "resolved"}))
+		assert.Equal(t, types.AlertResolved, cfg.Severity(labels.StatusResolved, map[string]string{"severity": "warning"}))
+
+		cfg.DefaultSeverity = types.AlertInfo
+		assert.Equal(t, types.AlertInfo, cfg.Severity(labels.StatusFiring, nil))
+
+		cfg.DefaultSeverity = ""
+		assert.Equal(t, types.AlertError, cfg.Severity(labels.StatusFiring, nil))
+	})
+
+	t.Run("route key should only be mapped if the label is configured", func(t *testing.T) {
+		t.Parallel()
+
+		lbls := map[string]string{"route_key": "storage", "": "empty"}
+
+		assert.Equal(t, "storage", labels.DefaultConfig().RouteKey(lbls))
+		assert.Empty(t, (&labels.Config{}).RouteKey(lbls))
+	})
+
+	t.Run("auto-resolve should fall back to the default", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, 3600, (&labels.Config{AutoResolve: time.Hour}).AutoResolveSeconds())
+		assert.Equal(t, int(labels.DefaultAutoResolve/time.Second), (&labels.Config{}).AutoResolveSeconds())
+		assert.Equal(t, int(labels.DefaultAutoResolve/time.Second), (&labels.Config{AutoResolve: -time.Second}).AutoResolveSeconds())
+	})
+
+	t.Run("mapped labels", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := labels.DefaultConfig()
+
+		assert.True(t, cfg.IsMapped("alertname"))
+		assert.True(t, cfg.IsMapped("severity"))
+		assert.True(t, cfg.IsMapped("route_key"))
+		assert.False(t, cfg.IsMapped("instance"))
+	})
+}
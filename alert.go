@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -23,6 +24,11 @@ var (
 
 	// SlackMentionRegex matches valid Slack mentions, such as <!here>, <!channel> and <@U12345678>.
 	SlackMentionRegex = regexp.MustCompile(fmt.Sprintf(`^((<!here>)|(<!channel>)|(<@[^>\s]{1,%d}>))$`, MaxMentionLength))
+
+	// Length-agnostic variants of the regexes above, used with the length limits of a ValidationPolicy.
+	slackChannelIDOrNamePattern = regexp.MustCompile(`^[0-9a-zA-Z\-_]+$`)
+	iconPattern                 = regexp.MustCompile(`^:[^:]+:$`)
+	slackMentionPattern         = regexp.MustCompile(`^((<!here>)|(<!channel>)|(<@[^>\s]+>))$`)
 )
 
 const (
@@ -463,9 +469,29 @@ func (a *Alert) UniqueID() string {
 // It trims whitespace, normalizes case where appropriate, truncates certain fields that exceed maximum lengths,
 // and applies default values for empty or invalid fields (e.g., sets Severity to 'error' if empty).
 // This method should be called before validation to ensure consistent data.
+//
+// Clean uses the default limits. Use CleanWithPolicy to clean with custom limits,
+// and CleanWithReport to get a list of the changes that were made.
 func (a *Alert) Clean() {
-	if time.Since(a.Timestamp) > MaxTimestampAge {
+	a.CleanWithPolicy(DefaultValidationPolicy())
+}
+
+// CleanWithPolicy cleans the alert like Clean, but truncates fields according to the limits in the specified policy.
+// The default policy is used if p is nil.
+func (a *Alert) CleanWithPolicy(p *ValidationPolicy) {
+	a.clean(p, nil)
+}
+
+// clean cleans the alert with the specified policy, recording all changes in r (if not nil).
+func (a *Alert) clean(p *ValidationPolicy, r *cleanRecorder) {
+	if p == nil {
+		p = DefaultValidationPolicy()
+	}
+
+	if time.Since(a.Timestamp) > p.MaxTimestampAge {
+		oldTimestamp := a.Timestamp
 		a.Timestamp = time.Now()
+		r.valueChanged("/timestamp", CleanChangeTimestampReplaced, oldTimestamp.Format(time.RFC3339Nano), a.Timestamp.Format(time.RFC3339Nano))
 	}
 
 	a.CorrelationID = strings.TrimSpace(a.CorrelationID)
@@ -477,68 +503,80 @@ func (a *Alert) Clean() {
 	a.RouteKey = strings.ToLower(strings.TrimSpace(a.RouteKey))
 
 	a.Header = strings.ReplaceAll(strings.TrimSpace(a.Header), "\n", " ")
-	a.Header = truncateStringIfNeeded(a.Header, MaxHeaderLength)
+	a.Header = r.truncate("/header", a.Header, p.MaxHeaderLength)
 
 	a.HeaderWhenResolved = strings.ReplaceAll(strings.TrimSpace(a.HeaderWhenResolved), "\n", " ")
-	a.HeaderWhenResolved = truncateStringIfNeeded(a.HeaderWhenResolved, MaxHeaderLength)
+	a.HeaderWhenResolved = r.truncate("/headerWhenResolved", a.HeaderWhenResolved, p.MaxHeaderLength)
 
 	a.Text = strings.TrimSpace(a.Text)
-	a.Text = shortenAlertTextIfNeeded(a.Text)
+	a.Text = r.shortenText("/text", a.Text, p.MaxTextLength)
 
 	a.TextWhenResolved = strings.TrimSpace(a.TextWhenResolved)
-	a.TextWhenResolved = shortenAlertTextIfNeeded(a.TextWhenResolved)
+	a.TextWhenResolved = r.shortenText("/textWhenResolved", a.TextWhenResolved, p.MaxTextLength)
 
 	a.FallbackText = strings.TrimSpace(strings.ReplaceAll(a.FallbackText, ":status:", ""))
 	a.FallbackText = strings.ReplaceAll(a.FallbackText, "\n", " ")
-	a.FallbackText = truncateStringIfNeeded(a.FallbackText, MaxFallbackTextLength)
+	a.FallbackText = r.truncate("/fallbackText", a.FallbackText, p.MaxFallbackTextLength)
 
 	a.Username = strings.TrimSpace(a.Username)
-	a.Username = truncateStringIfNeeded(a.Username, MaxUsernameLength)
+	a.Username = r.truncate("/username", a.Username, p.MaxUsernameLength)
 
 	a.Author = strings.TrimSpace(a.Author)
-	a.Author = truncateStringIfNeeded(a.Author, MaxAuthorLength)
+	a.Author = r.truncate("/author", a.Author, p.MaxAuthorLength)
 
 	a.Host = strings.TrimSpace(a.Host)
-	a.Host = truncateStringIfNeeded(a.Host, MaxHostLength)
+	a.Host = r.truncate("/host", a.Host, p.MaxHostLength)
 
 	a.Link = strings.TrimSpace(a.Link)
 
 	a.Footer = strings.TrimSpace(a.Footer)
-	a.Footer = truncateStringIfNeeded(a.Footer, MaxFooterLength)
+	a.Footer = r.truncate("/footer", a.Footer, p.MaxFooterLength)
 
 	a.IconEmoji = strings.ToLower(strings.TrimSpace(a.IconEmoji))
 
 	a.Severity = AlertSeverity(strings.ToLower(strings.TrimSpace(string(a.Severity))))
 
-	if a.Severity == "" || a.Severity == "critical" {
+	if a.Severity == "" {
 		a.Severity = AlertError
+		r.valueChanged("/severity", CleanChangeDefaultApplied, "", string(a.Severity))
+	}
+
+	if a.Severity == "critical" {
+		a.Severity = AlertError
+		r.valueChanged("/severity", CleanChangeNormalized, "critical", string(a.Severity))
 	}
 
 	if a.Severity == "resolve" || a.Severity == "recovered" || a.Severity == "recover" {
+		oldSeverity := a.Severity
 		a.Severity = AlertResolved
+		r.valueChanged("/severity", CleanChangeNormalized, string(oldSeverity), string(a.Severity))
 	}
 
 	if a.ArchivingDelaySeconds < 0 {
+		r.valueChanged("/archivingDelaySeconds", CleanChangeDefaultApplied, strconv.Itoa(a.ArchivingDelaySeconds), "0")
 		a.ArchivingDelaySeconds = 0
 	}
 
 	if a.NotificationDelaySeconds < 0 {
+		r.valueChanged("/notificationDelaySeconds", CleanChangeDefaultApplied, strconv.Itoa(a.NotificationDelaySeconds), "0")
 		a.NotificationDelaySeconds = 0
 	}
 
-	for _, field := range a.Fields {
+	for index, field := range a.Fields {
 		if field == nil {
 			continue
 		}
 
+		path := fmt.Sprintf("/fields/%d", index)
+
 		field.Title = strings.TrimSpace(field.Title)
-		field.Title = truncateStringIfNeeded(field.Title, MaxFieldTitleLength)
+		field.Title = r.truncate(path+"/title", field.Title, p.MaxFieldTitleLength)
 
 		field.Value = strings.TrimSpace(field.Value)
-		field.Value = truncateStringIfNeeded(field.Value, MaxFieldValueLength)
+		field.Value = r.truncate(path+"/value", field.Value, p.MaxFieldValueLength)
 	}
 
-	for _, hook := range a.Webhooks {
+	for index, hook := range a.Webhooks {
 		if hook == nil {
 			continue
 		}
@@ -550,6 +588,7 @@ func (a *Alert) Clean() {
 
 		if hook.ButtonStyle == "default" {
 			hook.ButtonStyle = ""
+			r.valueChanged(fmt.Sprintf("/webhooks/%d/buttonStyle", index), CleanChangeNormalized, "default", "")
 		}
 
 		for _, input := range hook.PlainTextInput {
@@ -573,6 +612,8 @@ func (a *Alert) Clean() {
 	}
 
 	if len(a.Escalation) > 0 {
+		oldDelays := escalationDelays(a.Escalation)
+
 		sort.Slice(a.Escalation, func(i, j int) bool {
 			if a.Escalation[i] == nil {
 				return true
@@ -583,6 +624,8 @@ func (a *Alert) Clean() {
 			return a.Escalation[i].DelaySeconds < a.Escalation[j].DelaySeconds
 		})
 
+		r.valueChanged("/escalation", CleanChangeReordered, oldDelays, escalationDelays(a.Escalation))
+
 		for _, e := range a.Escalation {
 			if e == nil {
 				continue
@@ -598,461 +641,499 @@ func (a *Alert) Clean() {
 	}
 }
 
-// Validate returns an error if one or more of the required fields are empty or invalid
+// Validate returns an error if one or more of the required fields are empty or invalid.
+// Validation stops at the first failing check. Use ValidateAll to collect all validation errors in a single pass.
+//
+// Validate uses the default limits. Use ValidateWithPolicy to validate with custom limits.
+// The returned error (if any) is a *ValidationError, except when the alert itself is nil.
 func (a *Alert) Validate() error {
+	return a.ValidateWithPolicy(DefaultValidationPolicy())
+}
+
+// ValidateWithPolicy validates the alert like Validate, but with the limits in the specified policy.
+// The default policy is used if p is nil. An error is returned if the policy itself is invalid.
+func (a *Alert) ValidateWithPolicy(p *ValidationPolicy) error {
 	if a == nil {
 		return errors.New("alert is nil")
 	}
 
-	if err := a.ValidateSlackChannelIDAndRouteKey(); err != nil {
-		return err
-	}
-
-	if err := a.ValidateHeaderAndText(); err != nil {
-		return err
+	if p == nil {
+		p = DefaultValidationPolicy()
 	}
 
-	if err := a.ValidateIcon(); err != nil {
-		return err
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid validation policy: %w", err)
 	}
 
-	if err := a.ValidateLink(); err != nil {
-		return err
-	}
+	return a.validate(p).first()
+}
 
-	if err := a.ValidateSeverity(); err != nil {
-		return err
-	}
+// ValidateAll validates the alert like Validate, but collects all validation errors in a single pass.
+// The returned error (if any) is of type ValidationErrors.
+func (a *Alert) ValidateAll() error {
+	return a.ValidateAllWithPolicy(DefaultValidationPolicy())
+}
 
-	if err := a.ValidateCorrelationID(); err != nil {
-		return err
+// ValidateAllWithPolicy validates the alert like ValidateAll, but with the limits in the specified policy.
+// The default policy is used if p is nil. An error is returned if the policy itself is invalid.
+func (a *Alert) ValidateAllWithPolicy(p *ValidationPolicy) error {
+	if a == nil {
+		return ValidationErrors{{Code: ValidationErrorRequired, Message: "alert is nil"}}
 	}
 
-	if err := a.ValidateAutoResolve(); err != nil {
-		return err
+	if p == nil {
+		p = DefaultValidationPolicy()
 	}
 
-	if err := a.ValidateFields(); err != nil {
-		return err
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid validation policy: %w", err)
 	}
 
-	if err := a.ValidateWebhooks(); err != nil {
-		return err
+	if errs := a.validate(p); len(errs) > 0 {
+		return errs
 	}
 
-	if err := a.ValidateEscalation(); err != nil {
-		return err
-	}
+	return nil
+}
 
-	return a.ValidateIgnoreIfTextContains()
+func (a *Alert) validate(p *ValidationPolicy) ValidationErrors {
+	var errs ValidationErrors
+
+	a.validateSlackChannelIDAndRouteKey(p, &errs)
+	a.validateHeaderAndText(&errs)
+	a.validateIcon(p, &errs)
+	a.validateLink(&errs)
+	a.validateSeverity(&errs)
+	a.validateCorrelationID(p, &errs)
+	a.validateAutoResolve(p, &errs)
+	a.validateFields(p, &errs)
+	a.validateWebhooks(p, &errs)
+	a.validateEscalation(p, &errs)
+	a.validateIgnoreIfTextContains(p, &errs)
+
+	return errs
 }
 
 // ValidateSlackChannelIDAndRouteKey validates that SlackChannelID and RouteKey are valid, if set.
 // Both values are allowed to be empty (in which case a fallback mapping must exist in the API).
 func (a *Alert) ValidateSlackChannelIDAndRouteKey() error {
+	var errs ValidationErrors
+	a.validateSlackChannelIDAndRouteKey(DefaultValidationPolicy(), &errs)
+	return errs.first()
+}
+
+func (a *Alert) validateSlackChannelIDAndRouteKey(p *ValidationPolicy, errs *ValidationErrors) {
 	if a.SlackChannelID != "" {
-		if !SlackChannelIDOrNameRegex.MatchString(a.SlackChannelID) {
-			return fmt.Errorf("slackChannelId '%s' is not valid", a.SlackChannelID)
+		if !p.slackChannelIDOrNameIsValid(a.SlackChannelID) {
+			errs.addf("/slackChannelId", ValidationErrorInvalidFormat, "slackChannelId '%s' is not valid", a.SlackChannelID)
 		}
 
-		return nil
+		return
 	}
 
-	if len(a.RouteKey) > MaxRouteKeyLength {
-		return fmt.Errorf("routeKey is too long, expected length <=%d", MaxRouteKeyLength)
+	if len(a.RouteKey) > p.MaxRouteKeyLength {
+		errs.addLimitf("/routeKey", ValidationErrorTooLong, p.MaxRouteKeyLength, "routeKey is too long, expected length <=%d", p.MaxRouteKeyLength)
 	}
-
-	return nil
 }
 
 // ValidateHeaderAndText validates that at least one of Header or Text is non-empty.
 // An alert must have either a header or text content to be meaningful.
 func (a *Alert) ValidateHeaderAndText() error {
+	var errs ValidationErrors
+	a.validateHeaderAndText(&errs)
+	return errs.first()
+}
+
+func (a *Alert) validateHeaderAndText(errs *ValidationErrors) {
 	if a.Header == "" && a.Text == "" {
-		return errors.New("header and text cannot both be empty")
+		errs.addf("/header", ValidationErrorRequired, "header and text cannot both be empty")
 	}
-
-	return nil
 }
 
 // ValidateIcon validates that IconEmoji, if set, matches the expected Slack emoji format ':emoji:'.
 func (a *Alert) ValidateIcon() error {
+	var errs ValidationErrors
+	a.validateIcon(DefaultValidationPolicy(), &errs)
+	return errs.first()
+}
+
+func (a *Alert) validateIcon(p *ValidationPolicy, errs *ValidationErrors) {
 	if a.IconEmoji == "" {
-		return nil
+		return
 	}
 
-	if !IconRegex.MatchString(a.IconEmoji) {
-		return fmt.Errorf("iconEmoji '%s' is not valid", a.IconEmoji)
+	if !p.iconEmojiIsValid(a.IconEmoji) {
+		errs.addf("/iconEmoji", ValidationErrorInvalidFormat, "iconEmoji '%s' is not valid", a.IconEmoji)
 	}
-
-	return nil
 }
 
 // ValidateLink validates that Link, if set, is a valid absolute URL with a scheme.
 func (a *Alert) ValidateLink() error {
+	var errs ValidationErrors
+	a.validateLink(&errs)
+	return errs.first()
+}
+
+func (a *Alert) validateLink(errs *ValidationErrors) {
 	if a.Link == "" {
-		return nil
+		return
 	}
 
 	url, err := url.ParseRequestURI(a.Link)
-	if err != nil {
-		return errors.New("link is not a valid absolute URL")
-	}
-
-	if url.Scheme == "" {
-		return errors.New("link is not a valid absolute URL")
+	if err != nil || url.Scheme == "" {
+		errs.addf("/link", ValidationErrorInvalidFormat, "link is not a valid absolute URL")
 	}
-
-	return nil
 }
 
 // ValidateSeverity validates that Severity is one of the allowed AlertSeverity values.
 func (a *Alert) ValidateSeverity() error {
+	var errs ValidationErrors
+	a.validateSeverity(&errs)
+	return errs.first()
+}
+
+func (a *Alert) validateSeverity(errs *ValidationErrors) {
 	if !SeverityIsValid(a.Severity) {
-		return fmt.Errorf("severity '%s' is not valid, expected one of [%s]", a.Severity, strings.Join(ValidSeverities(), ", "))
+		errs.addf("/severity", ValidationErrorInvalidValue, "severity '%s' is not valid, expected one of [%s]", a.Severity, strings.Join(ValidSeverities(), ", "))
 	}
-
-	return nil
 }
 
 // ValidateCorrelationID validates that CorrelationID, if set, does not exceed MaxCorrelationIDLength.
 func (a *Alert) ValidateCorrelationID() error {
-	if a.CorrelationID == "" {
-		return nil
-	}
+	var errs ValidationErrors
+	a.validateCorrelationID(DefaultValidationPolicy(), &errs)
+	return errs.first()
+}
 
-	if len(a.CorrelationID) > MaxCorrelationIDLength {
-		return fmt.Errorf("correlationId is too long, expected length <=%d", MaxCorrelationIDLength)
+func (a *Alert) validateCorrelationID(p *ValidationPolicy, errs *ValidationErrors) {
+	if len(a.CorrelationID) > p.MaxCorrelationIDLength {
+		errs.addLimitf("/correlationId", ValidationErrorTooLong, p.MaxCorrelationIDLength, "correlationId is too long, expected length <=%d", p.MaxCorrelationIDLength)
 	}
-
-	return nil
 }
 
 // ValidateAutoResolve validates that AutoResolveSeconds is within the allowed range
 // when IssueFollowUpEnabled is true.
 func (a *Alert) ValidateAutoResolve() error {
+	var errs ValidationErrors
+	a.validateAutoResolve(DefaultValidationPolicy(), &errs)
+	return errs.first()
+}
+
+func (a *Alert) validateAutoResolve(p *ValidationPolicy, errs *ValidationErrors) {
 	if !a.IssueFollowUpEnabled {
-		return nil
+		return
 	}
 
-	if a.AutoResolveSeconds < MinAutoResolveSeconds {
-		return fmt.Errorf("autoResolveSeconds %d is too low, expected value >=%d", a.AutoResolveSeconds, MinAutoResolveSeconds)
+	if a.AutoResolveSeconds < p.MinAutoResolveSeconds {
+		errs.addLimitf("/autoResolveSeconds", ValidationErrorOutOfRange, p.MinAutoResolveSeconds, "autoResolveSeconds %d is too low, expected value >=%d", a.AutoResolveSeconds, p.MinAutoResolveSeconds)
 	}
 
-	if a.AutoResolveSeconds > MaxAutoResolveSeconds {
-		return fmt.Errorf("autoResolveSeconds %d is too high, expected value <=%d", a.AutoResolveSeconds, MaxAutoResolveSeconds)
+	if a.AutoResolveSeconds > p.MaxAutoResolveSeconds {
+		errs.addLimitf("/autoResolveSeconds", ValidationErrorOutOfRange, p.MaxAutoResolveSeconds, "autoResolveSeconds %d is too high, expected value <=%d", a.AutoResolveSeconds, p.MaxAutoResolveSeconds)
 	}
-
-	return nil
 }
 
 // ValidateIgnoreIfTextContains validates that the IgnoreIfTextContains slice
 // does not exceed the maximum count and that each item does not exceed the maximum length.
 func (a *Alert) ValidateIgnoreIfTextContains() error {
-	if len(a.IgnoreIfTextContains) == 0 {
-		return nil
-	}
+	var errs ValidationErrors
+	a.validateIgnoreIfTextContains(DefaultValidationPolicy(), &errs)
+	return errs.first()
+}
 
-	if len(a.IgnoreIfTextContains) > MaxIgnoreIfTextContainsCount {
-		return fmt.Errorf("too many ignoreIfTextContains items, expected <=%d", MaxIgnoreIfTextContainsCount)
+func (a *Alert) validateIgnoreIfTextContains(p *ValidationPolicy, errs *ValidationErrors) {
+	if len(a.IgnoreIfTextContains) > p.MaxIgnoreIfTextContainsCount {
+		errs.addLimitf("/ignoreIfTextContains", ValidationErrorTooMany, p.MaxIgnoreIfTextContainsCount, "too many ignoreIfTextContains items, expected <=%d", p.MaxIgnoreIfTextContainsCount)
 	}
 
 	for index, s := range a.IgnoreIfTextContains {
-		if len(s) > MaxIgnoreIfTextContainsLength {
-			return fmt.Errorf("ignoreIfTextContains[%d] is too long, expected length <=%d", index, MaxIgnoreIfTextContainsLength)
+		if len(s) > p.MaxIgnoreIfTextContainsLength {
+			errs.addLimitf(fmt.Sprintf("/ignoreIfTextContains/%d", index), ValidationErrorTooLong, p.MaxIgnoreIfTextContainsLength, "ignoreIfTextContains[%d] is too long, expected length <=%d", index, p.MaxIgnoreIfTextContainsLength)
 		}
 	}
-
-	return nil
 }
 
 // ValidateFields validates that the number of fields does not exceed MaxFieldCount.
 func (a *Alert) ValidateFields() error {
-	if len(a.Fields) > MaxFieldCount {
-		return fmt.Errorf("too many fields, expected <=%d", MaxFieldCount)
-	}
+	var errs ValidationErrors
+	a.validateFields(DefaultValidationPolicy(), &errs)
+	return errs.first()
+}
 
-	return nil
+func (a *Alert) validateFields(p *ValidationPolicy, errs *ValidationErrors) {
+	if len(a.Fields) > p.MaxFieldCount {
+		errs.addLimitf("/fields", ValidationErrorTooMany, p.MaxFieldCount, "too many fields, expected <=%d", p.MaxFieldCount)
+	}
 }
 
 // ValidateWebhooks validates all webhooks in the alert.
 // It checks that the webhook count is within limits, all required fields are present,
 // URLs are valid, IDs are unique, and all nested inputs are properly configured.
 func (a *Alert) ValidateWebhooks() error {
-	if a.Webhooks == nil {
-		return nil
-	}
+	var errs ValidationErrors
+	a.validateWebhooks(DefaultValidationPolicy(), &errs)
+	return errs.first()
+}
 
-	if len(a.Webhooks) > MaxWebhookCount {
-		return fmt.Errorf("too many webhooks, expected <=%d", MaxWebhookCount)
+func (a *Alert) validateWebhooks(p *ValidationPolicy, errs *ValidationErrors) {
+	if len(a.Webhooks) > p.MaxWebhookCount {
+		errs.addLimitf("/webhooks", ValidationErrorTooMany, p.MaxWebhookCount, "too many webhooks, expected <=%d", p.MaxWebhookCount)
 	}
 
 	webhookIDs := make(map[string]struct{})
 
 	for index, hook := range a.Webhooks {
+		path := fmt.Sprintf("/webhooks/%d", index)
+
 		if hook == nil {
-			return fmt.Errorf("webhook[%d] is nil", index)
+			errs.addf(path, ValidationErrorRequired, "webhook[%d] is nil", index)
+			continue
 		}
 
 		if hook.ID == "" {
-			return fmt.Errorf("webhook[%d].id is required", index)
-		}
-
-		if len(hook.ID) > MaxWebhookIDLength {
-			return fmt.Errorf("webhook[%d].id is too long, expected length <=%d", index, MaxWebhookIDLength)
-		}
-
-		if _, ok := webhookIDs[hook.ID]; ok {
-			return fmt.Errorf("webhook[%d].id must be unique", index)
+			errs.addf(path+"/id", ValidationErrorRequired, "webhook[%d].id is required", index)
+		} else if len(hook.ID) > p.MaxWebhookIDLength {
+			errs.addLimitf(path+"/id", ValidationErrorTooLong, p.MaxWebhookIDLength, "webhook[%d].id is too long, expected length <=%d", index, p.MaxWebhookIDLength)
+		} else if _, ok := webhookIDs[hook.ID]; ok {
+			errs.addf(path+"/id", ValidationErrorNotUnique, "webhook[%d].id must be unique", index)
 		}
 
 		webhookIDs[hook.ID] = struct{}{}
 
 		if hook.URL == "" {
-			return fmt.Errorf("webhook[%d].url is required", index)
-		}
-
-		if len(hook.URL) > MaxWebhookURLLength {
-			return fmt.Errorf("webhook[%d].url is too long, expected length <=%d", index, MaxWebhookURLLength)
-		}
-
-		// For HTTP URLs, validate as absolute URL. For custom handler identifiers, validate as ASCII.
-		if strings.HasPrefix(strings.ToLower(hook.URL), "http") {
+			errs.addf(path+"/url", ValidationErrorRequired, "webhook[%d].url is required", index)
+		} else if len(hook.URL) > p.MaxWebhookURLLength {
+			errs.addLimitf(path+"/url", ValidationErrorTooLong, p.MaxWebhookURLLength, "webhook[%d].url is too long, expected length <=%d", index, p.MaxWebhookURLLength)
+		} else if strings.HasPrefix(strings.ToLower(hook.URL), "http") {
+			// For HTTP URLs, validate as absolute URL. For custom handler identifiers, validate as ASCII.
 			parsedURL, err := url.ParseRequestURI(hook.URL)
-			if err != nil {
-				return fmt.Errorf("webhook[%d].url is not a valid absolute URL", index)
-			}
-
-			if parsedURL.Scheme == "" || parsedURL.Host == "" {
-				return fmt.Errorf("webhook[%d].url is not a valid absolute URL", index)
+			if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+				errs.addf(path+"/url", ValidationErrorInvalidFormat, "webhook[%d].url is not a valid absolute URL", index)
 			}
 		} else if !isValidASCII(hook.URL) {
-			return fmt.Errorf("webhook[%d].url contains invalid characters, expected printable ASCII", index)
+			errs.addf(path+"/url", ValidationErrorInvalidFormat, "webhook[%d].url contains invalid characters, expected printable ASCII", index)
 		}
 
 		if hook.ButtonText == "" {
-			return fmt.Errorf("webhook[%d].buttonText is required", index)
-		}
-
-		if len(hook.ButtonText) > MaxWebhookButtonTextLength {
-			return fmt.Errorf("webhook[%d].buttonText is too long, expected length <=%d", index, MaxWebhookButtonTextLength)
+			errs.addf(path+"/buttonText", ValidationErrorRequired, "webhook[%d].buttonText is required", index)
+		} else if len(hook.ButtonText) > p.MaxWebhookButtonTextLength {
+			errs.addLimitf(path+"/buttonText", ValidationErrorTooLong, p.MaxWebhookButtonTextLength, "webhook[%d].buttonText is too long, expected length <=%d", index, p.MaxWebhookButtonTextLength)
 		}
 
-		if len(hook.ConfirmationText) > MaxWebhookConfirmationTextLength {
-			return fmt.Errorf("webhook[%d].confirmationText is too long, expected length <=%d", index, MaxWebhookConfirmationTextLength)
+		if len(hook.ConfirmationText) > p.MaxWebhookConfirmationTextLength {
+			errs.addLimitf(path+"/confirmationText", ValidationErrorTooLong, p.MaxWebhookConfirmationTextLength, "webhook[%d].confirmationText is too long, expected length <=%d", index, p.MaxWebhookConfirmationTextLength)
 		}
 
 		if hook.ButtonStyle != "" && !WebhookButtonStyleIsValid(hook.ButtonStyle) {
-			return fmt.Errorf("webhook[%d].buttonStyle '%s' is not valid, expected empty or one of [%s]", index, hook.ButtonStyle, strings.Join(ValidWebhookButtonStyles(), ", "))
+			errs.addf(path+"/buttonStyle", ValidationErrorInvalidValue, "webhook[%d].buttonStyle '%s' is not valid, expected empty or one of [%s]", index, hook.ButtonStyle, strings.Join(ValidWebhookButtonStyles(), ", "))
 		}
 
 		if hook.AccessLevel != "" && !WebhookAccessLevelIsValid(hook.AccessLevel) {
-			return fmt.Errorf("webhook[%d].accessLevel '%s' is not valid, expected empty or one of [%s]", index, hook.AccessLevel, strings.Join(ValidWebhookAccessLevels(), ", "))
+			errs.addf(path+"/accessLevel", ValidationErrorInvalidValue, "webhook[%d].accessLevel '%s' is not valid, expected empty or one of [%s]", index, hook.AccessLevel, strings.Join(ValidWebhookAccessLevels(), ", "))
 		}
 
 		if hook.DisplayMode != "" && !WebhookDisplayModeIsValid(hook.DisplayMode) {
-			return fmt.Errorf("webhook[%d].displayMode '%s' is not valid, expected empty or one of [%s]", index, hook.DisplayMode, strings.Join(ValidWebhookDisplayModes(), ", "))
+			errs.addf(path+"/displayMode", ValidationErrorInvalidValue, "webhook[%d].displayMode '%s' is not valid, expected empty or one of [%s]", index, hook.DisplayMode, strings.Join(ValidWebhookDisplayModes(), ", "))
 		}
 
-		if len(hook.Payload) > MaxWebhookPayloadCount {
-			return fmt.Errorf("webhook[%d].payload item count is too large, expected <=%d", index, MaxWebhookPayloadCount)
+		if len(hook.Payload) > p.MaxWebhookPayloadCount {
+			errs.addLimitf(path+"/payload", ValidationErrorTooMany, p.MaxWebhookPayloadCount, "webhook[%d].payload item count is too large, expected <=%d", index, p.MaxWebhookPayloadCount)
 		}
 
-		if len(hook.PlainTextInput) > MaxWebhookPlainTextInputCount {
-			return fmt.Errorf("webhook[%d].plainTextInput item count is too large, expected <=%d", index, MaxWebhookPlainTextInputCount)
+		if len(hook.PlainTextInput) > p.MaxWebhookPlainTextInputCount {
+			errs.addLimitf(path+"/plainTextInput", ValidationErrorTooMany, p.MaxWebhookPlainTextInputCount, "webhook[%d].plainTextInput item count is too large, expected <=%d", index, p.MaxWebhookPlainTextInputCount)
 		}
 
-		if len(hook.CheckboxInput) > MaxWebhookCheckboxInputCount {
-			return fmt.Errorf("webhook[%d].checkboxInput item count is too large, expected <=%d", index, MaxWebhookCheckboxInputCount)
+		if len(hook.CheckboxInput) > p.MaxWebhookCheckboxInputCount {
+			errs.addLimitf(path+"/checkboxInput", ValidationErrorTooMany, p.MaxWebhookCheckboxInputCount, "webhook[%d].checkboxInput item count is too large, expected <=%d", index, p.MaxWebhookCheckboxInputCount)
 		}
 
 		inputIDs := make(map[string]struct{})
 
 		for inputIndex, input := range hook.PlainTextInput {
+			inputPath := fmt.Sprintf("%s/plainTextInput/%d", path, inputIndex)
+
 			if input == nil {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d] is nil", index, inputIndex)
+				errs.addf(inputPath, ValidationErrorRequired, "webhook[%d].plainTextInput[%d] is nil", index, inputIndex)
+				continue
 			}
 
 			if input.ID == "" {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].id is required", index, inputIndex)
-			}
-
-			if _, ok := inputIDs[input.ID]; ok {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].id must be unique among all inputs", index, inputIndex)
+				errs.addf(inputPath+"/id", ValidationErrorRequired, "webhook[%d].plainTextInput[%d].id is required", index, inputIndex)
+			} else if _, ok := inputIDs[input.ID]; ok {
+				errs.addf(inputPath+"/id", ValidationErrorNotUnique, "webhook[%d].plainTextInput[%d].id must be unique among all inputs", index, inputIndex)
+			} else if len(input.ID) > p.MaxWebhookInputIDLength {
+				errs.addLimitf(inputPath+"/id", ValidationErrorTooLong, p.MaxWebhookInputIDLength, "webhook[%d].plainTextInput[%d].id is too long, expected <=%d", index, inputIndex, p.MaxWebhookInputIDLength)
 			}
 
 			inputIDs[input.ID] = struct{}{}
 
-			if len(input.ID) > MaxWebhookInputIDLength {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].id is too long, expected <=%d", index, inputIndex, MaxWebhookInputIDLength)
-			}
-
-			if len(input.Description) > MaxWebhookInputDescriptionLength {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].description is too long, expected <=%d", index, inputIndex, MaxWebhookInputDescriptionLength)
+			if len(input.Description) > p.MaxWebhookInputDescriptionLength {
+				errs.addLimitf(inputPath+"/description", ValidationErrorTooLong, p.MaxWebhookInputDescriptionLength, "webhook[%d].plainTextInput[%d].description is too long, expected <=%d", index, inputIndex, p.MaxWebhookInputDescriptionLength)
 			}
 
 			if input.MinLength < 0 {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].minLength must be >=0", index, inputIndex)
+				errs.addLimitf(inputPath+"/minLength", ValidationErrorOutOfRange, 0, "webhook[%d].plainTextInput[%d].minLength must be >=0", index, inputIndex)
 			}
 
-			if input.MinLength > MaxWebhookInputTextLength {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].minLength must be <=%d", index, inputIndex, MaxWebhookInputTextLength)
+			if input.MinLength > p.MaxWebhookInputTextLength {
+				errs.addLimitf(inputPath+"/minLength", ValidationErrorOutOfRange, p.MaxWebhookInputTextLength, "webhook[%d].plainTextInput[%d].minLength must be <=%d", index, inputIndex, p.MaxWebhookInputTextLength)
 			}
 
 			if input.MaxLength < 0 {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].maxLength must be >=0", index, inputIndex)
+				errs.addLimitf(inputPath+"/maxLength", ValidationErrorOutOfRange, 0, "webhook[%d].plainTextInput[%d].maxLength must be >=0", index, inputIndex)
 			}
 
-			if input.MaxLength > MaxWebhookInputTextLength {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].maxLength must be <=%d", index, inputIndex, MaxWebhookInputTextLength)
+			if input.MaxLength > p.MaxWebhookInputTextLength {
+				errs.addLimitf(inputPath+"/maxLength", ValidationErrorOutOfRange, p.MaxWebhookInputTextLength, "webhook[%d].plainTextInput[%d].maxLength must be <=%d", index, inputIndex, p.MaxWebhookInputTextLength)
 			}
 
 			if input.MaxLength < input.MinLength {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].maxLength cannot be smaller than minLength", index, inputIndex)
+				errs.addLimitf(inputPath+"/maxLength", ValidationErrorOutOfRange, input.MinLength, "webhook[%d].plainTextInput[%d].maxLength cannot be smaller than minLength", index, inputIndex)
 			}
 
 			if len(input.InitialValue) > input.MaxLength {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].initialValue cannot be longer than maxLength", index, inputIndex)
+				errs.addLimitf(inputPath+"/initialValue", ValidationErrorTooLong, input.MaxLength, "webhook[%d].plainTextInput[%d].initialValue cannot be longer than maxLength", index, inputIndex)
 			}
 
 			if len(input.InitialValue) < input.MinLength {
-				return fmt.Errorf("webhook[%d].plainTextInput[%d].initialValue cannot be shorter than minLength", index, inputIndex)
+				errs.addLimitf(inputPath+"/initialValue", ValidationErrorTooShort, input.MinLength, "webhook[%d].plainTextInput[%d].initialValue cannot be shorter than minLength", index, inputIndex)
 			}
 		}
 
 		for inputIndex, input := range hook.CheckboxInput {
+			inputPath := fmt.Sprintf("%s/checkboxInput/%d", path, inputIndex)
+
 			if input == nil {
-				return fmt.Errorf("webhook[%d].checkboxInput[%d] is nil", index, inputIndex)
+				errs.addf(inputPath, ValidationErrorRequired, "webhook[%d].checkboxInput[%d] is nil", index, inputIndex)
+				continue
 			}
 
 			if input.ID == "" {
-				return fmt.Errorf("webhook[%d].checkboxInput[%d].id is required", index, inputIndex)
-			}
-
-			if _, ok := inputIDs[input.ID]; ok {
-				return fmt.Errorf("webhook[%d].checkboxInput[%d].id must be unique among all inputs", index, inputIndex)
+				errs.addf(inputPath+"/id", ValidationErrorRequired, "webhook[%d].checkboxInput[%d].id is required", index, inputIndex)
+			} else if _, ok := inputIDs[input.ID]; ok {
+				errs.addf(inputPath+"/id", ValidationErrorNotUnique, "webhook[%d].checkboxInput[%d].id must be unique among all inputs", index, inputIndex)
+			} else if len(input.ID) > p.MaxWebhookInputIDLength {
+				errs.addLimitf(inputPath+"/id", ValidationErrorTooLong, p.MaxWebhookInputIDLength, "webhook[%d].checkboxInput[%d].id is too long, expected <=%d", index, inputIndex, p.MaxWebhookInputIDLength)
 			}
 
 			inputIDs[input.ID] = struct{}{}
 
-			if len(input.ID) > MaxWebhookInputIDLength {
-				return fmt.Errorf("webhook[%d].checkboxInput[%d].id is too long, expected <=%d", index, inputIndex, MaxWebhookInputIDLength)
+			if len(input.Label) > p.MaxWebhookInputLabelLength {
+				errs.addLimitf(inputPath+"/label", ValidationErrorTooLong, p.MaxWebhookInputLabelLength, "webhook[%d].checkboxInput[%d].label is too long, expected <=%d", index, inputIndex, p.MaxWebhookInputLabelLength)
 			}
 
-			if len(input.Label) > MaxWebhookInputLabelLength {
-				return fmt.Errorf("webhook[%d].checkboxInput[%d].label is too long, expected <=%d", index, inputIndex, MaxWebhookInputLabelLength)
-			}
-
-			if len(input.Options) > MaxWebhookCheckboxOptionCount {
-				return fmt.Errorf("webhook[%d].checkboxInput[%d].options item count is too large, expected <=%d", index, inputIndex, MaxWebhookCheckboxOptionCount)
+			if len(input.Options) > p.MaxWebhookCheckboxOptionCount {
+				errs.addLimitf(inputPath+"/options", ValidationErrorTooMany, p.MaxWebhookCheckboxOptionCount, "webhook[%d].checkboxInput[%d].options item count is too large, expected <=%d", index, inputIndex, p.MaxWebhookCheckboxOptionCount)
 			}
 
 			values := make(map[string]struct{})
 
 			for optionIndex, option := range input.Options {
+				optionPath := fmt.Sprintf("%s/options/%d", inputPath, optionIndex)
+
 				if option == nil {
-					return fmt.Errorf("webhook[%d].checkboxInput[%d].options[%d] is nil", index, inputIndex, optionIndex)
+					errs.addf(optionPath, ValidationErrorRequired, "webhook[%d].checkboxInput[%d].options[%d] is nil", index, inputIndex, optionIndex)
+					continue
 				}
 
 				if option.Value == "" {
-					return fmt.Errorf("webhook[%d].checkboxInput[%d].options[%d].value is required", index, inputIndex, optionIndex)
-				}
-
-				if len(option.Value) > MaxCheckboxOptionValueLength {
-					return fmt.Errorf("webhook[%d].checkboxInput[%d].options[%d].value is too long, expected <=%d", index, inputIndex, optionIndex, MaxCheckboxOptionValueLength)
-				}
-
-				if _, ok := values[option.Value]; ok {
-					return fmt.Errorf("webhook[%d].checkboxInput[%d].options[%d].value must be unique", index, inputIndex, optionIndex)
+					errs.addf(optionPath+"/value", ValidationErrorRequired, "webhook[%d].checkboxInput[%d].options[%d].value is required", index, inputIndex, optionIndex)
+				} else if len(option.Value) > p.MaxCheckboxOptionValueLength {
+					errs.addLimitf(optionPath+"/value", ValidationErrorTooLong, p.MaxCheckboxOptionValueLength, "webhook[%d].checkboxInput[%d].options[%d].value is too long, expected <=%d", index, inputIndex, optionIndex, p.MaxCheckboxOptionValueLength)
+				} else if _, ok := values[option.Value]; ok {
+					errs.addf(optionPath+"/value", ValidationErrorNotUnique, "webhook[%d].checkboxInput[%d].options[%d].value must be unique", index, inputIndex, optionIndex)
 				}
 
 				values[option.Value] = struct{}{}
 
-				if len(option.Text) > MaxWebhookCheckboxOptionTextLength {
-					return fmt.Errorf("webhook[%d].checkboxInput[%d].options[%d].text is too long, expected <=%d", index, inputIndex, optionIndex, MaxWebhookCheckboxOptionTextLength)
+				if len(option.Text) > p.MaxWebhookCheckboxOptionTextLength {
+					errs.addLimitf(optionPath+"/text", ValidationErrorTooLong, p.MaxWebhookCheckboxOptionTextLength, "webhook[%d].checkboxInput[%d].options[%d].text is too long, expected <=%d", index, inputIndex, optionIndex, p.MaxWebhookCheckboxOptionTextLength)
 				}
 			}
 		}
 	}
-
-	return nil
 }
 
 // ValidateEscalation validates all escalation points in the alert.
 // It checks that the escalation count is within limits, delays are properly spaced,
 // severities are valid for escalation, and Slack mentions and channels are valid.
 func (a *Alert) ValidateEscalation() error {
-	if a.Escalation == nil {
-		return nil
-	}
+	var errs ValidationErrors
+	a.validateEscalation(DefaultValidationPolicy(), &errs)
+	return errs.first()
+}
 
-	if len(a.Escalation) > MaxEscalationCount {
-		return fmt.Errorf("too many escalation points, expected <=%d", MaxEscalationCount)
+func (a *Alert) validateEscalation(p *ValidationPolicy, errs *ValidationErrors) {
+	if len(a.Escalation) > p.MaxEscalationCount {
+		errs.addLimitf("/escalation", ValidationErrorTooMany, p.MaxEscalationCount, "too many escalation points, expected <=%d", p.MaxEscalationCount)
 	}
 
 	previousDelay := 0
 
 	for index, e := range a.Escalation {
-		if e == nil {
-			return fmt.Errorf("escalation[%d] is nil", index)
-		}
+		path := fmt.Sprintf("/escalation/%d", index)
 
-		if e.DelaySeconds < MinEscalationDelaySeconds {
-			return fmt.Errorf("escalation[%d].delaySeconds '%d' is too low, expected value >=%d", index, e.DelaySeconds, MinEscalationDelaySeconds)
+		if e == nil {
+			errs.addf(path, ValidationErrorRequired, "escalation[%d] is nil", index)
+			continue
 		}
 
-		if previousDelay > 0 && e.DelaySeconds-previousDelay < MinEscalationDelayDiffSeconds {
-			return fmt.Errorf("escalation[%d].delaySeconds '%d' is too small compared to previous escalation, expected diff >=%d", index, e.DelaySeconds, MinEscalationDelayDiffSeconds)
+		if e.DelaySeconds < p.MinEscalationDelaySeconds {
+			errs.addLimitf(path+"/delaySeconds", ValidationErrorOutOfRange, p.MinEscalationDelaySeconds, "escalation[%d].delaySeconds '%d' is too low, expected value >=%d", index, e.DelaySeconds, p.MinEscalationDelaySeconds)
+		} else if previousDelay > 0 && e.DelaySeconds-previousDelay < p.MinEscalationDelayDiffSeconds {
+			errs.addLimitf(path+"/delaySeconds", ValidationErrorOutOfRange, p.MinEscalationDelayDiffSeconds, "escalation[%d].delaySeconds '%d' is too small compared to previous escalation, expected diff >=%d", index, e.DelaySeconds, p.MinEscalationDelayDiffSeconds)
 		}
 
 		previousDelay = e.DelaySeconds
 
 		if e.Severity != AlertPanic && e.Severity != AlertError && e.Severity != AlertWarning {
-			return fmt.Errorf("escalation[%d].severity '%s' is not valid, expected one of [panic, error, warning]", index, e.Severity)
+			errs.addf(path+"/severity", ValidationErrorInvalidValue, "escalation[%d].severity '%s' is not valid, expected one of [panic, error, warning]", index, e.Severity)
 		}
 
-		if len(e.SlackMentions) > MaxEscalationSlackMentionCount {
-			return fmt.Errorf("escalation[%d].slackMentions item count is too large, expected <=%d", index, MaxEscalationSlackMentionCount)
+		if len(e.SlackMentions) > p.MaxEscalationSlackMentionCount {
+			errs.addLimitf(path+"/slackMentions", ValidationErrorTooMany, p.MaxEscalationSlackMentionCount, "escalation[%d].slackMentions item count is too large, expected <=%d", index, p.MaxEscalationSlackMentionCount)
 		}
 
 		for j, mention := range e.SlackMentions {
-			if !SlackMentionRegex.MatchString(mention) {
-				return fmt.Errorf("escalation[%d].slackMentions[%d] is not valid", index, j)
+			if !p.slackMentionIsValid(mention) {
+				errs.addf(fmt.Sprintf("%s/slackMentions/%d", path, j), ValidationErrorInvalidFormat, "escalation[%d].slackMentions[%d] is not valid", index, j)
 			}
 		}
 
-		if e.MoveToChannel != "" {
-			if !SlackChannelIDOrNameRegex.MatchString(e.MoveToChannel) {
-				return fmt.Errorf("escalation[%d].moveToChannel is not valid", index)
-			}
+		if e.MoveToChannel != "" && !p.slackChannelIDOrNameIsValid(e.MoveToChannel) {
+			errs.addf(path+"/moveToChannel", ValidationErrorInvalidFormat, "escalation[%d].moveToChannel is not valid", index)
 		}
 	}
-
-	return nil
 }
 
-func shortenAlertTextIfNeeded(text string) string {
-	if utf8.RuneCountInString(text) <= MaxTextLength {
+func shortenAlertTextIfNeeded(text string, maxLen int) string {
+	if utf8.RuneCountInString(text) <= maxLen {
 		return text
 	}
 
 	endsWithCodeBlock := strings.HasSuffix(text, "```")
 
-	if endsWithCodeBlock {
-		return strings.TrimSpace(truncateString(text, MaxTextLength-6)) + "...```"
+	if endsWithCodeBlock && maxLen > 6 {
+		return strings.TrimSpace(truncateString(text, maxLen-6)) + "...```"
 	}
 
-	return strings.TrimSpace(truncateString(text, MaxTextLength-3)) + "..."
+	return truncateStringIfNeeded(text, maxLen)
 }
 
 // truncateStringIfNeeded truncates s to maxLen runes if it exceeds that limit, appending "...".
 // Trailing whitespace is trimmed from the truncated portion before appending.
 // If s is within maxLen runes, it is returned unchanged.
+// If maxLen is too small to fit the "..." suffix, s is truncated to maxLen runes without a suffix.
 func truncateStringIfNeeded(s string, maxLen int) string {
 	if utf8.RuneCountInString(s) <= maxLen {
 		return s
 	}
 
+	if maxLen <= 3 {
+		return truncateString(s, max(maxLen, 0))
+	}
+
 	return strings.TrimSpace(truncateString(s, maxLen-3)) + "..."
 }
 
@@ -0,0 +1,284 @@
+package types
+
+import "time"
+
+// AlertBuilder builds an Alert using chained method calls.
+//
+// Example:
+//
+//	alert, err := types.NewAlertBuilder(types.AlertError).
+//		Channel("C12345678").
+//		Header(":status: Disk usage above 90%").
+//		Field("Host", "db-1").
+//		AutoResolve(30 * time.Minute).
+//		EscalateAfter(time.Hour, types.AlertPanic, "<!here>").
+//		Webhook("restart", "https://example.com/restart", "Restart", types.WithConfirmationText("Are you sure?")).
+//		Build()
+//
+// Durations are converted to whole seconds (any fractional second is truncated).
+// An AlertBuilder should not be reused after Build has been called.
+type AlertBuilder struct {
+	alert *Alert
+}
+
+// WebhookOption configures a webhook added with AlertBuilder.Webhook.
+type WebhookOption func(w *Webhook)
+
+// NewAlertBuilder returns a new AlertBuilder for an alert with the specified severity.
+func NewAlertBuilder(severity AlertSeverity) *AlertBuilder {
+	return &AlertBuilder{alert: NewAlert(severity)}
+}
+
+// Timestamp sets the time when the alert was created. The default is the time the builder was created.
+func (b *AlertBuilder) Timestamp(t time.Time) *AlertBuilder {
+	b.alert.Timestamp = t
+	return b
+}
+
+// CorrelationID sets the correlation ID, used to group related alerts together in issues.
+func (b *AlertBuilder) CorrelationID(id string) *AlertBuilder {
+	b.alert.CorrelationID = id
+	return b
+}
+
+// Type sets the alert type, such as 'compliance' or 'security'.
+func (b *AlertBuilder) Type(t string) *AlertBuilder {
+	b.alert.Type = t
+	return b
+}
+
+// Channel sets the ID or name of the Slack channel where the alert should be posted.
+func (b *AlertBuilder) Channel(channel string) *AlertBuilder {
+	b.alert.SlackChannelID = channel
+	return b
+}
+
+// Route sets the route key, used for routing the alert to a Slack channel.
+func (b *AlertBuilder) Route(routeKey string) *AlertBuilder {
+	b.alert.RouteKey = routeKey
+	return b
+}
+
+// Header sets the main header (title) of the alert.
+func (b *AlertBuilder) Header(header string) *AlertBuilder {
+	b.alert.Header = header
+	return b
+}
+
+// HeaderWhenResolved sets the header used when the issue is resolved.
+func (b *AlertBuilder) HeaderWhenResolved(header string) *AlertBuilder {
+	b.alert.HeaderWhenResolved = header
+	return b
+}
+
+// Text sets the main text (body) of the alert.
+func (b *AlertBuilder) Text(text string) *AlertBuilder {
+	b.alert.Text = text
+	return b
+}
+
+// TextWhenResolved sets the text used when the issue is resolved.
+func (b *AlertBuilder) TextWhenResolved(text string) *AlertBuilder {
+	b.alert.TextWhenResolved = text
+	return b
+}
+
+// FallbackText sets the text displayed in Slack notifications.
+func (b *AlertBuilder) FallbackText(text string) *AlertBuilder {
+	b.alert.FallbackText = text
+	return b
+}
+
+// Author sets the author of the alert.
+func (b *AlertBuilder) Author(author string) *AlertBuilder {
+	b.alert.Author = author
+	return b
+}
+
+// Host sets the host on which the alert originated.
+func (b *AlertBuilder) Host(host string) *AlertBuilder {
+	b.alert.Host = host
+	return b
+}
+
+// Footer sets the footer of the alert.
+func (b *AlertBuilder) Footer(footer string) *AlertBuilder {
+	b.alert.Footer = footer
+	return b
+}
+
+// Link sets the link to more information about the alert.
+func (b *AlertBuilder) Link(link string) *AlertBuilder {
+	b.alert.Link = link
+	return b
+}
+
+// Username sets the username that the alert should be posted as in Slack.
+func (b *AlertBuilder) Username(username string) *AlertBuilder {
+	b.alert.Username = username
+	return b
+}
+
+// IconEmoji sets the emoji that the alert should be posted with in Slack, on the format ':emoji:'.
+func (b *AlertBuilder) IconEmoji(emoji string) *AlertBuilder {
+	b.alert.IconEmoji = emoji
+	return b
+}
+
+// Field adds a field with the specified title and value.
+func (b *AlertBuilder) Field(title, value string) *AlertBuilder {
+	b.alert.Fields = append(b.alert.Fields, &Field{Title: title, Value: value})
+	return b
+}
+
+// AutoResolve enables issue follow-up, and sets the duration after which the issue is automatically resolved.
+func (b *AlertBuilder) AutoResolve(d time.Duration) *AlertBuilder {
+	b.alert.IssueFollowUpEnabled = true
+	b.alert.AutoResolveSeconds = durationSeconds(d)
+	return b
+}
+
+// AutoResolveAsInconclusive makes the issue resolve as 'inconclusive' instead of 'resolved' when auto-resolving.
+func (b *AlertBuilder) AutoResolveAsInconclusive() *AlertBuilder {
+	b.alert.AutoResolveAsInconclusive = true
+	return b
+}
+
+// NotificationDelay sets the duration to wait before creating the Slack post.
+func (b *AlertBuilder) NotificationDelay(d time.Duration) *AlertBuilder {
+	b.alert.NotificationDelaySeconds = durationSeconds(d)
+	return b
+}
+
+// ArchivingDelay sets the duration to wait before archiving the issue, after it is resolved.
+func (b *AlertBuilder) ArchivingDelay(d time.Duration) *AlertBuilder {
+	b.alert.ArchivingDelaySeconds = durationSeconds(d)
+	return b
+}
+
+// EscalateAfter adds an escalation point, which changes the issue severity and adds the specified Slack mentions
+// if the issue is still unresolved after the specified duration.
+func (b *AlertBuilder) EscalateAfter(d time.Duration, severity AlertSeverity, slackMentions ...string) *AlertBuilder {
+	b.alert.Escalation = append(b.alert.Escalation, &Escalation{
+		Severity:      severity,
+		DelaySeconds:  durationSeconds(d),
+		SlackMentions: slackMentions,
+	})
+	return b
+}
+
+// EscalateAndMoveAfter adds an escalation point, which changes the issue severity and moves the issue to the
+// specified Slack channel if the issue is still unresolved after the specified duration.
+func (b *AlertBuilder) EscalateAndMoveAfter(d time.Duration, severity AlertSeverity, moveToChannel string, slackMentions ...string) *AlertBuilder {
+	b.alert.Escalation = append(b.alert.Escalation, &Escalation{
+		Severity:      severity,
+		DelaySeconds:  durationSeconds(d),
+		SlackMentions: slackMentions,
+		MoveToChannel: moveToChannel,
+	})
+	return b
+}
+
+// IgnoreIfTextContains adds substrings that, if found in the alert text, cause the alert to be ignored.
+func (b *AlertBuilder) IgnoreIfTextContains(s ...string) *AlertBuilder {
+	b.alert.IgnoreIfTextContains = append(b.alert.IgnoreIfTextContains, s...)
+	return b
+}
+
+// Metadata adds a metadata key-value pair.
+func (b *AlertBuilder) Metadata(key string, value any) *AlertBuilder {
+	b.alert.Metadata[key] = value
+	return b
+}
+
+// Webhook adds a webhook button with the specified ID, URL and button text, configured by the specified options.
+func (b *AlertBuilder) Webhook(id, url, buttonText string, opts ...WebhookOption) *AlertBuilder {
+	w := &Webhook{
+		ID:         id,
+		URL:        url,
+		ButtonText: buttonText,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	b.alert.Webhooks = append(b.alert.Webhooks, w)
+
+	return b
+}
+
+// Build cleans and validates the alert, and returns it.
+// An error is returned if the alert is not valid, using the same error types as Alert.Validate.
+func (b *AlertBuilder) Build() (*Alert, error) {
+	return b.BuildWithPolicy(DefaultValidationPolicy())
+}
+
+// BuildWithPolicy cleans and validates the alert using the limits in the specified policy, and returns it.
+func (b *AlertBuilder) BuildWithPolicy(p *ValidationPolicy) (*Alert, error) {
+	b.alert.CleanWithPolicy(p)
+
+	if err := b.alert.ValidateWithPolicy(p); err != nil {
+		return nil, err
+	}
+
+	return b.alert, nil
+}
+
+// WithConfirmationText sets the text displayed in a confirmation dialog before the webhook is triggered.
+func WithConfirmationText(text string) WebhookOption {
+	return func(w *Webhook) {
+		w.ConfirmationText = text
+	}
+}
+
+// WithButtonStyle sets the visual style of the webhook button.
+func WithButtonStyle(style WebhookButtonStyle) WebhookOption {
+	return func(w *Webhook) {
+		w.ButtonStyle = style
+	}
+}
+
+// WithAccessLevel sets who can click the webhook button.
+func WithAccessLevel(level WebhookAccessLevel) WebhookOption {
+	return func(w *Webhook) {
+		w.AccessLevel = level
+	}
+}
+
+// WithDisplayMode sets when the webhook button is visible.
+func WithDisplayMode(mode WebhookDisplayMode) WebhookOption {
+	return func(w *Webhook) {
+		w.DisplayMode = mode
+	}
+}
+
+// WithPayload adds a key-value pair to the webhook payload.
+func WithPayload(key string, value any) WebhookOption {
+	return func(w *Webhook) {
+		if w.Payload == nil {
+			w.Payload = make(map[string]any)
+		}
+
+		w.Payload[key] = value
+	}
+}
+
+// WithPlainTextInput adds a text input field to the webhook modal dialog.
+func WithPlainTextInput(input *WebhookPlainTextInput) WebhookOption {
+	return func(w *Webhook) {
+		w.PlainTextInput = append(w.PlainTextInput, input)
+	}
+}
+
+// WithCheckboxInput adds a checkbox group to the webhook modal dialog.
+func WithCheckboxInput(input *WebhookCheckboxInput) WebhookOption {
+	return func(w *Webhook) {
+		w.CheckboxInput = append(w.CheckboxInput, input)
+	}
+}
+
+// durationSeconds converts a duration to whole seconds, truncating any fractional second.
+func durationSeconds(d time.Duration) int {
+	return int(d / time.Second)
+}
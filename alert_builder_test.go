@@ -0,0 +1,109 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid alert should be built", func(t *testing.T) {
+		t.Parallel()
+
+		ts := time.Now().UTC().Add(-time.Minute)
+
+		a, err := types.NewAlertBuilder(types.AlertError).
+			Timestamp(ts).
+			Channel(" c12345678 ").
+			Route("my-route").
+			CorrelationID("abc").
+			Header(":status: Disk usage above 90%").
+			Text("Disk usage is 95%").
+			Field("Host", "db-1").
+			Field("Disk", "/data").
+			AutoResolve(30*time.Minute).
+			NotificationDelay(1500*time.Millisecond).
+			EscalateAfter(2*time.Hour, types.AlertPanic, "<!here>").
+			EscalateAndMoveAfter(time.Hour, types.AlertError, "C87654321").
+			IgnoreIfTextContains("foo", "bar").
+			Metadata("team", "storage").
+			Webhook("restart", "https://example.com/restart", "Restart",
+				types.WithConfirmationText("Are you sure?"),
+				types.WithButtonStyle(types.WebhookButtonStyleDanger),
+				types.WithAccessLevel(types.WebhookAccessLevelGlobalAdmins),
+				types.WithDisplayMode(types.WebhookDisplayModeOpenIssue),
+				types.WithPayload("action", "restart"),
+				types.WithPlainTextInput(&types.WebhookPlainTextInput{ID: "reason", MaxLength: 100}),
+				types.WithCheckboxInput(&types.WebhookCheckboxInput{ID: "opts", Options: []*types.WebhookCheckboxOption{{Value: "force"}}}),
+			).
+			Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, types.AlertError, a.Severity)
+		assert.Equal(t, ts, a.Timestamp)
+		assert.Equal(t, "C12345678", a.SlackChannelID)
+		assert.Equal(t, "my-route", a.RouteKey)
+		assert.Equal(t, "abc", a.CorrelationID)
+		assert.Equal(t, []*types.Field{{Title: "Host", Value: "db-1"}, {Title: "Disk", Value: "/data"}}, a.Fields)
+		assert.True(t, a.IssueFollowUpEnabled)
+		assert.Equal(t, 1800, a.AutoResolveSeconds)
+		assert.Equal(t, 1, a.NotificationDelaySeconds)
+		assert.Equal(t, []string{"foo", "bar"}, a.IgnoreIfTextContains)
+		assert.Equal(t, "storage", a.Metadata["team"])
+
+		// Escalations should be sorted by Clean
+		require.Len(t, a.Escalation, 2)
+		assert.Equal(t, 3600, a.Escalation[0].DelaySeconds)
+		assert.Equal(t, "C87654321", a.Escalation[0].MoveToChannel)
+		assert.Equal(t, 7200, a.Escalation[1].DelaySeconds)
+		assert.Equal(t, types.AlertPanic, a.Escalation[1].Severity)
+		assert.Equal(t, []string{"<!here>"}, a.Escalation[1].SlackMentions)
+
+		require.Len(t, a.Webhooks, 1)
+		w := a.Webhooks[0]
+		assert.Equal(t, "restart", w.ID)
+		assert.Equal(t, "https://example.com/restart", w.URL)
+		assert.Equal(t, "Restart", w.ButtonText)
+		assert.Equal(t, "Are you sure?", w.ConfirmationText)
+		assert.Equal(t, types.WebhookButtonStyleDanger, w.ButtonStyle)
+		assert.Equal(t, types.WebhookAccessLevelGlobalAdmins, w.AccessLevel)
+		assert.Equal(t, types.WebhookDisplayModeOpenIssue, w.DisplayMode)
+		assert.Equal(t, map[string]any{"action": "restart"}, w.Payload)
+		assert.Len(t, w.PlainTextInput, 1)
+		assert.Len(t, w.CheckboxInput, 1)
+	})
+
+	t.Run("invalid alert should return a validation error", func(t *testing.T) {
+		t.Parallel()
+
+		a, err := types.NewAlertBuilder(types.AlertWarning).
+			Header("foo").
+			AutoResolve(time.Second).
+			Build()
+		require.ErrorContains(t, err, "autoResolveSeconds 1 is too low")
+		assert.Nil(t, a)
+
+		var validationErr *types.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "/autoResolveSeconds", validationErr.Field)
+	})
+
+	t.Run("policy should be applied", func(t *testing.T) {
+		t.Parallel()
+
+		p := types.DefaultValidationPolicy()
+		p.MaxFieldCount = 1
+
+		_, err := types.NewAlertBuilder(types.AlertInfo).
+			Header("foo").
+			Field("a", "1").
+			Field("b", "2").
+			BuildWithPolicy(p)
+		require.ErrorContains(t, err, "too many fields")
+	})
+}
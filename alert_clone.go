@@ -0,0 +1,113 @@
+package types
+
+// Clone returns a deep copy of the alert. Slices, maps and nested structs are copied, so the clone can be
+// modified without affecting the original (and vice versa).
+//
+// Metadata and webhook payload values are copied recursively if they are maps (map[string]any) or slices ([]any),
+// as produced by encoding/json. Other reference values (such as pointers) are shared between the original and the clone.
+func (a *Alert) Clone() *Alert {
+	if a == nil {
+		return nil
+	}
+
+	c := *a
+
+	c.Fields = cloneSlice(a.Fields, func(f *Field) *Field {
+		fieldCopy := *f
+		return &fieldCopy
+	})
+
+	c.Escalation = cloneSlice(a.Escalation, func(e *Escalation) *Escalation {
+		escalationCopy := *e
+		escalationCopy.SlackMentions = cloneStrings(e.SlackMentions)
+		return &escalationCopy
+	})
+
+	c.IgnoreIfTextContains = cloneStrings(a.IgnoreIfTextContains)
+	c.Webhooks = cloneSlice(a.Webhooks, cloneWebhook)
+	c.Metadata = cloneMap(a.Metadata)
+
+	return &c
+}
+
+func cloneWebhook(w *Webhook) *Webhook {
+	c := *w
+
+	c.Payload = cloneMap(w.Payload)
+
+	c.PlainTextInput = cloneSlice(w.PlainTextInput, func(input *WebhookPlainTextInput) *WebhookPlainTextInput {
+		inputCopy := *input
+		return &inputCopy
+	})
+
+	c.CheckboxInput = cloneSlice(w.CheckboxInput, func(input *WebhookCheckboxInput) *WebhookCheckboxInput {
+		inputCopy := *input
+		inputCopy.Options = cloneSlice(input.Options, func(o *WebhookCheckboxOption) *WebhookCheckboxOption {
+			optionCopy := *o
+			return &optionCopy
+		})
+		return &inputCopy
+	})
+
+	return &c
+}
+
+// cloneSlice copies a slice of pointers, using cloneFunc to copy each non-nil item. Nil items are kept as nil.
+func cloneSlice[T any](s []*T, cloneFunc func(*T) *T) []*T {
+	if s == nil {
+		return nil
+	}
+
+	c := make([]*T, len(s))
+
+	for i, item := range s {
+		if item != nil {
+			c[i] = cloneFunc(item)
+		}
+	}
+
+	return c
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+
+	return append(make([]string, 0, len(s)), s...)
+}
+
+func cloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+
+	c := make(map[string]any, len(m))
+
+	for k, v := range m {
+		c[k] = cloneValue(v)
+	}
+
+	return c
+}
+
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return cloneMap(v)
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		c := make([]any, len(v))
+
+		for i, item := range v {
+			c[i] = cloneValue(item)
+		}
+
+		return c
+	default:
+		return v
+	}
+}
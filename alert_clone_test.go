@@ -0,0 +1,111 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFullAlert() *types.Alert {
+	return &types.Alert{
+		Timestamp:            time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		CorrelationID:        "abc",
+		Header:               "header",
+		Text:                 "text",
+		Severity:             types.AlertError,
+		SlackChannelID:       "C12345678",
+		IssueFollowUpEnabled: true,
+		AutoResolveSeconds:   3600,
+		Fields:               []*types.Field{{Title: "a", Value: "1"}, nil, {Title: "b", Value: "2"}},
+		Escalation: []*types.Escalation{
+			{Severity: types.AlertPanic, DelaySeconds: 60, SlackMentions: []string{"<!here>"}},
+		},
+		IgnoreIfTextContains: []string{"foo"},
+		Webhooks: []*types.Webhook{
+			{
+				ID:             "w1",
+				URL:            "https://example.com",
+				ButtonText:     "Click",
+				Payload:        map[string]any{"a": "b", "nested": map[string]any{"x": []any{1.0, "y"}}},
+				PlainTextInput: []*types.WebhookPlainTextInput{{ID: "p1", MaxLength: 10}},
+				CheckboxInput: []*types.WebhookCheckboxInput{
+					{ID: "c1", Options: []*types.WebhookCheckboxOption{{Value: "o1"}}},
+				},
+			},
+		},
+		Metadata: map[string]any{"team": "storage", "tags": []any{"a", "b"}},
+	}
+}
+
+func TestAlertClone(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil alert should return nil", func(t *testing.T) {
+		t.Parallel()
+
+		var a *types.Alert
+		assert.Nil(t, a.Clone())
+	})
+
+	t.Run("clone should be equal to the original", func(t *testing.T) {
+		t.Parallel()
+
+		a := newFullAlert()
+		c := a.Clone()
+		assert.Equal(t, a, c)
+		assert.NotSame(t, a, c)
+		assert.Empty(t, types.DiffAlerts(a, c))
+	})
+
+	t.Run("nil slices and maps should stay nil", func(t *testing.T) {
+		t.Parallel()
+
+		c := (&types.Alert{Header: "foo"}).Clone()
+		assert.Nil(t, c.Fields)
+		assert.Nil(t, c.Webhooks)
+		assert.Nil(t, c.Metadata)
+	})
+
+	t.Run("modifying the clone should not affect the original", func(t *testing.T) {
+		t.Parallel()
+
+		a := newFullAlert()
+		c := a.Clone()
+
+		c.Fields[0].Value = "changed"
+		c.Fields[1] = &types.Field{Title: "new"}
+		c.Escalation[0].SlackMentions[0] = "<!channel>"
+		c.IgnoreIfTextContains[0] = "bar"
+		c.Webhooks[0].ButtonText = "changed"
+		c.Webhooks[0].Payload["a"] = "changed"
+		nested, ok := c.Webhooks[0].Payload["nested"].(map[string]any)
+		require.True(t, ok)
+		require.IsType(t, []any{}, nested["x"])
+		nestedSlice, ok := nested["x"].([]any)
+		require.True(t, ok)
+		nestedSlice[1] = "changed"
+		c.Webhooks[0].PlainTextInput[0].MaxLength = 20
+		c.Webhooks[0].CheckboxInput[0].Options[0].Value = "changed"
+		c.Metadata["team"] = "changed"
+		tags, ok := c.Metadata["tags"].([]any)
+		require.True(t, ok)
+		tags[0] = "changed"
+
+		assert.Equal(t, newFullAlert().Fields, a.Fields)
+		assert.Equal(t, "<!here>", a.Escalation[0].SlackMentions[0])
+		assert.Equal(t, "foo", a.IgnoreIfTextContains[0])
+
+		w := a.Webhooks[0]
+		assert.Equal(t, "Click", w.ButtonText)
+		assert.Equal(t, "b", w.Payload["a"])
+		assert.Equal(t, map[string]any{"x": []any{1.0, "y"}}, w.Payload["nested"], "nested slices should be deep copied")
+		assert.Equal(t, 10, w.PlainTextInput[0].MaxLength)
+		assert.Equal(t, "o1", w.CheckboxInput[0].Options[0].Value)
+
+		assert.Equal(t, "storage", a.Metadata["team"])
+		require.Equal(t, []any{"a", "b"}, a.Metadata["tags"])
+	})
+}
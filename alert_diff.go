@@ -0,0 +1,247 @@
+package types
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AlertChangeKind describes how a value differs between two alerts.
+type AlertChangeKind string
+
+const (
+	// AlertChangeAdded indicates that a list item or map key exists in the new alert, but not in the old alert.
+	AlertChangeAdded AlertChangeKind = "added"
+
+	// AlertChangeRemoved indicates that a list item or map key exists in the old alert, but not in the new alert.
+	AlertChangeRemoved AlertChangeKind = "removed"
+
+	// AlertChangeModified indicates that a value exists in both alerts, but is different.
+	AlertChangeModified AlertChangeKind = "modified"
+)
+
+// AlertChange describes a single difference between two alerts.
+type AlertChange struct {
+	// Field is a JSON pointer (RFC 6901) to the changed value, based on the JSON field names,
+	// such as '/header' or '/webhooks/1/buttonText'.
+	Field string `json:"field"`
+
+	// Kind describes how the value was changed.
+	Kind AlertChangeKind `json:"kind"`
+
+	// Visible is true if the value is rendered in the Slack post, i.e. if the change requires the Slack post to be updated.
+	Visible bool `json:"visible"`
+}
+
+// AlertChanges is a list of differences between two alerts, as returned by DiffAlerts.
+type AlertChanges []*AlertChange
+
+// HasVisibleChanges returns true if any of the changes are visible in the Slack post.
+func (c AlertChanges) HasVisibleChanges() bool {
+	for _, change := range c {
+		if change.Visible {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fields returns the JSON pointers of all changed values.
+func (c AlertChanges) Fields() []string {
+	fields := make([]string, len(c))
+
+	for i, change := range c {
+		fields[i] = change.Field
+	}
+
+	return fields
+}
+
+// DiffAlerts compares the old alert a with the new alert b, and returns a list of the changed values.
+// The returned list is empty if the alerts are equal. A nil alert is treated as an empty alert.
+//
+// Changes to the header, text, fallback text, author, host, footer, link, severity, username, icon emoji, fields
+// and the rendered webhook button properties (id, buttonText, buttonStyle, confirmationText and displayMode) are
+// marked as visible, since they change the content of the Slack post. All other changes (such as routing,
+// timing, escalations, metadata and webhook targets) are not visible.
+//
+// Nil and empty slices and maps are considered equal. The alerts should be cleaned before they are compared,
+// to avoid reporting differences that are removed by Alert.Clean (such as surrounding whitespace).
+func DiffAlerts(a, b *Alert) AlertChanges {
+	if a == nil {
+		a = &Alert{}
+	}
+
+	if b == nil {
+		b = &Alert{}
+	}
+
+	d := &alertDiffer{changes: AlertChanges{}}
+
+	if !a.Timestamp.Equal(b.Timestamp) {
+		d.modified("/timestamp", false)
+	}
+
+	diffValue(d, "/correlationId", false, a.CorrelationID, b.CorrelationID)
+	diffValue(d, "/type", false, a.Type, b.Type)
+	diffValue(d, "/header", true, a.Header, b.Header)
+	diffValue(d, "/headerWhenResolved", true, a.HeaderWhenResolved, b.HeaderWhenResolved)
+	diffValue(d, "/text", true, a.Text, b.Text)
+	diffValue(d, "/textWhenResolved", true, a.TextWhenResolved, b.TextWhenResolved)
+	diffValue(d, "/fallbackText", true, a.FallbackText, b.FallbackText)
+	diffValue(d, "/author", true, a.Author, b.Author)
+	diffValue(d, "/host", true, a.Host, b.Host)
+	diffValue(d, "/footer", true, a.Footer, b.Footer)
+	diffValue(d, "/link", true, a.Link, b.Link)
+	diffValue(d, "/issueFollowUpEnabled", false, a.IssueFollowUpEnabled, b.IssueFollowUpEnabled)
+	diffValue(d, "/autoResolveSeconds", false, a.AutoResolveSeconds, b.AutoResolveSeconds)
+	diffValue(d, "/autoResolveAsInconclusive", false, a.AutoResolveAsInconclusive, b.AutoResolveAsInconclusive)
+	diffValue(d, "/severity", true, a.Severity, b.Severity)
+	diffValue(d, "/slackChannelId", false, a.SlackChannelID, b.SlackChannelID)
+	diffValue(d, "/routeKey", false, a.RouteKey, b.RouteKey)
+	diffValue(d, "/username", true, a.Username, b.Username)
+	diffValue(d, "/iconEmoji", true, a.IconEmoji, b.IconEmoji)
+
+	diffSlice(d, "/fields", true, a.Fields, b.Fields, func(path string, x, y *Field) {
+		diffValue(d, path+"/title", true, x.Title, y.Title)
+		diffValue(d, path+"/value", true, x.Value, y.Value)
+	})
+
+	diffValue(d, "/notificationDelaySeconds", false, a.NotificationDelaySeconds, b.NotificationDelaySeconds)
+	diffValue(d, "/archivingDelaySeconds", false, a.ArchivingDelaySeconds, b.ArchivingDelaySeconds)
+
+	diffSlice(d, "/escalation", false, a.Escalation, b.Escalation, func(path string, x, y *Escalation) {
+		diffValue(d, path+"/severity", false, x.Severity, y.Severity)
+		diffValue(d, path+"/delaySeconds", false, x.DelaySeconds, y.DelaySeconds)
+		diffStrings(d, path+"/slackMentions", false, x.SlackMentions, y.SlackMentions)
+		diffValue(d, path+"/moveToChannel", false, x.MoveToChannel, y.MoveToChannel)
+	})
+
+	diffStrings(d, "/ignoreIfTextContains", false, a.IgnoreIfTextContains, b.IgnoreIfTextContains)
+	diffSlice(d, "/webhooks", true, a.Webhooks, b.Webhooks, d.webhook)
+	diffMap(d, "/metadata", false, a.Metadata, b.Metadata)
+	diffValue(d, "/failOnRateLimitError", false, a.FailOnRateLimitError, b.FailOnRateLimitError)
+
+	return d.changes
+}
+
+type alertDiffer struct {
+	changes AlertChanges
+}
+
+func (d *alertDiffer) add(field string, kind AlertChangeKind, visible bool) {
+	d.changes = append(d.changes, &AlertChange{Field: field, Kind: kind, Visible: visible})
+}
+
+func (d *alertDiffer) modified(field string, visible bool) {
+	d.add(field, AlertChangeModified, visible)
+}
+
+func (d *alertDiffer) webhook(path string, x, y *Webhook) {
+	diffValue(d, path+"/id", true, x.ID, y.ID)
+	diffValue(d, path+"/url", false, x.URL, y.URL)
+	diffValue(d, path+"/confirmationText", true, x.ConfirmationText, y.ConfirmationText)
+	diffValue(d, path+"/buttonText", true, x.ButtonText, y.ButtonText)
+	diffValue(d, path+"/buttonStyle", true, x.ButtonStyle, y.ButtonStyle)
+	diffValue(d, path+"/accessLevel", false, x.AccessLevel, y.AccessLevel)
+	diffValue(d, path+"/displayMode", true, x.DisplayMode, y.DisplayMode)
+	diffMap(d, path+"/payload", false, x.Payload, y.Payload)
+
+	diffSlice(d, path+"/plainTextInput", false, x.PlainTextInput, y.PlainTextInput, func(path string, x, y *WebhookPlainTextInput) {
+		if *x != *y {
+			d.modified(path, false)
+		}
+	})
+
+	diffSlice(d, path+"/checkboxInput", false, x.CheckboxInput, y.CheckboxInput, func(path string, x, y *WebhookCheckboxInput) {
+		diffValue(d, path+"/id", false, x.ID, y.ID)
+		diffValue(d, path+"/label", false, x.Label, y.Label)
+
+		diffSlice(d, path+"/options", false, x.Options, y.Options, func(path string, x, y *WebhookCheckboxOption) {
+			if *x != *y {
+				d.modified(path, false)
+			}
+		})
+	})
+}
+
+func diffValue[T comparable](d *alertDiffer, path string, visible bool, x, y T) {
+	if x != y {
+		d.modified(path, visible)
+	}
+}
+
+// diffSlice compares two slices of pointers item by item, using diffFunc to compare items that are non-nil in both slices.
+// Items beyond the length of the shorter slice are reported as added or removed.
+func diffSlice[T any](d *alertDiffer, path string, visible bool, x, y []*T, diffFunc func(path string, x, y *T)) {
+	for i := range max(len(x), len(y)) {
+		itemPath := path + "/" + strconv.Itoa(i)
+
+		switch {
+		case i >= len(x):
+			d.add(itemPath, AlertChangeAdded, visible)
+		case i >= len(y):
+			d.add(itemPath, AlertChangeRemoved, visible)
+		case x[i] == nil && y[i] == nil:
+			continue
+		case x[i] == nil || y[i] == nil:
+			d.modified(itemPath, visible)
+		default:
+			diffFunc(itemPath, x[i], y[i])
+		}
+	}
+}
+
+func diffStrings(d *alertDiffer, path string, visible bool, x, y []string) {
+	for i := range max(len(x), len(y)) {
+		itemPath := path + "/" + strconv.Itoa(i)
+
+		switch {
+		case i >= len(x):
+			d.add(itemPath, AlertChangeAdded, visible)
+		case i >= len(y):
+			d.add(itemPath, AlertChangeRemoved, visible)
+		case x[i] != y[i]:
+			d.modified(itemPath, visible)
+		}
+	}
+}
+
+// diffMap compares two maps key by key. Keys are reported in sorted order, for deterministic output.
+func diffMap(d *alertDiffer, path string, visible bool, x, y map[string]any) {
+	keys := make([]string, 0, len(x)+len(y))
+
+	for k := range x {
+		keys = append(keys, k)
+	}
+
+	for k := range y {
+		if _, ok := x[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		keyPath := path + "/" + escapeJSONPointerToken(k)
+		xv, inX := x[k]
+		yv, inY := y[k]
+
+		switch {
+		case !inX:
+			d.add(keyPath, AlertChangeAdded, visible)
+		case !inY:
+			d.add(keyPath, AlertChangeRemoved, visible)
+		case !reflect.DeepEqual(xv, yv):
+			d.modified(keyPath, visible)
+		}
+	}
+}
+
+// escapeJSONPointerToken escapes a JSON pointer reference token, as defined in RFC 6901.
+func escapeJSONPointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,87 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffAlerts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("equal alerts should have no changes", func(t *testing.T) {
+		t.Parallel()
+
+		changes := types.DiffAlerts(newFullAlert(), newFullAlert())
+		assert.NotNil(t, changes)
+		assert.Empty(t, changes)
+		assert.False(t, changes.HasVisibleChanges())
+
+		assert.Empty(t, types.DiffAlerts(nil, nil))
+		assert.Empty(t, types.DiffAlerts(&types.Alert{Fields: []*types.Field{}}, &types.Alert{}))
+	})
+
+	t.Run("invisible changes should be reported as not visible", func(t *testing.T) {
+		t.Parallel()
+
+		a := newFullAlert()
+		b := a.Clone()
+		b.Timestamp = b.Timestamp.Add(time.Minute)
+		b.AutoResolveSeconds = 7200
+		b.Escalation[0].DelaySeconds = 120
+		b.Webhooks[0].URL = "https://example.com/other"
+		b.Metadata["team"] = "other"
+
+		changes := types.DiffAlerts(a, b)
+		assert.Equal(t, []string{
+			"/timestamp",
+			"/autoResolveSeconds",
+			"/escalation/0/delaySeconds",
+			"/webhooks/0/url",
+			"/metadata/team",
+		}, changes.Fields())
+		assert.False(t, changes.HasVisibleChanges())
+	})
+
+	t.Run("visible changes should be reported as visible", func(t *testing.T) {
+		t.Parallel()
+
+		a := newFullAlert()
+		b := a.Clone()
+		b.Text = "other text"
+		b.Fields[2].Value = "3"
+		b.Fields = append(b.Fields, &types.Field{Title: "c", Value: "4"})
+		b.Webhooks[0].ButtonText = "Other"
+
+		changes := types.DiffAlerts(a, b)
+		assert.Equal(t, types.AlertChanges{
+			{Field: "/text", Kind: types.AlertChangeModified, Visible: true},
+			{Field: "/fields/2/value", Kind: types.AlertChangeModified, Visible: true},
+			{Field: "/fields/3", Kind: types.AlertChangeAdded, Visible: true},
+			{Field: "/webhooks/0/buttonText", Kind: types.AlertChangeModified, Visible: true},
+		}, changes)
+		assert.True(t, changes.HasVisibleChanges())
+	})
+
+	t.Run("removed items and map keys should be reported", func(t *testing.T) {
+		t.Parallel()
+
+		a := newFullAlert()
+		a.Metadata["a/b"] = 1
+		b := a.Clone()
+		b.Webhooks = nil
+		delete(b.Metadata, "a/b")
+		b.Metadata["new"] = true
+		b.Fields[1] = &types.Field{}
+
+		changes := types.DiffAlerts(a, b)
+		assert.Equal(t, types.AlertChanges{
+			{Field: "/fields/1", Kind: types.AlertChangeModified, Visible: true},
+			{Field: "/webhooks/0", Kind: types.AlertChangeRemoved, Visible: true},
+			{Field: "/metadata/a~1b", Kind: types.AlertChangeRemoved, Visible: false},
+			{Field: "/metadata/new", Kind: types.AlertChangeAdded, Visible: false},
+		}, changes)
+	})
+}
@@ -0,0 +1,350 @@
+package blockkit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/slackmgr/types"
+)
+
+const (
+	// StatusPlaceholder is replaced with the status emoji in the alert header and text.
+	StatusPlaceholder = ":status:"
+
+	// MaxSectionTextLength is the maximum length of the text in a Slack section block.
+	// Longer alert texts are split into multiple section blocks.
+	MaxSectionTextLength = 3000
+
+	// MaxSectionFieldCount is the maximum number of fields in a Slack section block.
+	// Alerts with more fields are rendered with multiple section blocks.
+	MaxSectionFieldCount = 10
+)
+
+// IssueState is the state of the issue that an alert belongs to.
+type IssueState string
+
+const (
+	// IssueStateOpen is used for open (unresolved) issues.
+	IssueStateOpen IssueState = "open"
+
+	// IssueStateResolved is used for resolved issues.
+	IssueStateResolved IssueState = "resolved"
+
+	// IssueStateInconclusive is used for issues that were resolved as inconclusive (see Alert.AutoResolveAsInconclusive).
+	IssueStateInconclusive IssueState = "inconclusive"
+)
+
+// IssueStateIsValid returns true if the provided IssueState is valid.
+func IssueStateIsValid(s IssueState) bool {
+	switch s {
+	case IssueStateOpen, IssueStateResolved, IssueStateInconclusive:
+		return true
+	}
+	return false
+}
+
+// StatusEmojis defines the emojis used for the :status: placeholder, for each issue status.
+type StatusEmojis struct {
+	Panic        string `json:"panic"`
+	Error        string `json:"error"`
+	Warning      string `json:"warning"`
+	Resolved     string `json:"resolved"`
+	Inconclusive string `json:"inconclusive"`
+	Info         string `json:"info"`
+}
+
+// DefaultStatusEmojis returns a new StatusEmojis with the default emojis.
+func DefaultStatusEmojis() *StatusEmojis {
+	return &StatusEmojis{
+		Panic:        ":rotating_light:",
+		Error:        ":red_circle:",
+		Warning:      ":warning:",
+		Resolved:     ":white_check_mark:",
+		Inconclusive: ":grey_question:",
+		Info:         ":information_source:",
+	}
+}
+
+// Message is a Slack message, in the format expected by the chat.postMessage and chat.update API methods.
+type Message struct {
+	// Text is the fallback text, displayed in notifications.
+	Text string `json:"text,omitempty"`
+
+	// Username is the username that the message is posted as.
+	Username string `json:"username,omitempty"`
+
+	// IconEmoji is the emoji that the message is posted with.
+	IconEmoji string `json:"icon_emoji,omitempty"`
+
+	// Blocks are the Block Kit blocks of the message.
+	Blocks []*Block `json:"blocks"`
+}
+
+// Block is a Slack Block Kit layout block.
+type Block struct {
+	Type     string        `json:"type"`
+	Text     *TextObject   `json:"text,omitempty"`
+	Fields   []*TextObject `json:"fields,omitempty"`
+	Elements []any         `json:"elements,omitempty"`
+}
+
+// TextObject is a Slack Block Kit text object, of type 'plain_text' or 'mrkdwn'.
+type TextObject struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// Button is a Slack Block Kit button element.
+type Button struct {
+	Type     string      `json:"type"`
+	Text     *TextObject `json:"text"`
+	ActionID string      `json:"action_id"`
+	Value    string      `json:"value"`
+	Style    string      `json:"style,omitempty"`
+	Confirm  *Confirm    `json:"confirm,omitempty"`
+}
+
+// Confirm is a Slack Block Kit confirmation dialog object.
+type Confirm struct {
+	Title   *TextObject `json:"title"`
+	Text    *TextObject `json:"text"`
+	Confirm *TextObject `json:"confirm"`
+	Deny    *TextObject `json:"deny"`
+}
+
+// Render renders the alert to a Slack message, for an issue in the specified state, using the default status emojis.
+func Render(alert *types.Alert, state IssueState) (*Message, error) {
+	return RenderWithEmojis(alert, state, DefaultStatusEmojis())
+}
+
+// RenderWithEmojis renders the alert to a Slack message, for an issue in the specified state, using the specified status emojis.
+// The default status emojis are used if emojis is nil.
+func RenderWithEmojis(alert *types.Alert, state IssueState, emojis *StatusEmojis) (*Message, error) {
+	if alert == nil {
+		return nil, errors.New("alert is nil")
+	}
+
+	if !IssueStateIsValid(state) {
+		return nil, fmt.Errorf("issue state '%s' is not valid", state)
+	}
+
+	if emojis == nil {
+		emojis = DefaultStatusEmojis()
+	}
+
+	status := emojis.forAlert(alert, state)
+	resolved := state != IssueStateOpen
+
+	header := alert.Header
+	text := alert.Text
+
+	if resolved && alert.HeaderWhenResolved != "" {
+		header = alert.HeaderWhenResolved
+	}
+
+	if resolved && alert.TextWhenResolved != "" {
+		text = alert.TextWhenResolved
+	}
+
+	header = strings.ReplaceAll(header, StatusPlaceholder, status)
+	text = strings.ReplaceAll(text, StatusPlaceholder, status)
+
+	msg := &Message{
+		Text:      alert.FallbackText,
+		Username:  alert.Username,
+		IconEmoji: alert.IconEmoji,
+		Blocks:    []*Block{},
+	}
+
+	if msg.Text == "" {
+		msg.Text = header
+	}
+
+	if header != "" {
+		msg.Blocks = append(msg.Blocks, &Block{Type: "header", Text: plainText(header)})
+	}
+
+	for _, chunk := range splitText(text, MaxSectionTextLength) {
+		msg.Blocks = append(msg.Blocks, &Block{Type: "section", Text: mrkdwn(chunk)})
+	}
+
+	msg.Blocks = append(msg.Blocks, fieldBlocks(alert.Fields)...)
+
+	if block := contextBlock(alert); block != nil {
+		msg.Blocks = append(msg.Blocks, block)
+	}
+
+	if block := actionsBlock(alert.Webhooks, state); block != nil {
+		msg.Blocks = append(msg.Blocks, block)
+	}
+
+	if alert.Footer != "" {
+		msg.Blocks = append(msg.Blocks, &Block{Type: "context", Elements: []any{mrkdwn(alert.Footer)}})
+	}
+
+	return msg, nil
+}
+
+// forAlert returns the status emoji for the alert, for an issue in the specified state.
+func (e *StatusEmojis) forAlert(alert *types.Alert, state IssueState) string {
+	if state == IssueStateResolved {
+		return e.Resolved
+	}
+
+	if state == IssueStateInconclusive {
+		return e.Inconclusive
+	}
+
+	switch alert.Severity {
+	case types.AlertPanic:
+		return e.Panic
+	case types.AlertWarning:
+		return e.Warning
+	case types.AlertResolved:
+		return e.Resolved
+	case types.AlertInfo:
+		return e.Info
+	case types.AlertError:
+		return e.Error
+	default:
+		return e.Error
+	}
+}
+
+func fieldBlocks(fields []*types.Field) []*Block {
+	var blocks []*Block
+	var current *Block
+
+	for _, f := range fields {
+		if f == nil {
+			continue
+		}
+
+		if current == nil || len(current.Fields) == MaxSectionFieldCount {
+			current = &Block{Type: "section"}
+			blocks = append(blocks, current)
+		}
+
+		current.Fields = append(current.Fields, mrkdwn(fmt.Sprintf("*%s*\n%s", f.Title, f.Value)))
+	}
+
+	return blocks
+}
+
+func contextBlock(alert *types.Alert) *Block {
+	var elements []any
+
+	if alert.Author != "" {
+		elements = append(elements, mrkdwn("*Author:* "+alert.Author))
+	}
+
+	if alert.Host != "" {
+		elements = append(elements, mrkdwn("*Host:* "+alert.Host))
+	}
+
+	if alert.Link != "" {
+		elements = append(elements, mrkdwn(fmt.Sprintf("<%s|Link>", alert.Link)))
+	}
+
+	if len(elements) == 0 {
+		return nil
+	}
+
+	return &Block{Type: "context", Elements: elements}
+}
+
+func actionsBlock(webhooks []*types.Webhook, state IssueState) *Block {
+	var elements []any
+
+	for _, w := range webhooks {
+		if w == nil || !webhookIsVisible(w, state) {
+			continue
+		}
+
+		button := &Button{
+			Type:     "button",
+			Text:     plainText(w.ButtonText),
+			ActionID: w.ID,
+			Value:    w.ID,
+			Style:    string(w.ButtonStyle),
+		}
+
+		if w.ConfirmationText != "" {
+			button.Confirm = &Confirm{
+				Title:   plainText("Are you sure?"),
+				Text:    mrkdwn(w.ConfirmationText),
+				Confirm: plainText("Yes"),
+				Deny:    plainText("Cancel"),
+			}
+		}
+
+		elements = append(elements, button)
+	}
+
+	if len(elements) == 0 {
+		return nil
+	}
+
+	return &Block{Type: "actions", Elements: elements}
+}
+
+// webhookIsVisible returns true if the webhook button should be displayed for an issue in the specified state.
+func webhookIsVisible(w *types.Webhook, state IssueState) bool {
+	switch w.DisplayMode {
+	case types.WebhookDisplayModeOpenIssue:
+		return state == IssueStateOpen
+	case types.WebhookDisplayModeResolvedIssue:
+		return state != IssueStateOpen
+	case types.WebhookDisplayModeAlways:
+		return true
+	default:
+		return true
+	}
+}
+
+// splitText splits text into chunks of at most maxLen characters, preferably at line breaks.
+func splitText(text string, maxLen int) []string {
+	var chunks []string
+
+	for text != "" {
+		if utf8.RuneCountInString(text) <= maxLen {
+			chunks = append(chunks, text)
+			break
+		}
+
+		// Find the byte offset of the first maxLen runes
+		end := len(text)
+		runes := 0
+
+		for i := range text {
+			if runes == maxLen {
+				end = i
+				break
+			}
+			runes++
+		}
+
+		if i := strings.LastIndexByte(text[:end], '\n'); i > 0 {
+			end = i + 1
+		}
+
+		if chunk := strings.TrimSuffix(text[:end], "\n"); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+
+		text = text[end:]
+	}
+
+	return chunks
+}
+
+func plainText(text string) *TextObject {
+	return &TextObject{Type: "plain_text", Text: text, Emoji: true}
+}
+
+func mrkdwn(text string) *TextObject {
+	return &TextObject{Type: "mrkdwn", Text: text}
+}
@@ -0,0 +1,188 @@
+package blockkit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/blockkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func newAlert() *types.Alert {
+	return &types.Alert{
+		Timestamp:          time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Header:             ":status: Disk usage above 90%",
+		HeaderWhenResolved: ":status: Disk usage back to normal",
+		Text:               "Disk usage on `/data` is *95%*",
+		FallbackText:       "Disk usage above 90%",
+		Author:             "disk-monitor",
+		Host:               "db-1",
+		Footer:             "Managed by the storage team",
+		Link:               "https://example.com/dashboards/disk",
+		Severity:           types.AlertError,
+		SlackChannelID:     "C12345678",
+		Username:           "Disk Monitor",
+		IconEmoji:          ":floppy_disk:",
+		Fields: []*types.Field{
+			{Title: "Host", Value: "db-1"},
+			{Title: "Mount", Value: "/data"},
+		},
+		Webhooks: []*types.Webhook{
+			{ID: "cleanup", URL: "https://example.com/cleanup", ButtonText: "Clean up", ButtonStyle: types.WebhookButtonStylePrimary, DisplayMode: types.WebhookDisplayModeOpenIssue},
+			{ID: "restart", URL: "https://example.com/restart", ButtonText: "Restart", ButtonStyle: types.WebhookButtonStyleDanger, ConfirmationText: "This restarts *db-1*."},
+			{ID: "postmortem", URL: "https://example.com/postmortem", ButtonText: "Write postmortem", DisplayMode: types.WebhookDisplayModeResolvedIssue},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		alert  func() *types.Alert
+		state  blockkit.IssueState
+		golden string
+	}{
+		{
+			name:   "open issue",
+			alert:  newAlert,
+			state:  blockkit.IssueStateOpen,
+			golden: "open.json",
+		},
+		{
+			name: "resolved issue",
+			alert: func() *types.Alert {
+				a := newAlert()
+				a.TextWhenResolved = "Disk usage on `/data` is back below 90%"
+				return a
+			},
+			state:  blockkit.IssueStateResolved,
+			golden: "resolved.json",
+		},
+		{
+			name:   "inconclusive issue",
+			alert:  newAlert,
+			state:  blockkit.IssueStateInconclusive,
+			golden: "inconclusive.json",
+		},
+		{
+			name: "panic alert with text only",
+			alert: func() *types.Alert {
+				return &types.Alert{Text: ":status: Everything is on fire", Severity: types.AlertPanic}
+			},
+			state:  blockkit.IssueStateOpen,
+			golden: "panic_text_only.json",
+		},
+		{
+			name: "long text and many fields",
+			alert: func() *types.Alert {
+				a := &types.Alert{Header: "Many fields", Severity: types.AlertWarning}
+				a.Text = strings.Repeat(strings.Repeat("a", 99)+"\n", 40)
+				for i := range types.MaxFieldCount {
+					a.Fields = append(a.Fields, &types.Field{Title: "Field", Value: strings.Repeat("v", i+1)})
+				}
+				return a
+			},
+			state:  blockkit.IssueStateOpen,
+			golden: "long_text_many_fields.json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg, err := blockkit.Render(tc.alert(), tc.state)
+			require.NoError(t, err)
+
+			var body bytes.Buffer
+			enc := json.NewEncoder(&body)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			require.NoError(t, enc.Encode(msg))
+
+			assertGolden(t, tc.golden, body.Bytes())
+		})
+	}
+}
+
+func TestRenderStatusAndWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("status placeholder should use the issue state", func(t *testing.T) {
+		t.Parallel()
+
+		msg, err := blockkit.Render(newAlert(), blockkit.IssueStateOpen)
+		require.NoError(t, err)
+		assert.Equal(t, ":red_circle: Disk usage above 90%", msg.Blocks[0].Text.Text)
+
+		msg, err = blockkit.Render(newAlert(), blockkit.IssueStateResolved)
+		require.NoError(t, err)
+		assert.Equal(t, ":white_check_mark: Disk usage back to normal", msg.Blocks[0].Text.Text)
+
+		emojis := blockkit.DefaultStatusEmojis()
+		emojis.Error = ":fire:"
+		msg, err = blockkit.RenderWithEmojis(newAlert(), blockkit.IssueStateOpen, emojis)
+		require.NoError(t, err)
+		assert.Equal(t, ":fire: Disk usage above 90%", msg.Blocks[0].Text.Text)
+	})
+
+	t.Run("webhooks should be filtered by display mode", func(t *testing.T) {
+		t.Parallel()
+
+		buttonIDs := func(msg *blockkit.Message) []string {
+			var ids []string
+			for _, block := range msg.Blocks {
+				for _, element := range block.Elements {
+					if button, ok := element.(*blockkit.Button); ok {
+						ids = append(ids, button.ActionID)
+					}
+				}
+			}
+			return ids
+		}
+
+		msg, err := blockkit.Render(newAlert(), blockkit.IssueStateOpen)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cleanup", "restart"}, buttonIDs(msg))
+
+		msg, err = blockkit.Render(newAlert(), blockkit.IssueStateInconclusive)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"restart", "postmortem"}, buttonIDs(msg))
+	})
+
+	t.Run("invalid input should return an error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := blockkit.Render(nil, blockkit.IssueStateOpen)
+		require.Error(t, err)
+
+		_, err = blockkit.Render(newAlert(), "foo")
+		require.ErrorContains(t, err, "issue state 'foo' is not valid")
+	})
+}
+
+func assertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, actual, 0o600))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "run 'go test ./blockkit -update' to create the golden files")
+	assert.JSONEq(t, string(expected), string(actual))
+}
@@ -0,0 +1,17 @@
+// Package blockkit renders Slack Manager alerts to Slack Block Kit messages.
+//
+// The rendered message reflects the state of the issue that the alert belongs to: the :status: placeholder
+// is replaced with the emoji for the issue status, HeaderWhenResolved and TextWhenResolved are used for resolved
+// (and inconclusive) issues, and webhook buttons are filtered by their WebhookDisplayMode.
+//
+// Alerts should be cleaned and validated before they are rendered.
+//
+// Example:
+//
+//	msg, err := blockkit.Render(alert, blockkit.IssueStateOpen)
+//	if err != nil {
+//		// handle error
+//	}
+//
+//	body, err := json.Marshal(msg)
+package blockkit
@@ -0,0 +1,400 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DBCacheHitsMetric is the counter metric with the number of cache hits of a DB wrapped with WithCache,
+	// labelled by cache (see DBCacheLabel).
+	DBCacheHitsMetric = "db_cache_hits_total"
+
+	// DBCacheMissesMetric is the counter metric with the number of cache misses of a DB wrapped with WithCache,
+	// labelled by cache (see DBCacheLabel).
+	DBCacheMissesMetric = "db_cache_misses_total"
+
+	// DBCacheLabel is the label with the cache name of the WithCache metrics: "issue", "move_mapping" or
+	// "channel_processing_state".
+	DBCacheLabel = "cache"
+)
+
+// DBCacheOptions defines the caches of a DB wrapped with WithCache. A cache with size 0 is disabled.
+type DBCacheOptions struct {
+	// IssueCacheSize is the maximum number of cached FindOpenIssueByCorrelationID and FindIssueBySlackPostID results
+	// (including the results of their VersionedDB counterparts).
+	IssueCacheSize int
+
+	// IssueTTL is the maximum time an issue lookup is cached.
+	IssueTTL time.Duration
+
+	// MoveMappingCacheSize is the maximum number of cached FindMoveMapping results.
+	MoveMappingCacheSize int
+
+	// MoveMappingTTL is the maximum time a move mapping lookup is cached.
+	MoveMappingTTL time.Duration
+
+	// ChannelProcessingStateCacheSize is the maximum number of cached FindChannelProcessingState results.
+	ChannelProcessingStateCacheSize int
+
+	// ChannelProcessingStateTTL is the maximum time a channel processing state lookup is cached.
+	ChannelProcessingStateTTL time.Duration
+}
+
+// DefaultDBCacheOptions returns a new DBCacheOptions with the default settings.
+func DefaultDBCacheOptions() *DBCacheOptions {
+	return &DBCacheOptions{
+		IssueCacheSize:                  10000,
+		IssueTTL:                        5 * time.Second,
+		MoveMappingCacheSize:            10000,
+		MoveMappingTTL:                  30 * time.Second,
+		ChannelProcessingStateCacheSize: 1000,
+		ChannelProcessingStateTTL:       5 * time.Second,
+	}
+}
+
+// Validate returns an error if the options are invalid.
+func (o *DBCacheOptions) Validate() error {
+	if o == nil {
+		return errors.New("db cache options cannot be nil")
+	}
+
+	if o.IssueCacheSize < 0 {
+		return errors.New("issueCacheSize must be >=0")
+	}
+
+	if o.IssueCacheSize > 0 && o.IssueTTL <= 0 {
+		return errors.New("issueTtl must be >0")
+	}
+
+	if o.MoveMappingCacheSize < 0 {
+		return errors.New("moveMappingCacheSize must be >=0")
+	}
+
+	if o.MoveMappingCacheSize > 0 && o.MoveMappingTTL <= 0 {
+		return errors.New("moveMappingTtl must be >0")
+	}
+
+	if o.ChannelProcessingStateCacheSize < 0 {
+		return errors.New("channelProcessingStateCacheSize must be >=0")
+	}
+
+	if o.ChannelProcessingStateCacheSize > 0 && o.ChannelProcessingStateTTL <= 0 {
+		return errors.New("channelProcessingStateTtl must be >0")
+	}
+
+	return nil
+}
+
+// cachingDB is a DB decorator with read-through caches. See WithCache.
+type cachingDB struct {
+	db      DB
+	metrics Metrics
+
+	issues       *lruCache[*StoredIssue] // A nil issue means that no issue was found
+	moveMappings *lruCache[json.RawMessage]
+	states       *lruCache[*ChannelProcessingState]
+}
+
+// versionedCachingDB is a cachingDB that wraps a VersionedDB.
+type versionedCachingDB struct {
+	*cachingDB
+
+	versioned VersionedDB
+}
+
+// WithCache returns a DB that wraps db, with bounded LRU read-through caches (with TTLs) for the hot lookups:
+// FindOpenIssueByCorrelationID, FindIssueBySlackPostID, FindMoveMapping and FindChannelProcessingState.
+// "Not found" results are cached as well. Errors are never cached.
+//
+// The caches are invalidated by the writes made through the returned DB: SaveIssue, SaveIssues and MoveIssue
+// invalidate the lookups of the affected issues, SaveMoveMapping and DeleteMoveMapping the lookup of the move
+// mapping, SaveChannelProcessingState the lookup of the state, and DropAllData all caches. Writes made by other
+// instances (or directly to the wrapped DB) are not seen until the TTL has passed, so the TTLs should be kept short
+// if multiple Slack Manager instances share the database.
+//
+// The returned DB implements VersionedDB if db does, and caches FindOpenVersionedIssueByCorrelationID and
+// FindVersionedIssueBySlackPostID in the issue cache as well.
+//
+// Cache hits and misses are counted through metrics (see DBCacheHitsMetric and DBCacheMissesMetric).
+// The default options are used if opts is nil, and NoopMetrics is used if metrics is nil.
+func WithCache(db DB, metrics Metrics, opts *DBCacheOptions) (DB, error) { //nolint:ireturn
+	if opts == nil {
+		opts = DefaultDBCacheOptions()
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid db cache options: %w", err)
+	}
+
+	if metrics == nil {
+		metrics = &NoopMetrics{}
+	}
+
+	metrics.RegisterCounter(DBCacheHitsMetric, "Number of database cache hits", DBCacheLabel)
+	metrics.RegisterCounter(DBCacheMissesMetric, "Number of database cache misses", DBCacheLabel)
+
+	d := &cachingDB{
+		db:           db,
+		metrics:      metrics,
+		issues:       newLRUCache[*StoredIssue](opts.IssueCacheSize, opts.IssueTTL),
+		moveMappings: newLRUCache[json.RawMessage](opts.MoveMappingCacheSize, opts.MoveMappingTTL),
+		states:       newLRUCache[*ChannelProcessingState](opts.ChannelProcessingStateCacheSize, opts.ChannelProcessingStateTTL),
+	}
+
+	if versioned, ok := db.(VersionedDB); ok {
+		return &versionedCachingDB{cachingDB: d, versioned: versioned}, nil
+	}
+
+	return d, nil
+}
+
+func (d *cachingDB) Init(ctx context.Context, skipSchemaValidation bool) error {
+	return d.db.Init(ctx, skipSchemaValidation)
+}
+
+func (d *cachingDB) SaveAlert(ctx context.Context, alert *Alert) error {
+	return d.db.SaveAlert(ctx, alert)
+}
+
+func (d *cachingDB) SaveIssue(ctx context.Context, issue Issue) error {
+	err := d.db.SaveIssue(ctx, issue)
+	d.invalidateIssue(issue, issueChannelID(issue))
+
+	return err
+}
+
+func (d *cachingDB) SaveIssues(ctx context.Context, issues ...Issue) error {
+	err := d.db.SaveIssues(ctx, issues...)
+
+	for _, issue := range issues {
+		d.invalidateIssue(issue, issueChannelID(issue))
+	}
+
+	return err
+}
+
+func (d *cachingDB) MoveIssue(ctx context.Context, issue Issue, sourceChannelID, targetChannelID string) error {
+	err := d.db.MoveIssue(ctx, issue, sourceChannelID, targetChannelID)
+	d.invalidateIssue(issue, sourceChannelID, targetChannelID)
+
+	return err
+}
+
+func (d *cachingDB) FindOpenIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (string, json.RawMessage, error) {
+	return unversionedIssue(d.findIssue(issueCorrelationCacheKey(channelID, correlationID), func() (*StoredIssue, error) {
+		return storedIssue(d.db.FindOpenIssueByCorrelationID(ctx, channelID, correlationID))
+	}))
+}
+
+func (d *versionedCachingDB) FindOpenVersionedIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (*StoredIssue, error) {
+	return d.findIssue(versionedIssueCacheKey(issueCorrelationCacheKey(channelID, correlationID)), func() (*StoredIssue, error) {
+		return d.versioned.FindOpenVersionedIssueByCorrelationID(ctx, channelID, correlationID)
+	})
+}
+
+func (d *cachingDB) FindIssueBySlackPostID(ctx context.Context, channelID, postID string) (string, json.RawMessage, error) {
+	return unversionedIssue(d.findIssue(issuePostCacheKey(channelID, postID), func() (*StoredIssue, error) {
+		return storedIssue(d.db.FindIssueBySlackPostID(ctx, channelID, postID))
+	}))
+}
+
+func (d *versionedCachingDB) FindVersionedIssueBySlackPostID(ctx context.Context, channelID, postID string) (*StoredIssue, error) {
+	return d.findIssue(versionedIssueCacheKey(issuePostCacheKey(channelID, postID)), func() (*StoredIssue, error) {
+		return d.versioned.FindVersionedIssueBySlackPostID(ctx, channelID, postID)
+	})
+}
+
+func (d *cachingDB) FindActiveChannels(ctx context.Context) ([]string, error) {
+	return d.db.FindActiveChannels(ctx)
+}
+
+func (d *cachingDB) LoadOpenIssuesInChannel(ctx context.Context, channelID string) (map[string]json.RawMessage, error) {
+	return d.db.LoadOpenIssuesInChannel(ctx, channelID)
+}
+
+func (d *versionedCachingDB) LoadOpenVersionedIssuesInChannel(ctx context.Context, channelID string) (map[string]*StoredIssue, error) {
+	return d.versioned.LoadOpenVersionedIssuesInChannel(ctx, channelID)
+}
+
+func (d *cachingDB) SaveMoveMapping(ctx context.Context, moveMapping MoveMapping) error {
+	err := d.db.SaveMoveMapping(ctx, moveMapping)
+
+	if moveMapping != nil {
+		d.moveMappings.remove(moveMappingCacheKey(moveMapping.ChannelID(), moveMapping.GetCorrelationID()))
+	}
+
+	return err
+}
+
+func (d *cachingDB) FindMoveMapping(ctx context.Context, channelID, correlationID string) (json.RawMessage, error) {
+	if !d.moveMappings.enabled() {
+		return d.db.FindMoveMapping(ctx, channelID, correlationID)
+	}
+
+	key := moveMappingCacheKey(channelID, correlationID)
+
+	if body, ok := d.moveMappings.get(key, time.Now()); ok {
+		d.metrics.CounterInc(DBCacheHitsMetric, "move_mapping")
+		return bytes.Clone(body), nil
+	}
+
+	d.metrics.CounterInc(DBCacheMissesMetric, "move_mapping")
+
+	generation := d.moveMappings.currentGeneration()
+
+	body, err := d.db.FindMoveMapping(ctx, channelID, correlationID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.moveMappings.add(key, "", bytes.Clone(body), generation, time.Now())
+
+	return body, nil
+}
+
+func (d *cachingDB) DeleteMoveMapping(ctx context.Context, channelID, correlationID string) error {
+	err := d.db.DeleteMoveMapping(ctx, channelID, correlationID)
+	d.moveMappings.remove(moveMappingCacheKey(channelID, correlationID))
+
+	return err
+}
+
+func (d *cachingDB) SaveChannelProcessingState(ctx context.Context, state *ChannelProcessingState) error {
+	err := d.db.SaveChannelProcessingState(ctx, state)
+
+	if state != nil {
+		d.states.remove(state.ChannelID)
+	}
+
+	return err
+}
+
+func (d *cachingDB) FindChannelProcessingState(ctx context.Context, channelID string) (*ChannelProcessingState, error) {
+	if !d.states.enabled() {
+		return d.db.FindChannelProcessingState(ctx, channelID)
+	}
+
+	if state, ok := d.states.get(channelID, time.Now()); ok {
+		d.metrics.CounterInc(DBCacheHitsMetric, "channel_processing_state")
+		return copyChannelProcessingState(state), nil
+	}
+
+	d.metrics.CounterInc(DBCacheMissesMetric, "channel_processing_state")
+
+	generation := d.states.currentGeneration()
+
+	state, err := d.db.FindChannelProcessingState(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.states.add(channelID, "", copyChannelProcessingState(state), generation, time.Now())
+
+	return state, nil
+}
+
+func (d *cachingDB) DropAllData(ctx context.Context) error {
+	err := d.db.DropAllData(ctx)
+
+	d.issues.purge()
+	d.moveMappings.purge()
+	d.states.purge()
+
+	return err
+}
+
+// findIssue returns the cached issue lookup with the specified key, or calls find and caches the result.
+// The result is tagged with the issue ID, so that it is invalidated when the issue is saved or moved.
+func (d *cachingDB) findIssue(key string, find func() (*StoredIssue, error)) (*StoredIssue, error) {
+	if !d.issues.enabled() {
+		return find()
+	}
+
+	if issue, ok := d.issues.get(key, time.Now()); ok {
+		d.metrics.CounterInc(DBCacheHitsMetric, "issue")
+		return copyStoredIssue(issue), nil
+	}
+
+	d.metrics.CounterInc(DBCacheMissesMetric, "issue")
+
+	generation := d.issues.currentGeneration()
+
+	issue, err := find()
+	if err != nil {
+		return nil, err
+	}
+
+	var tag string
+	if issue != nil {
+		tag = issue.ID
+	}
+
+	d.issues.add(key, tag, copyStoredIssue(issue), generation, time.Now())
+
+	return issue, nil
+}
+
+// invalidateIssue removes the cached lookups of the issue, and the lookups in the specified channels that may
+// return the issue after the write (including cached "not found" results).
+// The issue is invalidated even if the write failed, since it may have been written anyway.
+func (d *cachingDB) invalidateIssue(issue Issue, channelIDs ...string) {
+	if issue == nil {
+		return
+	}
+
+	d.issues.removeTag(issue.UniqueID())
+
+	keys := make([]string, 0, 4*len(channelIDs))
+
+	for _, channelID := range channelIDs {
+		key := issueCorrelationCacheKey(channelID, issue.GetCorrelationID())
+		keys = append(keys, key, versionedIssueCacheKey(key))
+
+		if postID := issue.CurrentPostID(); postID != "" {
+			key = issuePostCacheKey(channelID, postID)
+			keys = append(keys, key, versionedIssueCacheKey(key))
+		}
+	}
+
+	d.issues.remove(keys...)
+}
+
+// copyStoredIssue returns a deep copy of the issue, or nil if the issue is nil.
+func copyStoredIssue(issue *StoredIssue) *StoredIssue {
+	if issue == nil {
+		return nil
+	}
+
+	return &StoredIssue{ID: issue.ID, Body: bytes.Clone(issue.Body), Version: issue.Version}
+}
+
+// issueChannelID returns the channel ID of the issue, or an empty string if the issue is nil.
+func issueChannelID(issue Issue) string {
+	if issue == nil {
+		return ""
+	}
+
+	return issue.ChannelID()
+}
+
+func issueCorrelationCacheKey(channelID, correlationID string) string {
+	return "correlation\x00" + channelID + "\x00" + correlationID
+}
+
+func issuePostCacheKey(channelID, postID string) string {
+	return "post\x00" + channelID + "\x00" + postID
+}
+
+// versionedIssueCacheKey returns the cache key of the versioned lookup with the specified (unversioned) key.
+func versionedIssueCacheKey(key string) string {
+	return "versioned\x00" + key
+}
+
+func moveMappingCacheKey(channelID, correlationID string) string {
+	return channelID + "\x00" + correlationID
+}
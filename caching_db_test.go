@@ -0,0 +1,382 @@
+package types_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/dbtests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBCacheOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	var opts *types.DBCacheOptions
+	require.Error(t, opts.Validate())
+
+	opts = types.DefaultDBCacheOptions()
+	require.NoError(t, opts.Validate())
+
+	opts.IssueCacheSize = -1
+	require.ErrorContains(t, opts.Validate(), "issueCacheSize must be >=0")
+
+	opts.IssueCacheSize = 0
+	opts.IssueTTL = 0
+	require.NoError(t, opts.Validate(), "ttl is not required for a disabled cache")
+
+	opts.MoveMappingTTL = 0
+	require.ErrorContains(t, opts.Validate(), "moveMappingTtl must be >0")
+
+	_, err := types.WithCache(types.NewInMemoryDB(), nil, opts)
+	require.ErrorContains(t, err, "invalid db cache options")
+
+	_, err = types.WithCache(types.NewInMemoryDB(), nil, nil)
+	require.NoError(t, err)
+}
+
+func TestWithCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("caching db should pass the db tests", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := types.WithCache(types.NewInMemoryDB(), nil, nil)
+		require.NoError(t, err)
+
+		dbtests.RunAllTests(t, db)
+	})
+
+	t.Run("issue lookups should be cached and invalidated when the issue is saved", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		metrics := &recordingMetrics{}
+		inner, db := newCachingDB(t, metrics, nil)
+
+		id, body, err := db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Empty(t, id)
+		assert.Nil(t, body)
+
+		// The "not found" result is cached, and must be invalidated when the issue is created
+		issue := newTestIssue("issue-1", "C000000001", "corr-1", "1700000000.000001")
+		require.NoError(t, db.SaveIssue(ctx, issue))
+
+		for range 3 {
+			id, body, err = db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+			require.NoError(t, err)
+			assert.Equal(t, "issue-1", id)
+			assert.Equal(t, "1700000000.000001", testIssueFromJSON(t, body).PostID)
+		}
+
+		assert.Equal(t, 2, inner.Calls("FindOpenIssueByCorrelationID"))
+		assert.InDelta(t, 2, metrics.counter(types.DBCacheHitsMetric, "issue"), 0)
+		assert.InDelta(t, 2, metrics.counter(types.DBCacheMissesMetric, "issue"), 0)
+
+		_, _, err = db.FindIssueBySlackPostID(ctx, "C000000001", "1700000000.000001")
+		require.NoError(t, err)
+
+		issue.PostID = "1700000000.000002"
+		require.NoError(t, db.SaveIssues(ctx, issue))
+
+		_, body, err = db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, "1700000000.000002", testIssueFromJSON(t, body).PostID)
+
+		// The lookup by the previous post ID is invalidated by the issue ID
+		id, body, err = db.FindIssueBySlackPostID(ctx, "C000000001", "1700000000.000001")
+		require.NoError(t, err)
+		assert.Empty(t, id)
+		assert.Nil(t, body)
+
+		assert.Equal(t, 3, inner.Calls("FindOpenIssueByCorrelationID"))
+		assert.Equal(t, 2, inner.Calls("FindIssueBySlackPostID"))
+	})
+
+	t.Run("versioned issue lookups should be cached and invalidated when the issue is saved", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		inner, db := newCachingDB(t, nil, nil)
+
+		versioned, ok := db.(types.VersionedDB)
+		require.True(t, ok, "caching db should implement VersionedDB if the wrapped db does")
+
+		issue := newTestIssue("issue-1", "C000000001", "corr-1", "1700000000.000001")
+		require.NoError(t, db.SaveIssue(ctx, issue))
+
+		// Unversioned and versioned lookups are cached separately
+		_, _, err := db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+
+		for range 2 {
+			stored, err := versioned.FindOpenVersionedIssueByCorrelationID(ctx, "C000000001", "corr-1")
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+			assert.Equal(t, "issue-1", stored.ID)
+			assert.Equal(t, int64(1), stored.Version)
+		}
+
+		assert.Equal(t, 1, inner.Calls("FindOpenVersionedIssueByCorrelationID"))
+
+		stored, err := versioned.FindVersionedIssueBySlackPostID(ctx, "C000000001", "1700000000.000001")
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+
+		// Modifying the returned issue must not modify the cached issue
+		stored.Body[0] = 'x'
+
+		require.NoError(t, db.SaveIssue(ctx, &versionedTestIssue{testIssue: issue, version: 1}))
+
+		stored, err = versioned.FindOpenVersionedIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, int64(2), stored.Version)
+		assert.True(t, json.Valid(stored.Body))
+
+		stored, err = versioned.FindVersionedIssueBySlackPostID(ctx, "C000000001", "1700000000.000001")
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, int64(2), stored.Version)
+
+		assert.Equal(t, 2, inner.Calls("FindOpenVersionedIssueByCorrelationID"))
+		assert.Equal(t, 2, inner.Calls("FindVersionedIssueBySlackPostID"))
+
+		issues, err := versioned.LoadOpenVersionedIssuesInChannel(ctx, "C000000001")
+		require.NoError(t, err)
+		require.Contains(t, issues, "issue-1")
+		assert.Equal(t, int64(2), issues["issue-1"].Version)
+	})
+
+	t.Run("caching db should not implement VersionedDB if the wrapped db does not", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := types.WithCache(unversionedDB{types.NewInMemoryDB()}, nil, nil)
+		require.NoError(t, err)
+
+		_, ok := db.(types.VersionedDB)
+		assert.False(t, ok)
+	})
+
+	t.Run("issue lookups should be invalidated when the issue is moved", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		_, db := newCachingDB(t, nil, nil)
+
+		issue := newTestIssue("issue-1", "C000000001", "corr-1", "")
+		require.NoError(t, db.SaveIssue(ctx, issue))
+
+		id, _, err := db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, "issue-1", id)
+
+		id, _, err = db.FindOpenIssueByCorrelationID(ctx, "C000000002", "corr-1")
+		require.NoError(t, err)
+		assert.Empty(t, id)
+
+		issue.Channel = "C000000002"
+		require.NoError(t, db.MoveIssue(ctx, issue, "C000000001", "C000000002"))
+
+		id, _, err = db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Empty(t, id)
+
+		id, _, err = db.FindOpenIssueByCorrelationID(ctx, "C000000002", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, "issue-1", id)
+	})
+
+	t.Run("move mapping lookups should be cached and invalidated", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		metrics := &recordingMetrics{}
+		inner, db := newCachingDB(t, metrics, nil)
+
+		body, err := db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Nil(t, body)
+
+		body, err = db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Nil(t, body)
+		assert.Equal(t, 1, inner.Calls("FindMoveMapping"))
+
+		require.NoError(t, db.SaveMoveMapping(ctx, &testMoveMapping{Channel: "C000000001", CorrelationID: "corr-1"}))
+
+		body, err = db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.NotNil(t, body)
+
+		// Modifying the returned body must not modify the cached body
+		body[0] = 'x'
+
+		body, err = db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.True(t, json.Valid(body))
+
+		require.NoError(t, db.DeleteMoveMapping(ctx, "C000000001", "corr-1"))
+
+		body, err = db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Nil(t, body)
+
+		assert.Equal(t, 3, inner.Calls("FindMoveMapping"))
+		assert.InDelta(t, 2, metrics.counter(types.DBCacheHitsMetric, "move_mapping"), 0)
+		assert.InDelta(t, 3, metrics.counter(types.DBCacheMissesMetric, "move_mapping"), 0)
+	})
+
+	t.Run("channel processing state lookups should be cached and invalidated", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		metrics := &recordingMetrics{}
+		inner, db := newCachingDB(t, metrics, nil)
+
+		state := types.NewChannelProcessingState("C000000001")
+		state.OpenIssues = 1
+		require.NoError(t, db.SaveChannelProcessingState(ctx, state))
+
+		found, err := db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		found.OpenIssues = 100
+
+		found, err = db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.Equal(t, 1, found.OpenIssues, "cached state should not be modified through returned copies")
+		assert.Equal(t, 1, inner.Calls("FindChannelProcessingState"))
+
+		state.OpenIssues = 2
+		require.NoError(t, db.SaveChannelProcessingState(ctx, state))
+
+		found, err = db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.Equal(t, 2, found.OpenIssues)
+		assert.Equal(t, 2, inner.Calls("FindChannelProcessingState"))
+		assert.InDelta(t, 1, metrics.counter(types.DBCacheHitsMetric, "channel_processing_state"), 0)
+	})
+
+	t.Run("drop all data should purge the caches", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		_, db := newCachingDB(t, nil, nil)
+
+		require.NoError(t, db.SaveIssue(ctx, newTestIssue("issue-1", "C000000001", "corr-1", "")))
+		require.NoError(t, db.SaveChannelProcessingState(ctx, types.NewChannelProcessingState("C000000001")))
+
+		id, _, err := db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, "issue-1", id)
+
+		state, err := db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.NotNil(t, state)
+
+		require.NoError(t, db.DropAllData(ctx))
+
+		id, _, err = db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Empty(t, id)
+
+		state, err = db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.Nil(t, state)
+	})
+
+	t.Run("errors should not be cached", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		inner, db := newCachingDB(t, nil, nil)
+		require.NoError(t, inner.AddFault(types.FaultRule{Method: "FindMoveMapping", Call: 1, Err: errDBUnavailable}))
+
+		_, err := db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.ErrorIs(t, err, errDBUnavailable)
+
+		_, err = db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, inner.Calls("FindMoveMapping"))
+	})
+
+	t.Run("entries should expire after the ttl", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		opts := types.DefaultDBCacheOptions()
+		opts.MoveMappingTTL = 20 * time.Millisecond
+		inner, db := newCachingDB(t, nil, opts)
+
+		_, err := db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		_, err = db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, inner.Calls("FindMoveMapping"))
+
+		time.Sleep(30 * time.Millisecond)
+
+		_, err = db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, inner.Calls("FindMoveMapping"))
+	})
+
+	t.Run("least recently used entries should be evicted", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		opts := types.DefaultDBCacheOptions()
+		opts.MoveMappingCacheSize = 2
+		inner, db := newCachingDB(t, nil, opts)
+
+		find := func(correlationID string) {
+			_, err := db.FindMoveMapping(ctx, "C000000001", correlationID)
+			require.NoError(t, err)
+		}
+
+		find("corr-1")
+		find("corr-2")
+		find("corr-1")
+		find("corr-3") // Evicts corr-2
+		assert.Equal(t, 3, inner.Calls("FindMoveMapping"))
+
+		find("corr-1")
+		assert.Equal(t, 3, inner.Calls("FindMoveMapping"))
+
+		find("corr-2")
+		assert.Equal(t, 4, inner.Calls("FindMoveMapping"))
+	})
+
+	t.Run("disabled caches should pass through", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		metrics := &recordingMetrics{}
+		opts := &types.DBCacheOptions{}
+		inner, db := newCachingDB(t, metrics, opts)
+
+		for range 2 {
+			_, _, err := db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, inner.Calls("FindOpenIssueByCorrelationID"))
+		assert.Zero(t, metrics.counter(types.DBCacheMissesMetric, "issue"))
+	})
+}
+
+// newCachingDB returns a caching DB, and the FaultyDB it wraps (to count and fail the calls to the underlying DB).
+func newCachingDB(t *testing.T, metrics types.Metrics, opts *types.DBCacheOptions) (*types.FaultyDB, types.DB) {
+	t.Helper()
+
+	inner := newFaultyDB(t, nil)
+
+	db, err := types.WithCache(inner.DB(), metrics, opts)
+	require.NoError(t, err)
+
+	return inner, db
+}
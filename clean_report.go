@@ -0,0 +1,127 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CleanChangeReason is a machine-readable reason for a change made by Alert.Clean.
+type CleanChangeReason string
+
+const (
+	// CleanChangeTruncated indicates that a value was truncated, because it exceeded its maximum length.
+	CleanChangeTruncated CleanChangeReason = "truncated"
+
+	// CleanChangeNormalized indicates that a value was rewritten to its canonical form,
+	// such as the severity 'critical' being rewritten to 'error'.
+	CleanChangeNormalized CleanChangeReason = "normalized"
+
+	// CleanChangeDefaultApplied indicates that an empty or out-of-range value was replaced with a default value.
+	CleanChangeDefaultApplied CleanChangeReason = "default_applied"
+
+	// CleanChangeTimestampReplaced indicates that a missing or stale timestamp was replaced with the current time.
+	CleanChangeTimestampReplaced CleanChangeReason = "timestamp_replaced"
+
+	// CleanChangeReordered indicates that a list was reordered, such as escalation points being sorted by delay.
+	CleanChangeReordered CleanChangeReason = "reordered"
+)
+
+// CleanChange describes a single change made by Alert.Clean.
+//
+// Whitespace trimming, newline replacement and case normalization are not reported, since they
+// do not change the meaning of the alert.
+type CleanChange struct {
+	// Field is a JSON pointer (RFC 6901) to the changed value, based on the JSON field names,
+	// such as '/fields/2/value'.
+	Field string `json:"field"`
+
+	// Reason is the machine-readable reason for the change.
+	Reason CleanChangeReason `json:"reason"`
+
+	// OldValue is the value before the change. It is empty for truncations, where OldLength is set instead.
+	OldValue string `json:"oldValue,omitempty"`
+
+	// NewValue is the value after the change. It is empty for truncations, where NewLength is set instead.
+	NewValue string `json:"newValue,omitempty"`
+
+	// OldLength is the length (in characters) of the value before truncation.
+	OldLength int `json:"oldLength,omitempty"`
+
+	// NewLength is the length (in characters) of the value after truncation.
+	NewLength int `json:"newLength,omitempty"`
+}
+
+// CleanWithReport cleans the alert like Clean, and returns a list of the changes that were made.
+// The returned list is empty if no changes were made.
+func (a *Alert) CleanWithReport() []*CleanChange {
+	return a.CleanWithPolicyAndReport(DefaultValidationPolicy())
+}
+
+// CleanWithPolicyAndReport cleans the alert like CleanWithPolicy, and returns a list of the changes that were made.
+// The default policy is used if p is nil. The returned list is empty if no changes were made.
+func (a *Alert) CleanWithPolicyAndReport(p *ValidationPolicy) []*CleanChange {
+	r := &cleanRecorder{changes: []*CleanChange{}}
+	a.clean(p, r)
+	return r.changes
+}
+
+// cleanRecorder records the changes made by Alert.clean. A nil recorder records nothing.
+type cleanRecorder struct {
+	changes []*CleanChange
+}
+
+func (r *cleanRecorder) add(change *CleanChange) {
+	if r == nil {
+		return
+	}
+
+	r.changes = append(r.changes, change)
+}
+
+// valueChanged records a change from oldValue to newValue, if they are different.
+func (r *cleanRecorder) valueChanged(field string, reason CleanChangeReason, oldValue, newValue string) {
+	if oldValue == newValue {
+		return
+	}
+
+	r.add(&CleanChange{Field: field, Reason: reason, OldValue: oldValue, NewValue: newValue})
+}
+
+// truncate truncates s with truncateStringIfNeeded, and records the truncation (if any).
+func (r *cleanRecorder) truncate(field, s string, maxLen int) string {
+	return r.recordTruncation(field, s, truncateStringIfNeeded(s, maxLen))
+}
+
+// shortenText truncates s with shortenAlertTextIfNeeded, and records the truncation (if any).
+func (r *cleanRecorder) shortenText(field, s string, maxLen int) string {
+	return r.recordTruncation(field, s, shortenAlertTextIfNeeded(s, maxLen))
+}
+
+func (r *cleanRecorder) recordTruncation(field, before, after string) string {
+	if before != after {
+		r.add(&CleanChange{
+			Field:     field,
+			Reason:    CleanChangeTruncated,
+			OldLength: utf8.RuneCountInString(before),
+			NewLength: utf8.RuneCountInString(after),
+		})
+	}
+
+	return after
+}
+
+// escalationDelays returns a string representation of the escalation delays, for use in change reports.
+func escalationDelays(escalation []*Escalation) string {
+	delays := make([]string, len(escalation))
+
+	for i, e := range escalation {
+		if e == nil {
+			delays[i] = "null"
+		} else {
+			delays[i] = strconv.Itoa(e.DelaySeconds)
+		}
+	}
+
+	return "[" + strings.Join(delays, ",") + "]"
+}
@@ -0,0 +1,131 @@
+package types_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertCleanWithReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clean alert should produce an empty report", func(t *testing.T) {
+		t.Parallel()
+
+		a := types.NewErrorAlert()
+		a.Header = "  header  "
+		a.SlackChannelID = "c12345678"
+
+		changes := a.CleanWithReport()
+		require.NotNil(t, changes)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("all reported changes", func(t *testing.T) {
+		t.Parallel()
+
+		a := &types.Alert{
+			Timestamp:                time.Now().Add(-8 * 24 * time.Hour),
+			Header:                   strings.Repeat("h", types.MaxHeaderLength+5),
+			Text:                     strings.Repeat("t", types.MaxTextLength+1),
+			Footer:                   strings.Repeat("f", types.MaxFooterLength+1),
+			Severity:                 " Critical ",
+			NotificationDelaySeconds: -5,
+			Fields: []*types.Field{
+				{Title: "ok", Value: "ok"},
+				{Title: "ok", Value: strings.Repeat("v", types.MaxFieldValueLength+1)},
+			},
+			Webhooks: []*types.Webhook{
+				{ID: "a", ButtonStyle: "default"},
+			},
+			Escalation: []*types.Escalation{
+				{DelaySeconds: 120},
+				{DelaySeconds: 60},
+			},
+		}
+
+		changes := a.CleanWithReport()
+
+		type result struct {
+			field  string
+			reason types.CleanChangeReason
+		}
+
+		results := make([]result, len(changes))
+		for i, c := range changes {
+			results[i] = result{field: c.Field, reason: c.Reason}
+		}
+
+		assert.Equal(t, []result{
+			{"/timestamp", types.CleanChangeTimestampReplaced},
+			{"/header", types.CleanChangeTruncated},
+			{"/text", types.CleanChangeTruncated},
+			{"/footer", types.CleanChangeTruncated},
+			{"/severity", types.CleanChangeNormalized},
+			{"/notificationDelaySeconds", types.CleanChangeDefaultApplied},
+			{"/fields/1/value", types.CleanChangeTruncated},
+			{"/webhooks/0/buttonStyle", types.CleanChangeNormalized},
+			{"/escalation", types.CleanChangeReordered},
+		}, results)
+
+		assert.Equal(t, types.MaxHeaderLength+5, changes[1].OldLength)
+		assert.Equal(t, types.MaxHeaderLength, changes[1].NewLength)
+		assert.Empty(t, changes[1].OldValue)
+		assert.Equal(t, "critical", changes[4].OldValue)
+		assert.Equal(t, "error", changes[4].NewValue)
+		assert.Equal(t, "-5", changes[5].OldValue)
+		assert.Equal(t, "0", changes[5].NewValue)
+		assert.Equal(t, "[120,60]", changes[8].OldValue)
+		assert.Equal(t, "[60,120]", changes[8].NewValue)
+	})
+
+	t.Run("empty and aliased severities", func(t *testing.T) {
+		t.Parallel()
+
+		a := &types.Alert{Header: "foo"}
+		changes := a.CleanWithReport()
+		require.Len(t, changes, 2)
+		assert.Equal(t, "/timestamp", changes[0].Field)
+		assert.Equal(t, types.CleanChangeDefaultApplied, changes[1].Reason)
+		assert.Equal(t, "error", changes[1].NewValue)
+
+		a = types.NewAlert("recover")
+		changes = a.CleanWithReport()
+		require.Len(t, changes, 1)
+		assert.Equal(t, types.CleanChangeNormalized, changes[0].Reason)
+		assert.Equal(t, "recover", changes[0].OldValue)
+		assert.Equal(t, "resolved", changes[0].NewValue)
+	})
+
+	t.Run("report should use policy limits", func(t *testing.T) {
+		t.Parallel()
+
+		p := types.DefaultValidationPolicy()
+		p.MaxTextLength = 3000
+
+		a := types.NewErrorAlert()
+		a.Text = strings.Repeat("t", 3001)
+
+		changes := a.CleanWithPolicyAndReport(p)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "/text", changes[0].Field)
+		assert.Equal(t, 3001, changes[0].OldLength)
+		assert.Equal(t, 3000, changes[0].NewLength)
+	})
+
+	t.Run("report should marshal to JSON", func(t *testing.T) {
+		t.Parallel()
+
+		a := types.NewErrorAlert()
+		a.Author = strings.Repeat("a", types.MaxAuthorLength+1)
+
+		body, err := json.Marshal(a.CleanWithReport())
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"field":"/author","reason":"truncated","oldLength":101,"newLength":100}]`, string(body))
+	})
+}
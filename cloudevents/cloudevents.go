@@ -0,0 +1,219 @@
+// Package cloudevents encodes Slack Manager alerts and webhook callbacks as CloudEvents v1.0,
+// in structured JSON mode and HTTP binary mode.
+//
+// The package is implemented on the standard library only.
+//
+// Example:
+//
+//	event, err := cloudevents.ToCloudEvent(alert)
+//	if err != nil {
+//		// handle error
+//	}
+//
+//	req, err := cloudevents.NewHTTPRequest(ctx, "https://events.example.com", event, cloudevents.ModeBinary)
+package cloudevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"time"
+
+	"github.com/slackmgr/types"
+)
+
+const (
+	// SpecVersion is the CloudEvents specification version implemented by this package.
+	SpecVersion = "1.0"
+
+	// DefaultSource is the default event source.
+	DefaultSource = "/slackmgr"
+
+	// TypeAlert is the event type for alerts.
+	TypeAlert = "io.slackmgr.alert.v1"
+
+	// TypeWebhookCallback is the event type for webhook callbacks.
+	TypeWebhookCallback = "io.slackmgr.webhook_callback.v1"
+
+	// ContentTypeJSON is the content type of the event data.
+	ContentTypeJSON = "application/json"
+
+	// ContentTypeCloudEventsJSON is the content type of events in structured JSON mode.
+	ContentTypeCloudEventsJSON = "application/cloudevents+json"
+)
+
+// Event is a CloudEvents v1.0 event with JSON data.
+// Marshaling an Event with encoding/json produces the structured JSON mode representation of the event.
+type Event struct {
+	// SpecVersion is the CloudEvents specification version, which must be "1.0".
+	SpecVersion string `json:"specversion"`
+
+	// ID identifies the event. It is unique per source.
+	ID string `json:"id"`
+
+	// Source identifies the context in which the event happened.
+	Source string `json:"source"`
+
+	// Type is the type of the event, such as TypeAlert.
+	Type string `json:"type"`
+
+	// Subject is the subject of the event in the context of the source, such as the Slack channel ID.
+	Subject string `json:"subject,omitempty"`
+
+	// Time is the time when the event happened.
+	Time time.Time `json:"time,omitzero"`
+
+	// DataContentType is the content type of the data, which must be JSON.
+	DataContentType string `json:"datacontenttype,omitempty"`
+
+	// Data is the JSON encoded event data.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Validate returns an error if any of the required attributes are missing, or if the data is not JSON.
+func (e *Event) Validate() error {
+	if e == nil {
+		return errors.New("event is nil")
+	}
+
+	if e.SpecVersion != SpecVersion {
+		return fmt.Errorf("specversion '%s' is not supported, expected '%s'", e.SpecVersion, SpecVersion)
+	}
+
+	if e.ID == "" {
+		return errors.New("id is required")
+	}
+
+	if e.Source == "" {
+		return errors.New("source is required")
+	}
+
+	if e.Type == "" {
+		return errors.New("type is required")
+	}
+
+	if e.DataContentType != "" && !isJSONContentType(e.DataContentType) {
+		return fmt.Errorf("datacontenttype '%s' is not supported, expected JSON", e.DataContentType)
+	}
+
+	return nil
+}
+
+// Unmarshal decodes and validates an event in structured JSON mode.
+// Extension attributes are ignored.
+func Unmarshal(body []byte) (*Event, error) {
+	var e Event
+
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("failed to decode cloud event: %w", err)
+	}
+
+	if err := e.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cloud event: %w", err)
+	}
+
+	return &e, nil
+}
+
+// ToCloudEvent returns a CloudEvent for the alert, with type TypeAlert, id set to Alert.UniqueID()
+// and subject set to the Slack channel ID (or the route key, if the channel ID is empty).
+// The source is DefaultSource, and can be changed on the returned event.
+func ToCloudEvent(alert *types.Alert) (*Event, error) {
+	if alert == nil {
+		return nil, errors.New("alert is nil")
+	}
+
+	subject := alert.SlackChannelID
+	if subject == "" {
+		subject = alert.RouteKey
+	}
+
+	return newEvent(TypeAlert, alert.UniqueID(), subject, alert.Timestamp, alert)
+}
+
+// AlertFromCloudEvent returns the alert in a CloudEvent with type TypeAlert.
+func AlertFromCloudEvent(e *Event) (*types.Alert, error) {
+	var alert types.Alert
+
+	if err := decodeData(e, TypeAlert, &alert); err != nil {
+		return nil, err
+	}
+
+	return &alert, nil
+}
+
+// WebhookCallbackToCloudEvent returns a CloudEvent for the webhook callback, with type TypeWebhookCallback,
+// id set to WebhookCallback.UniqueID() and subject set to the Slack channel ID.
+// The source is DefaultSource, and can be changed on the returned event.
+func WebhookCallbackToCloudEvent(callback *types.WebhookCallback) (*Event, error) {
+	if callback == nil {
+		return nil, errors.New("webhook callback is nil")
+	}
+
+	return newEvent(TypeWebhookCallback, callback.UniqueID(), callback.ChannelID, callback.Timestamp, callback)
+}
+
+// WebhookCallbackFromCloudEvent returns the webhook callback in a CloudEvent with type TypeWebhookCallback.
+func WebhookCallbackFromCloudEvent(e *Event) (*types.WebhookCallback, error) {
+	var callback types.WebhookCallback
+
+	if err := decodeData(e, TypeWebhookCallback, &callback); err != nil {
+		return nil, err
+	}
+
+	return &callback, nil
+}
+
+func newEvent(eventType, id, subject string, t time.Time, data any) (*Event, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cloud event data: %w", err)
+	}
+
+	e := &Event{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          DefaultSource,
+		Type:            eventType,
+		Subject:         subject,
+		DataContentType: ContentTypeJSON,
+		Data:            body,
+	}
+
+	if !t.IsZero() {
+		e.Time = t.UTC()
+	}
+
+	return e, nil
+}
+
+func decodeData(e *Event, eventType string, v any) error {
+	if err := e.Validate(); err != nil {
+		return fmt.Errorf("invalid cloud event: %w", err)
+	}
+
+	if e.Type != eventType {
+		return fmt.Errorf("cloud event type '%s' is not valid, expected '%s'", e.Type, eventType)
+	}
+
+	if len(e.Data) == 0 {
+		return errors.New("cloud event data is empty")
+	}
+
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("failed to decode cloud event data: %w", err)
+	}
+
+	return nil
+}
+
+// isJSONContentType returns true if the content type is application/json, or any other media type with a +json suffix.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == ContentTypeJSON || (len(mediaType) > 5 && mediaType[len(mediaType)-5:] == "+json")
+}
@@ -0,0 +1,126 @@
+package cloudevents_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/cloudevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAlert() *types.Alert {
+	return &types.Alert{
+		Timestamp:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		CorrelationID:  "abc",
+		Header:         "Disk usage above 90%",
+		Severity:       types.AlertError,
+		SlackChannelID: "C12345678",
+		Fields:         []*types.Field{{Title: "Host", Value: "db-1"}},
+		Metadata:       map[string]any{"team": "storage"},
+	}
+}
+
+func TestAlertCloudEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("alert should round-trip in structured mode", func(t *testing.T) {
+		t.Parallel()
+
+		a := newAlert()
+
+		e, err := cloudevents.ToCloudEvent(a)
+		require.NoError(t, err)
+		assert.Equal(t, cloudevents.SpecVersion, e.SpecVersion)
+		assert.Equal(t, cloudevents.TypeAlert, e.Type)
+		assert.Equal(t, cloudevents.DefaultSource, e.Source)
+		assert.Equal(t, a.UniqueID(), e.ID)
+		assert.Equal(t, "C12345678", e.Subject)
+		assert.Equal(t, a.Timestamp, e.Time)
+		assert.Equal(t, cloudevents.ContentTypeJSON, e.DataContentType)
+
+		body, err := json.Marshal(e)
+		require.NoError(t, err)
+
+		var attributes map[string]any
+		require.NoError(t, json.Unmarshal(body, &attributes))
+		assert.Equal(t, "1.0", attributes["specversion"])
+		assert.Equal(t, "2026-01-02T03:04:05Z", attributes["time"])
+		assert.IsType(t, map[string]any{}, attributes["data"])
+
+		decoded, err := cloudevents.Unmarshal(body)
+		require.NoError(t, err)
+
+		b, err := cloudevents.AlertFromCloudEvent(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("route key should be used as subject if channel is empty", func(t *testing.T) {
+		t.Parallel()
+
+		a := newAlert()
+		a.SlackChannelID = ""
+		a.RouteKey = "storage"
+
+		e, err := cloudevents.ToCloudEvent(a)
+		require.NoError(t, err)
+		assert.Equal(t, "storage", e.Subject)
+	})
+
+	t.Run("invalid events should return an error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := cloudevents.ToCloudEvent(nil)
+		require.Error(t, err)
+
+		_, err = cloudevents.Unmarshal([]byte(`{"specversion":"0.3","id":"1","source":"/x","type":"y"}`))
+		require.ErrorContains(t, err, "specversion '0.3' is not supported")
+
+		_, err = cloudevents.Unmarshal([]byte(`{"specversion":"1.0","source":"/x","type":"y"}`))
+		require.ErrorContains(t, err, "id is required")
+
+		_, err = cloudevents.Unmarshal([]byte(`{"specversion":"1.0","id":"1","source":"/x","type":"y","datacontenttype":"text/plain"}`))
+		require.ErrorContains(t, err, "datacontenttype 'text/plain' is not supported")
+
+		e, err := cloudevents.ToCloudEvent(newAlert())
+		require.NoError(t, err)
+		_, err = cloudevents.WebhookCallbackFromCloudEvent(e)
+		require.ErrorContains(t, err, "type 'io.slackmgr.alert.v1' is not valid")
+
+		e.Data = nil
+		_, err = cloudevents.AlertFromCloudEvent(e)
+		require.ErrorContains(t, err, "data is empty")
+	})
+}
+
+func TestWebhookCallbackCloudEvent(t *testing.T) {
+	t.Parallel()
+
+	cb := &types.WebhookCallback{
+		ID:            "restart",
+		UserID:        "U12345",
+		UserRealName:  "Jane Doe",
+		ChannelID:     "C12345678",
+		MessageID:     "1767323045.000100",
+		Timestamp:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Input:         map[string]string{"reason": "disk full"},
+		CheckboxInput: map[string][]string{"opts": {"force"}},
+		Payload:       map[string]any{"action": "restart"},
+	}
+
+	e, err := cloudevents.WebhookCallbackToCloudEvent(cb)
+	require.NoError(t, err)
+	assert.Equal(t, cloudevents.TypeWebhookCallback, e.Type)
+	assert.Equal(t, cb.UniqueID(), e.ID)
+	assert.Equal(t, "C12345678", e.Subject)
+
+	decoded, err := cloudevents.WebhookCallbackFromCloudEvent(e)
+	require.NoError(t, err)
+	assert.Equal(t, cb, decoded)
+
+	_, err = cloudevents.WebhookCallbackToCloudEvent(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Mode is the CloudEvents HTTP content mode.
+type Mode string
+
+const (
+	// ModeStructured sends the entire event as a JSON document in the HTTP body.
+	ModeStructured Mode = "structured"
+
+	// ModeBinary sends the event attributes as ce-* HTTP headers, and the event data as the HTTP body.
+	ModeBinary Mode = "binary"
+)
+
+// headerPrefix is the prefix of event attribute headers in binary mode.
+const headerPrefix = "Ce-"
+
+// MaxBodySize is the maximum size of an HTTP request body read by FromHTTPRequest.
+const MaxBodySize = 1 << 20
+
+// NewHTTPRequest returns a new HTTP POST request for the event, in the specified content mode.
+func NewHTTPRequest(ctx context.Context, targetURL string, e *Event, mode Mode) (*http.Request, error) {
+	if err := e.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cloud event: %w", err)
+	}
+
+	var body []byte
+	var contentType string
+
+	switch mode {
+	case ModeStructured:
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cloud event: %w", err)
+		}
+
+		body = b
+		contentType = ContentTypeCloudEventsJSON
+	case ModeBinary:
+		body = e.Data
+		contentType = e.DataContentType
+	default:
+		return nil, fmt.Errorf("cloud event mode '%s' is not valid", mode)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if mode == ModeBinary {
+		writeBinaryHeaders(req.Header, e)
+	}
+
+	return req, nil
+}
+
+// FromHTTPRequest reads and validates an event from an HTTP request, in either structured or binary content mode.
+// Requests with content type application/cloudevents+json are read in structured mode, and all other requests in binary mode.
+// At most MaxBodySize bytes are read from the request body.
+func FromHTTPRequest(r *http.Request) (*Event, error) {
+	if r == nil || r.Body == nil {
+		return nil, errors.New("request body is empty")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	if len(body) > MaxBodySize {
+		return nil, fmt.Errorf("request body is larger than %d bytes", MaxBodySize)
+	}
+
+	contentType := r.Header.Get("Content-Type")
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == ContentTypeCloudEventsJSON {
+		return Unmarshal(body)
+	}
+
+	return readBinary(r.Header, contentType, body)
+}
+
+func writeBinaryHeaders(h http.Header, e *Event) {
+	h.Set(headerPrefix+"Specversion", encodeHeaderValue(e.SpecVersion))
+	h.Set(headerPrefix+"Id", encodeHeaderValue(e.ID))
+	h.Set(headerPrefix+"Source", encodeHeaderValue(e.Source))
+	h.Set(headerPrefix+"Type", encodeHeaderValue(e.Type))
+
+	if e.Subject != "" {
+		h.Set(headerPrefix+"Subject", encodeHeaderValue(e.Subject))
+	}
+
+	if !e.Time.IsZero() {
+		h.Set(headerPrefix+"Time", e.Time.Format(time.RFC3339Nano))
+	}
+}
+
+func readBinary(h http.Header, contentType string, body []byte) (*Event, error) {
+	attr := func(name string) (string, error) {
+		value, err := url.PathUnescape(h.Get(headerPrefix + name))
+		if err != nil {
+			return "", fmt.Errorf("failed to decode ce-%s header: %w", strings.ToLower(name), err)
+		}
+
+		return value, nil
+	}
+
+	e := &Event{DataContentType: contentType}
+
+	if len(body) > 0 {
+		e.Data = body
+	}
+
+	for name, dst := range map[string]*string{
+		"Specversion": &e.SpecVersion,
+		"Id":          &e.ID,
+		"Source":      &e.Source,
+		"Type":        &e.Type,
+		"Subject":     &e.Subject,
+	} {
+		value, err := attr(name)
+		if err != nil {
+			return nil, err
+		}
+
+		*dst = value
+	}
+
+	if t := h.Get(headerPrefix + "Time"); t != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ce-time header: %w", err)
+		}
+
+		e.Time = parsed
+	}
+
+	if err := e.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cloud event: %w", err)
+	}
+
+	return e, nil
+}
+
+// encodeHeaderValue percent-encodes a header value, as required by the CloudEvents HTTP binding:
+// space, double quote, percent and any characters outside the printable ASCII range are encoded.
+func encodeHeaderValue(s string) string {
+	var b strings.Builder
+
+	for i := range len(s) {
+		c := s[i]
+
+		if c <= ' ' || c >= 0x7f || c == '"' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
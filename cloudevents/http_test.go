@@ -0,0 +1,113 @@
+package cloudevents_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/slackmgr/types/cloudevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("binary mode", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := cloudevents.ToCloudEvent(newAlert())
+		require.NoError(t, err)
+		e.Subject = "my channel/100%"
+
+		req, err := cloudevents.NewHTTPRequest(t.Context(), "https://example.com/events", e, cloudevents.ModeBinary)
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, cloudevents.ContentTypeJSON, req.Header.Get("Content-Type"))
+		assert.Equal(t, "1.0", req.Header.Get("ce-specversion"))
+		assert.Equal(t, cloudevents.TypeAlert, req.Header.Get("ce-type"))
+		assert.Equal(t, "my%20channel/100%25", req.Header.Get("ce-subject"))
+		assert.Equal(t, "2026-01-02T03:04:05Z", req.Header.Get("ce-time"))
+
+		bodyReader, err := req.GetBody()
+		require.NoError(t, err)
+		body, err := io.ReadAll(bodyReader)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(e.Data), string(body))
+
+		decoded, err := cloudevents.FromHTTPRequest(roundTrip(t, req))
+		require.NoError(t, err)
+		assert.Equal(t, e, decoded)
+	})
+
+	t.Run("structured mode", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := cloudevents.ToCloudEvent(newAlert())
+		require.NoError(t, err)
+
+		req, err := cloudevents.NewHTTPRequest(t.Context(), "https://example.com/events", e, cloudevents.ModeStructured)
+		require.NoError(t, err)
+		assert.Equal(t, cloudevents.ContentTypeCloudEventsJSON, req.Header.Get("Content-Type"))
+		assert.Empty(t, req.Header.Get("ce-id"))
+
+		decoded, err := cloudevents.FromHTTPRequest(roundTrip(t, req))
+		require.NoError(t, err)
+
+		a, err := cloudevents.AlertFromCloudEvent(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, newAlert(), a)
+	})
+
+	t.Run("invalid requests should return an error", func(t *testing.T) {
+		t.Parallel()
+
+		e, err := cloudevents.ToCloudEvent(newAlert())
+		require.NoError(t, err)
+
+		_, err = cloudevents.NewHTTPRequest(t.Context(), "https://example.com", e, "foo")
+		require.ErrorContains(t, err, "mode 'foo' is not valid")
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		_, err = cloudevents.FromHTTPRequest(req)
+		require.ErrorContains(t, err, "specversion")
+
+		req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		req.Header.Set("ce-specversion", "1.0")
+		req.Header.Set("ce-id", "1")
+		req.Header.Set("ce-source", "/x")
+		req.Header.Set("ce-type", "y")
+		req.Header.Set("ce-time", "yesterday")
+		_, err = cloudevents.FromHTTPRequest(req)
+		require.ErrorContains(t, err, "ce-time")
+	})
+}
+
+// roundTrip sends the request to a test server, and returns the request as received by the server.
+func roundTrip(t *testing.T, req *http.Request) *http.Request {
+	t.Helper()
+
+	received := make(chan *http.Request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		received <- r
+	}))
+	defer server.Close()
+
+	outgoing, err := http.NewRequestWithContext(t.Context(), req.Method, server.URL, req.Body)
+	require.NoError(t, err)
+	outgoing.Header = req.Header
+
+	resp, err := http.DefaultClient.Do(outgoing)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	return <-received
+}
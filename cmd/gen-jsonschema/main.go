@@ -0,0 +1,52 @@
+// Command gen-jsonschema writes the JSON Schemas for the Alert and WebhookCallback wire formats to disk.
+//
+// Usage:
+//
+//	go run ./cmd/gen-jsonschema -out schema
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/slackmgr/types"
+)
+
+func main() {
+	out := flag.String("out", "schema", "output directory")
+	flag.Parse()
+
+	if err := run(*out); err != nil {
+		fmt.Fprintf(os.Stderr, "gen-jsonschema: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string) error {
+	schemas := []struct {
+		filename string
+		generate func() ([]byte, error)
+	}{
+		{"alert.schema.json", types.AlertJSONSchema},
+		{"webhook_callback.schema.json", types.WebhookCallbackJSONSchema},
+	}
+
+	if err := os.MkdirAll(out, 0o750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for _, s := range schemas {
+		body, err := s.generate()
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(out, s.filename), body, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", s.filename, err)
+		}
+	}
+
+	return nil
+}
@@ -3,8 +3,51 @@ package types
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 )
 
+// ErrConflict is matched (with errors.Is) by the *ConflictError returned when a VersionedIssue is written with a
+// version that does not match the version of the stored issue.
+var ErrConflict = errors.New("issue version conflict")
+
+// ConflictError is returned by the SaveIssue, SaveIssues and MoveIssue methods of a VersionedDB when the version of a
+// VersionedIssue does not match the version of the stored issue, i.e. when the issue has been written by someone else
+// since it was loaded. The caller should reload the issue and re-apply its changes.
+type ConflictError struct {
+	// IssueID is the unique ID of the conflicting issue.
+	IssueID string
+
+	// ExpectedVersion is the version of the issue that was written (see VersionedIssue.Version).
+	ExpectedVersion int64
+
+	// CurrentVersion is the version of the stored issue, or 0 if the issue does not exist.
+	CurrentVersion int64
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("issue %s has version %d, expected version %d: %s", e.IssueID, e.CurrentVersion, e.ExpectedVersion, ErrConflict)
+}
+
+// Is returns true if target is ErrConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// StoredIssue is an issue as stored in a VersionedDB.
+type StoredIssue struct {
+	// ID is the unique ID of the issue (see Issue.UniqueID).
+	ID string
+
+	// Body is the JSON representation of the issue.
+	Body json.RawMessage
+
+	// Version is the current version of the stored issue. It is 1 when the issue is created,
+	// and is incremented by every successful write of the issue (SaveIssue, SaveIssues and MoveIssue).
+	Version int64
+}
+
 // DB is an interface for interacting with the database.
 // It must be implemented by any database driver used by the Slack Manager.
 type DB interface {
@@ -72,3 +115,62 @@ type DB interface {
 	// It should be used with caution, as it will remove all alerts, issues, move mappings, and processing states.
 	DropAllData(ctx context.Context) error
 }
+
+// VersionedDB is an optional interface for DB implementations with optimistic concurrency control for issues.
+// Callers can check for it with a type assertion (db.(VersionedDB)), and fall back to the DB lookups if it is
+// not implemented.
+//
+// A VersionedDB stores a version with each issue, which is 1 when the issue is created, and is incremented by every
+// successful write of the issue (SaveIssue, SaveIssues and MoveIssue). Writes of a VersionedIssue are conditional:
+// the database implementation must return a *ConflictError (and not write the issue) if the version of the stored
+// issue (0 if it does not exist) does not match the version of the issue. Other issues are written unconditionally.
+// If SaveIssues returns a *ConflictError, the conflicting issue is not written, and whether the other issues are
+// written depends on the database implementation.
+type VersionedDB interface {
+	DB
+
+	// FindOpenVersionedIssueByCorrelationID is FindOpenIssueByCorrelationID, but returns the version of the issue.
+	//
+	// The database implementation should return an error if the query matches multiple issues, and [nil, nil] if no issue is found.
+	FindOpenVersionedIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (*StoredIssue, error)
+
+	// FindVersionedIssueBySlackPostID is FindIssueBySlackPostID, but returns the version of the issue.
+	//
+	// The database implementation should return an error if the query matches multiple issues, and [nil, nil] if no issue is found.
+	FindVersionedIssueBySlackPostID(ctx context.Context, channelID, postID string) (*StoredIssue, error)
+
+	// LoadOpenVersionedIssuesInChannel is LoadOpenIssuesInChannel, but returns the versions of the issues.
+	// The returned map is keyed by issue ID, and may be empty if no open issues are found in the channel.
+	LoadOpenVersionedIssuesInChannel(ctx context.Context, channelID string) (map[string]*StoredIssue, error)
+}
+
+// storedIssue returns the result of an unversioned issue lookup (see DB.FindOpenIssueByCorrelationID) as a
+// *StoredIssue without version, or nil if no issue was found.
+func storedIssue(id string, body json.RawMessage, err error) (*StoredIssue, error) {
+	if err != nil || id == "" {
+		return nil, err
+	}
+
+	return &StoredIssue{ID: id, Body: body}, nil
+}
+
+// unversionedIssue returns the result of a versioned issue lookup (see VersionedDB.FindOpenVersionedIssueByCorrelationID)
+// as an issue ID and body, or an empty ID and nil body if no issue was found.
+func unversionedIssue(issue *StoredIssue, err error) (string, json.RawMessage, error) {
+	if err != nil || issue == nil {
+		return "", nil, err
+	}
+
+	return issue.ID, issue.Body, nil
+}
+
+// unversionedIssues returns the issue bodies of a versioned issue load (see VersionedDB.LoadOpenVersionedIssuesInChannel).
+func unversionedIssues(issues map[string]*StoredIssue) map[string]json.RawMessage {
+	result := make(map[string]json.RawMessage, len(issues))
+
+	for id, issue := range issues {
+		result[id] = issue.Body
+	}
+
+	return result
+}
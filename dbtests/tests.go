@@ -613,6 +613,120 @@ func TestMoveIssue_EdgeCases(t *testing.T, client types.DB) {
 	})
 }
 
+// TestIssueVersionConflict tests optimistic concurrency control for versioned issues.
+// Every write of an issue must increment the stored version, and writes of a VersionedIssue with an outdated
+// version must fail with types.ErrConflict, without modifying the stored issue.
+// The test is skipped if the client does not implement types.VersionedDB.
+func TestIssueVersionConflict(t *testing.T, client types.DB) {
+	versioned, ok := client.(types.VersionedDB)
+	if !ok {
+		t.Skip("client does not implement types.VersionedDB")
+	}
+
+	ctx := context.Background()
+	channel1 := "C0ABABABAB"
+	channel2 := "C0ABABABAC"
+	assert := assert.New(t)
+	require := require.New(t)
+
+	corr := uuid.New().String()
+	postID := uuid.New().String()
+	issue := newTestIssue(newTestAlert(channel1, corr), postID)
+
+	// A new versioned issue is created with version 1
+	err := versioned.SaveIssue(ctx, &versionedTestIssue{testIssue: issue, version: 0})
+	require.NoError(err, "should not error when creating a versioned issue")
+
+	stored, err := versioned.FindOpenVersionedIssueByCorrelationID(ctx, channel1, corr)
+	require.NoError(err)
+	require.NotNil(stored)
+	assert.Equal(issue.ID, stored.ID)
+	assert.Equal(int64(1), stored.Version, "new issue should have version 1")
+
+	stored, err = versioned.FindVersionedIssueBySlackPostID(ctx, channel1, postID)
+	require.NoError(err)
+	require.NotNil(stored)
+	assert.Equal(int64(1), stored.Version, "FindVersionedIssueBySlackPostID should return the current version")
+
+	issues, err := versioned.LoadOpenVersionedIssuesInChannel(ctx, channel1)
+	require.NoError(err)
+	require.Contains(issues, issue.ID)
+	assert.Equal(int64(1), issues[issue.ID].Version, "LoadOpenVersionedIssuesInChannel should return the current version")
+
+	// Creating the same issue again must conflict
+	err = versioned.SaveIssue(ctx, &versionedTestIssue{testIssue: issue, version: 0})
+	require.ErrorIs(err, types.ErrConflict, "should not create an issue that already exists")
+
+	// Two writers load version 1, the first write wins and the second conflicts
+	first := *issue
+	first.SlackPostID = uuid.New().String()
+	second := *issue
+	second.Archived = true
+
+	err = versioned.SaveIssue(ctx, &versionedTestIssue{testIssue: &first, version: 1})
+	require.NoError(err, "first write should succeed")
+
+	err = versioned.SaveIssue(ctx, &versionedTestIssue{testIssue: &second, version: 1})
+	require.ErrorIs(err, types.ErrConflict, "second write should conflict")
+
+	var conflict *types.ConflictError
+	require.ErrorAs(err, &conflict)
+	assert.Equal(issue.ID, conflict.IssueID)
+	assert.Equal(int64(1), conflict.ExpectedVersion)
+	assert.Equal(int64(2), conflict.CurrentVersion)
+
+	stored, err = versioned.FindOpenVersionedIssueByCorrelationID(ctx, channel1, corr)
+	require.NoError(err)
+	require.NotNil(stored, "conflicting write should not archive the issue")
+	assert.Equal(int64(2), stored.Version)
+	assert.Equal(first.SlackPostID, testIssueFromJSON(stored.Body).SlackPostID, "conflicting write should not modify the issue")
+
+	// SaveIssues must not write a conflicting issue
+	err = versioned.SaveIssues(ctx, &versionedTestIssue{testIssue: &second, version: 1})
+	require.ErrorIs(err, types.ErrConflict, "SaveIssues should return a conflict")
+
+	stored, err = versioned.FindOpenVersionedIssueByCorrelationID(ctx, channel1, corr)
+	require.NoError(err)
+	require.NotNil(stored, "conflicting SaveIssues should not archive the issue")
+	assert.Equal(int64(2), stored.Version)
+
+	// MoveIssue must not move a conflicting issue
+	moved := first
+	movedAlert := *first.LastAlert
+	movedAlert.SlackChannelID = channel2
+	moved.LastAlert = &movedAlert
+
+	err = versioned.MoveIssue(ctx, &versionedTestIssue{testIssue: &moved, version: 1}, channel1, channel2)
+	require.ErrorIs(err, types.ErrConflict, "MoveIssue should return a conflict")
+
+	stored, err = versioned.FindOpenVersionedIssueByCorrelationID(ctx, channel1, corr)
+	require.NoError(err)
+	require.NotNil(stored, "conflicting move should not move the issue")
+
+	err = versioned.MoveIssue(ctx, &versionedTestIssue{testIssue: &moved, version: 2}, channel1, channel2)
+	require.NoError(err, "move with the current version should succeed")
+
+	stored, err = versioned.FindOpenVersionedIssueByCorrelationID(ctx, channel2, corr)
+	require.NoError(err)
+	require.NotNil(stored)
+	assert.Equal(int64(3), stored.Version, "move should increment the version")
+
+	// Unversioned issues are written unconditionally, and still increment the version
+	err = versioned.SaveIssue(ctx, &moved)
+	require.NoError(err, "unversioned write should succeed")
+
+	stored, err = versioned.FindOpenVersionedIssueByCorrelationID(ctx, channel2, corr)
+	require.NoError(err)
+	require.NotNil(stored)
+	assert.Equal(int64(4), stored.Version, "unversioned write should increment the version")
+
+	// The unversioned lookups return the same issue
+	id, body, err := versioned.FindOpenIssueByCorrelationID(ctx, channel2, corr)
+	require.NoError(err)
+	assert.Equal(stored.ID, id)
+	assert.JSONEq(string(stored.Body), string(body))
+}
+
 // TestConcurrentSaveIssue tests concurrent writes to the same issue.
 func TestConcurrentSaveIssue(t *testing.T, client types.DB) {
 	ctx := context.Background()
@@ -659,6 +773,60 @@ func TestConcurrentSaveIssue(t *testing.T, client types.DB) {
 	require.NotNil(issueBody)
 }
 
+// TestConcurrentVersionedSaveIssue tests concurrent conditional writes to the same issue.
+// All writers use the same version, so exactly one write must succeed, and all other writes must conflict.
+// The test is skipped if the client does not implement types.VersionedDB.
+func TestConcurrentVersionedSaveIssue(t *testing.T, client types.DB) {
+	versioned, ok := client.(types.VersionedDB)
+	if !ok {
+		t.Skip("client does not implement types.VersionedDB")
+	}
+
+	ctx := context.Background()
+	channel := "C0ABABABAB"
+	corr := uuid.New().String()
+	require := require.New(t)
+
+	issue := newTestIssue(newTestAlert(channel, corr), uuid.New().String())
+	err := versioned.SaveIssue(ctx, &versionedTestIssue{testIssue: issue, version: 0})
+	require.NoError(err)
+
+	const goroutines = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+
+	for i := range goroutines {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			issueCopy := *issue
+			issueCopy.SlackPostID = fmt.Sprintf("post-%d-%s", index, uuid.New().String())
+			errs <- versioned.SaveIssue(ctx, &versionedTestIssue{testIssue: &issueCopy, version: 1})
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+
+		require.ErrorIs(err, types.ErrConflict, "concurrent versioned save should only fail with a conflict")
+	}
+
+	require.Equal(1, succeeded, "exactly one concurrent versioned save should succeed")
+
+	stored, err := versioned.FindOpenVersionedIssueByCorrelationID(ctx, channel, corr)
+	require.NoError(err)
+	require.NotNil(stored)
+	require.Equal(int64(2), stored.Version)
+}
+
 // TestConcurrentMoveMapping tests concurrent move mapping operations.
 func TestConcurrentMoveMapping(t *testing.T, client types.DB) {
 	ctx := context.Background()
@@ -939,6 +1107,10 @@ func RunAllTests(t *testing.T, client types.DB) {
 	t.Run("ConcurrentSaveIssue", func(t *testing.T) { TestConcurrentSaveIssue(t, client) })
 	t.Run("ConcurrentMoveMapping", func(t *testing.T) { TestConcurrentMoveMapping(t, client) })
 
+	// Optimistic concurrency tests (skipped if the client does not implement types.VersionedDB)
+	t.Run("IssueVersionConflict", func(t *testing.T) { TestIssueVersionConflict(t, client) })
+	t.Run("ConcurrentVersionedSaveIssue", func(t *testing.T) { TestConcurrentVersionedSaveIssue(t, client) })
+
 	// Large dataset tests
 	t.Run("LoadOpenIssuesInChannel_LargeDataset", func(t *testing.T) { TestLoadOpenIssuesInChannel_LargeDataset(t, client) })
 	t.Run("FindActiveChannels_ManyChannels", func(t *testing.T) { TestFindActiveChannels_ManyChannels(t, client) })
@@ -955,6 +1127,17 @@ type testIssue struct {
 	SlackPostID   string       `json:"slackPostId"`
 }
 
+// versionedTestIssue is a testIssue with optimistic concurrency control.
+type versionedTestIssue struct {
+	*testIssue
+
+	version int64
+}
+
+func (issue *versionedTestIssue) Version() int64 {
+	return issue.version
+}
+
 func newTestAlert(channelID, correlationID string) *types.Alert {
 	alert := types.NewErrorAlert()
 	alert.SlackChannelID = channelID
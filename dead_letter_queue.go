@@ -0,0 +1,73 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// DeadLetter is a message that has been moved to a DeadLetterQueue, because it was received the max receive count
+// of its source queue without being acknowledged.
+type DeadLetter struct {
+	// MessageID is the ID of the message in the source queue. It identifies the dead letter in the dead-letter queue.
+	MessageID string `json:"messageId"`
+
+	// SourceQueue is the name of the queue the message was moved from.
+	SourceQueue string `json:"sourceQueue"`
+
+	// SlackChannelID is the ID of the Slack channel to which the message is related (the message group).
+	SlackChannelID string `json:"slackChannelId"`
+
+	// Body is the body of the message.
+	Body string `json:"body"`
+
+	// Attributes are the message attributes, if any.
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// SentTimestamp is the time the message was originally sent to the source queue.
+	SentTimestamp time.Time `json:"sentTimestamp"`
+
+	// DeadLetterTimestamp is the time the message was moved to the dead-letter queue.
+	DeadLetterTimestamp time.Time `json:"deadLetterTimestamp"`
+
+	// ReceiveCount is the number of times the message was received from the source queue, without being acknowledged.
+	ReceiveCount int `json:"receiveCount"`
+
+	// LastError describes why the last delivery of the message failed, such as the error reported with
+	// FifoQueueItem.NackWithError, or that the visibility timeout expired.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// DeadLetterQueue is an interface for a dead-letter queue, which holds the messages that a FifoQueue could not
+// deliver successfully within its max receive count (e.g. an alert that makes the processor fail every time).
+//
+// Dead letters can be listed and inspected by ops tooling, and redriven (sent back) to their source queue once
+// the cause of the failure has been fixed.
+type DeadLetterQueue interface {
+	// Name returns the name of the dead-letter queue (for logging purposes).
+	Name() string
+
+	// Add adds a dead letter, moved from the source queue. It is called by the source queue.
+	// A dead letter with the same message ID replaces the existing one.
+	Add(ctx context.Context, source FifoQueue, letter *DeadLetter) error
+
+	// List returns all dead letters, in the order they were added.
+	// The returned list may be empty if the dead-letter queue is empty.
+	List(ctx context.Context) ([]*DeadLetter, error)
+
+	// Get returns the dead letter with the specified message ID.
+	// The implementation should return [nil, nil] if no dead letter is found.
+	Get(ctx context.Context, messageID string) (*DeadLetter, error)
+
+	// Redrive sends the dead letter with the specified message ID back to its source queue, and removes it from the
+	// dead-letter queue. The message is sent as a new message, with its original message group and attributes.
+	// An error is returned if the dead letter is not found, or cannot be sent.
+	Redrive(ctx context.Context, messageID string) error
+
+	// RedriveAll redrives all dead letters, in the order they were added, and returns the number of redriven dead letters.
+	// It stops at the first error.
+	RedriveAll(ctx context.Context) (int, error)
+
+	// Delete removes the dead letter with the specified message ID, without redriving it.
+	// Deleting a dead letter that does not exist is not an error.
+	Delete(ctx context.Context, messageID string) error
+}
@@ -8,6 +8,9 @@
 //
 // DB - Database abstraction for persisting alerts, issues, move mappings, and channel processing state.
 // Implementations must handle storage as opaque JSON to allow flexibility.
+// InstrumentDB wraps any DB with metrics and debug logging, WithRetry retries transient failures,
+// and WithCache caches the hot lookups.
+// FaultyDB injects scripted faults into any DB, for chaos testing.
 //
 // Logger - Structured logging interface with Debug/Info/Error levels and field support.
 // Supports method chaining with WithField and WithFields.
@@ -15,14 +18,22 @@
 // Metrics - Prometheus-style metrics interface supporting counters, gauges, and histograms.
 // Allows registration of metrics with labels and observation of values.
 //
+// FifoQueue - FIFO message queue abstraction, with per-channel ordering, Ack/Nack redelivery and deduplication.
+// FileFifoQueue is a durable, file-backed implementation for single-node deployments.
+//
+// DeadLetterQueue - Holds messages that exceeded the max receive count of a FifoQueue, for inspection and redrive.
+//
 // # Core Domain Types
 //
 // Alert - The central type representing an alert with comprehensive validation and cleaning.
 // Contains severity, header, text, fields, webhooks, escalations, and routing information.
-// Use constructor functions: NewPanicAlert(), NewErrorAlert(), NewWarningAlert(), NewResolvedAlert(), NewInfoAlert().
+// Use constructor functions: NewPanicAlert(), NewErrorAlert(), NewWarningAlert(), NewResolvedAlert(), NewInfoAlert(),
+// or NewAlertBuilder() to build, clean and validate an alert using chained method calls.
 //
 // Issue - Interface for tracking issue state in channels. Issues group related alerts together
 // using correlation IDs. The actual implementation is internal and stored as opaque JSON.
+// A DB that implements the optional VersionedDB interface only writes a VersionedIssue if the stored version
+// is unchanged, otherwise ErrConflict is returned.
 //
 // MoveMapping - Interface for tracking issues that have been moved between channels.
 // Ensures new alerts with the same correlation ID go to the new channel.
@@ -45,15 +56,24 @@
 // Alert provides extensive validation and cleaning methods:
 //
 //   - Clean() - Normalizes and truncates all fields to valid values
+//   - CleanWithReport() - Like Clean(), but returns a list of the changes that were made
 //   - Validate() - Returns error if any field is invalid
+//   - ValidateAll() - Returns all validation errors (ValidationErrors) in a single pass
 //   - Individual validation methods for specific fields (ValidateSlackChannelIDAndRouteKey, etc.)
 //
+// Use Clone() to get a deep copy of an alert, and DiffAlerts() to list the fields that differ between two alerts.
+//
 // The package defines comprehensive constants for maximum lengths and limits (e.g., MaxHeaderLength = 130).
+// Use a ValidationPolicy with CleanWithPolicy() and ValidateWithPolicy() to tighten or loosen these limits.
+//
+// # Block Kit Rendering
+//
+// The blockkit subpackage renders an alert to a Slack Block Kit message, for an issue in a given state.
 //
 // # Testing Utilities
 //
 // The dbtests subpackage provides a shared test suite that can be run against any DB implementation
-// to ensure compliance with the interface contract.
+// to ensure compliance with the interface contract. The queuetests subpackage does the same for FifoQueue implementations.
 //
 // No-op implementations (NoopLogger, NoopMetrics) are provided for testing purposes.
 // InMemoryFifoQueue is provided for testing but should not be used in production.
@@ -0,0 +1,628 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FaultRule defines a fault injected by a FaultyDB. A rule applies to a call if the method and call number match,
+// and the (seeded) random draw is within the probability. All matching rules are applied: the latencies are added,
+// and the error of the first matching rule with an error is returned.
+type FaultRule struct {
+	// Method is the DB (or VersionedDB) method the rule applies to, such as "SaveIssue". Empty means all methods.
+	Method string
+
+	// Call is the call number the rule applies to, counted per method and starting at 1. Zero means all calls.
+	Call int
+
+	// Probability is the probability (0-1) that the rule applies to a matching call.
+	// Zero means always, i.e. the same as 1.
+	Probability float64
+
+	// Err is the error returned by the call, instead of calling the wrapped DB. Nil means no error.
+	Err error
+
+	// Latency is added to the call, before the wrapped DB is called.
+	Latency time.Duration
+
+	// LatencyJitter is the maximum random latency added to Latency.
+	LatencyJitter time.Duration
+
+	// DropWrite makes a write method return nil without calling the wrapped DB, i.e. the write is silently lost.
+	// It is ignored for read methods.
+	DropWrite bool
+
+	// StaleRead makes a read method return the result of the previous successful call with the same arguments
+	// (if any), instead of calling the wrapped DB. It is ignored for write methods.
+	StaleRead bool
+}
+
+// Validate returns an error if the rule is invalid.
+func (r *FaultRule) Validate() error {
+	if r.Method != "" && !isDBMethod(r.Method) {
+		return fmt.Errorf("method '%s' is not a DB method", r.Method)
+	}
+
+	if r.Call < 0 {
+		return errors.New("call must be >=0")
+	}
+
+	if r.Probability < 0 || r.Probability > 1 {
+		return errors.New("probability must be between 0 and 1")
+	}
+
+	if r.Latency < 0 {
+		return errors.New("latency must be >=0")
+	}
+
+	if r.LatencyJitter < 0 {
+		return errors.New("latencyJitter must be >=0")
+	}
+
+	return nil
+}
+
+// FaultyDBOptions defines the behavior of a FaultyDB.
+type FaultyDBOptions struct {
+	// Seed is the seed of the random number generator, used for probabilities and latency jitter.
+	// The same seed, faults and (sequential) calls produce the same scenario.
+	Seed uint64
+
+	// IgnoreContextCancellation makes the FaultyDB ignore context cancellation, like a misbehaving database driver:
+	// injected latency is not interrupted, and the wrapped DB is called with a context that is never canceled.
+	// By default, injected latency is interrupted, and the context error is returned.
+	IgnoreContextCancellation bool
+
+	// Faults are the initial fault rules. More rules can be added with FaultyDB.AddFault.
+	Faults []FaultRule
+}
+
+// Validate returns an error if the options are invalid.
+func (o *FaultyDBOptions) Validate() error {
+	if o == nil {
+		return errors.New("faulty db options cannot be nil")
+	}
+
+	for i := range o.Faults {
+		if err := o.Faults[i].Validate(); err != nil {
+			return fmt.Errorf("faults[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// FaultyDB is a DB wrapper that injects scripted faults, for testing how the Slack Manager behaves when the
+// database misbehaves: failed calls, latency, stale reads, silently dropped writes, and ignored context cancellation.
+//
+// FaultyDB implements DB, and controls the injected faults. Use DB to get the wrapped DB with the faults injected,
+// which also implements VersionedDB if the wrapped DB does.
+//
+// For TEST purposes only! Do not use in production!
+type FaultyDB struct {
+	db                        DB
+	ignoreContextCancellation bool
+
+	mu     sync.Mutex
+	rnd    *rand.Rand
+	faults []FaultRule
+	calls  map[string]int
+	reads  map[string]any
+}
+
+// versionedFaultyDB is a FaultyDB that wraps a VersionedDB.
+type versionedFaultyDB struct {
+	*FaultyDB
+
+	versioned VersionedDB
+}
+
+// faultyDBCall is the outcome of the fault rules for a single call.
+type faultyDBCall struct {
+	dropWrite bool
+	staleRead bool
+}
+
+// faultyDBIssueResult is the remembered result of FindOpenIssueByCorrelationID and FindIssueBySlackPostID.
+type faultyDBIssueResult struct {
+	id   string
+	body json.RawMessage
+}
+
+// NewFaultyDB creates a new FaultyDB, which wraps db.
+// No faults are injected if opts is nil.
+//
+// For TEST purposes only! Do not use in production!
+func NewFaultyDB(db DB, opts *FaultyDBOptions) (*FaultyDB, error) {
+	if opts == nil {
+		opts = &FaultyDBOptions{}
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid faulty db options: %w", err)
+	}
+
+	return &FaultyDB{
+		db:                        db,
+		ignoreContextCancellation: opts.IgnoreContextCancellation,
+		rnd:                       rand.New(rand.NewPCG(opts.Seed, opts.Seed)),
+		faults:                    append([]FaultRule(nil), opts.Faults...),
+		calls:                     make(map[string]int),
+		reads:                     make(map[string]any),
+	}, nil
+}
+
+// AddFault adds a fault rule, e.g. to simulate a database outage in the middle of a test.
+func (d *FaultyDB) AddFault(rule FaultRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.faults = append(d.faults, rule)
+
+	return nil
+}
+
+// ClearFaults removes all fault rules. The call counts are kept.
+func (d *FaultyDB) ClearFaults() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.faults = nil
+}
+
+// DB returns the FaultyDB as a DB, which implements VersionedDB if the wrapped DB does.
+// The returned DB shares the fault rules and call counts of the FaultyDB.
+func (d *FaultyDB) DB() DB { //nolint:ireturn
+	if versioned, ok := d.db.(VersionedDB); ok {
+		return &versionedFaultyDB{FaultyDB: d, versioned: versioned}
+	}
+
+	return d
+}
+
+// Calls returns the number of calls made to the specified DB method, including failed calls.
+func (d *FaultyDB) Calls(method string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.calls[method]
+}
+
+func (d *FaultyDB) Init(ctx context.Context, skipSchemaValidation bool) error {
+	ctx, call, err := d.before(ctx, "Init")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.Init(ctx, skipSchemaValidation)
+}
+
+func (d *FaultyDB) SaveAlert(ctx context.Context, alert *Alert) error {
+	ctx, call, err := d.before(ctx, "SaveAlert")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.SaveAlert(ctx, alert)
+}
+
+func (d *FaultyDB) SaveIssue(ctx context.Context, issue Issue) error {
+	ctx, call, err := d.before(ctx, "SaveIssue")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.SaveIssue(ctx, issue)
+}
+
+func (d *FaultyDB) SaveIssues(ctx context.Context, issues ...Issue) error {
+	ctx, call, err := d.before(ctx, "SaveIssues")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.SaveIssues(ctx, issues...)
+}
+
+func (d *FaultyDB) MoveIssue(ctx context.Context, issue Issue, sourceChannelID, targetChannelID string) error {
+	ctx, call, err := d.before(ctx, "MoveIssue")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.MoveIssue(ctx, issue, sourceChannelID, targetChannelID)
+}
+
+func (d *FaultyDB) FindOpenIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (string, json.RawMessage, error) {
+	ctx, call, err := d.before(ctx, "FindOpenIssueByCorrelationID")
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := faultyDBReadKey("FindOpenIssueByCorrelationID", channelID, correlationID)
+
+	if call.staleRead {
+		if result, ok := rememberedRead[faultyDBIssueResult](d, key); ok {
+			return result.id, bytes.Clone(result.body), nil
+		}
+	}
+
+	id, body, err := d.db.FindOpenIssueByCorrelationID(ctx, channelID, correlationID)
+	if err == nil {
+		d.rememberRead(key, faultyDBIssueResult{id: id, body: bytes.Clone(body)})
+	}
+
+	return id, body, err
+}
+
+func (d *FaultyDB) FindIssueBySlackPostID(ctx context.Context, channelID, postID string) (string, json.RawMessage, error) {
+	ctx, call, err := d.before(ctx, "FindIssueBySlackPostID")
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := faultyDBReadKey("FindIssueBySlackPostID", channelID, postID)
+
+	if call.staleRead {
+		if result, ok := rememberedRead[faultyDBIssueResult](d, key); ok {
+			return result.id, bytes.Clone(result.body), nil
+		}
+	}
+
+	id, body, err := d.db.FindIssueBySlackPostID(ctx, channelID, postID)
+	if err == nil {
+		d.rememberRead(key, faultyDBIssueResult{id: id, body: bytes.Clone(body)})
+	}
+
+	return id, body, err
+}
+
+func (d *versionedFaultyDB) FindOpenVersionedIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (*StoredIssue, error) {
+	ctx, call, err := d.before(ctx, "FindOpenVersionedIssueByCorrelationID")
+	if err != nil {
+		return nil, err
+	}
+
+	key := faultyDBReadKey("FindOpenVersionedIssueByCorrelationID", channelID, correlationID)
+
+	if call.staleRead {
+		if issue, ok := rememberedRead[*StoredIssue](d.FaultyDB, key); ok {
+			return copyStoredIssue(issue), nil
+		}
+	}
+
+	issue, err := d.versioned.FindOpenVersionedIssueByCorrelationID(ctx, channelID, correlationID)
+	if err == nil {
+		d.rememberRead(key, copyStoredIssue(issue))
+	}
+
+	return issue, err
+}
+
+func (d *versionedFaultyDB) FindVersionedIssueBySlackPostID(ctx context.Context, channelID, postID string) (*StoredIssue, error) {
+	ctx, call, err := d.before(ctx, "FindVersionedIssueBySlackPostID")
+	if err != nil {
+		return nil, err
+	}
+
+	key := faultyDBReadKey("FindVersionedIssueBySlackPostID", channelID, postID)
+
+	if call.staleRead {
+		if issue, ok := rememberedRead[*StoredIssue](d.FaultyDB, key); ok {
+			return copyStoredIssue(issue), nil
+		}
+	}
+
+	issue, err := d.versioned.FindVersionedIssueBySlackPostID(ctx, channelID, postID)
+	if err == nil {
+		d.rememberRead(key, copyStoredIssue(issue))
+	}
+
+	return issue, err
+}
+
+func (d *FaultyDB) FindActiveChannels(ctx context.Context) ([]string, error) {
+	ctx, call, err := d.before(ctx, "FindActiveChannels")
+	if err != nil {
+		return nil, err
+	}
+
+	key := faultyDBReadKey("FindActiveChannels")
+
+	if call.staleRead {
+		if channels, ok := rememberedRead[[]string](d, key); ok {
+			return slices.Clone(channels), nil
+		}
+	}
+
+	channels, err := d.db.FindActiveChannels(ctx)
+	if err == nil {
+		d.rememberRead(key, slices.Clone(channels))
+	}
+
+	return channels, err
+}
+
+func (d *FaultyDB) LoadOpenIssuesInChannel(ctx context.Context, channelID string) (map[string]json.RawMessage, error) {
+	ctx, call, err := d.before(ctx, "LoadOpenIssuesInChannel")
+	if err != nil {
+		return nil, err
+	}
+
+	key := faultyDBReadKey("LoadOpenIssuesInChannel", channelID)
+
+	if call.staleRead {
+		if issues, ok := rememberedRead[map[string]json.RawMessage](d, key); ok {
+			return copyRawMessages(issues), nil
+		}
+	}
+
+	issues, err := d.db.LoadOpenIssuesInChannel(ctx, channelID)
+	if err == nil {
+		d.rememberRead(key, copyRawMessages(issues))
+	}
+
+	return issues, err
+}
+
+func (d *versionedFaultyDB) LoadOpenVersionedIssuesInChannel(ctx context.Context, channelID string) (map[string]*StoredIssue, error) {
+	ctx, call, err := d.before(ctx, "LoadOpenVersionedIssuesInChannel")
+	if err != nil {
+		return nil, err
+	}
+
+	key := faultyDBReadKey("LoadOpenVersionedIssuesInChannel", channelID)
+
+	if call.staleRead {
+		if issues, ok := rememberedRead[map[string]*StoredIssue](d.FaultyDB, key); ok {
+			return copyStoredIssues(issues), nil
+		}
+	}
+
+	issues, err := d.versioned.LoadOpenVersionedIssuesInChannel(ctx, channelID)
+	if err == nil {
+		d.rememberRead(key, copyStoredIssues(issues))
+	}
+
+	return issues, err
+}
+
+func (d *FaultyDB) SaveMoveMapping(ctx context.Context, moveMapping MoveMapping) error {
+	ctx, call, err := d.before(ctx, "SaveMoveMapping")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.SaveMoveMapping(ctx, moveMapping)
+}
+
+func (d *FaultyDB) FindMoveMapping(ctx context.Context, channelID, correlationID string) (json.RawMessage, error) {
+	ctx, call, err := d.before(ctx, "FindMoveMapping")
+	if err != nil {
+		return nil, err
+	}
+
+	key := faultyDBReadKey("FindMoveMapping", channelID, correlationID)
+
+	if call.staleRead {
+		if body, ok := rememberedRead[json.RawMessage](d, key); ok {
+			return bytes.Clone(body), nil
+		}
+	}
+
+	body, err := d.db.FindMoveMapping(ctx, channelID, correlationID)
+	if err == nil {
+		d.rememberRead(key, bytes.Clone(body))
+	}
+
+	return body, err
+}
+
+func (d *FaultyDB) DeleteMoveMapping(ctx context.Context, channelID, correlationID string) error {
+	ctx, call, err := d.before(ctx, "DeleteMoveMapping")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.DeleteMoveMapping(ctx, channelID, correlationID)
+}
+
+func (d *FaultyDB) SaveChannelProcessingState(ctx context.Context, state *ChannelProcessingState) error {
+	ctx, call, err := d.before(ctx, "SaveChannelProcessingState")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.SaveChannelProcessingState(ctx, state)
+}
+
+func (d *FaultyDB) FindChannelProcessingState(ctx context.Context, channelID string) (*ChannelProcessingState, error) {
+	ctx, call, err := d.before(ctx, "FindChannelProcessingState")
+	if err != nil {
+		return nil, err
+	}
+
+	key := faultyDBReadKey("FindChannelProcessingState", channelID)
+
+	if call.staleRead {
+		if state, ok := rememberedRead[*ChannelProcessingState](d, key); ok {
+			return copyChannelProcessingState(state), nil
+		}
+	}
+
+	state, err := d.db.FindChannelProcessingState(ctx, channelID)
+	if err == nil {
+		d.rememberRead(key, copyChannelProcessingState(state))
+	}
+
+	return state, err
+}
+
+func (d *FaultyDB) DropAllData(ctx context.Context) error {
+	ctx, call, err := d.before(ctx, "DropAllData")
+	if err != nil || call.dropWrite {
+		return err
+	}
+
+	return d.db.DropAllData(ctx)
+}
+
+// before counts the call, applies the matching fault rules, and waits for the injected latency.
+// It returns the context to pass to the wrapped DB, how the call should proceed, and the injected error
+// (or the context error).
+func (d *FaultyDB) before(ctx context.Context, method string) (context.Context, faultyDBCall, error) {
+	d.mu.Lock()
+
+	d.calls[method]++
+	callNumber := d.calls[method]
+
+	var (
+		latency   time.Duration
+		injectErr error
+		call      faultyDBCall
+	)
+
+	for i := range d.faults {
+		rule := &d.faults[i]
+
+		if (rule.Method != "" && rule.Method != method) || (rule.Call != 0 && rule.Call != callNumber) {
+			continue
+		}
+
+		if rule.Probability > 0 && rule.Probability < 1 && d.rnd.Float64() >= rule.Probability {
+			continue
+		}
+
+		latency += rule.Latency
+
+		if rule.LatencyJitter > 0 {
+			latency += time.Duration(d.rnd.Int64N(int64(rule.LatencyJitter)))
+		}
+
+		if injectErr == nil {
+			injectErr = rule.Err
+		}
+
+		call.dropWrite = call.dropWrite || (rule.DropWrite && !isDBReadMethod(method))
+		call.staleRead = call.staleRead || (rule.StaleRead && isDBReadMethod(method))
+	}
+
+	d.mu.Unlock()
+
+	if d.ignoreContextCancellation {
+		time.Sleep(latency)
+		return context.WithoutCancel(ctx), call, injectErr
+	}
+
+	if err := sleepContext(ctx, latency); err != nil {
+		return ctx, call, err
+	}
+
+	return ctx, call, injectErr
+}
+
+// rememberRead stores the result of a successful read, to be returned by a later stale read.
+// The result must not be shared with the caller, i.e. it must be copied before it is stored and when it is returned.
+func (d *FaultyDB) rememberRead(key string, result any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.reads[key] = result
+}
+
+// rememberedRead returns the result of the previous successful read with the specified key, if any.
+func rememberedRead[T any](d *FaultyDB, key string) (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result, ok := d.reads[key].(T)
+
+	return result, ok
+}
+
+func faultyDBReadKey(method string, args ...string) string {
+	return method + "\x00" + strings.Join(args, "\x00")
+}
+
+func copyChannelProcessingState(state *ChannelProcessingState) *ChannelProcessingState {
+	if state == nil {
+		return nil
+	}
+
+	c := *state
+
+	return &c
+}
+
+func copyRawMessages(issues map[string]json.RawMessage) map[string]json.RawMessage {
+	c := maps.Clone(issues)
+
+	for id, body := range c {
+		c[id] = bytes.Clone(body)
+	}
+
+	return c
+}
+
+func copyStoredIssues(issues map[string]*StoredIssue) map[string]*StoredIssue {
+	c := maps.Clone(issues)
+
+	for id, issue := range c {
+		c[id] = copyStoredIssue(issue)
+	}
+
+	return c
+}
+
+// sleepContext waits for the specified duration, and returns the context error if the context is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isDBMethod returns true if name is the name of a DB (or VersionedDB) method.
+func isDBMethod(name string) bool {
+	switch name {
+	case "Init", "SaveAlert", "SaveIssue", "SaveIssues", "MoveIssue", "SaveMoveMapping", "DeleteMoveMapping",
+		"SaveChannelProcessingState", "DropAllData":
+		return true
+	default:
+		return isDBReadMethod(name)
+	}
+}
+
+// isDBReadMethod returns true if name is the name of a read-only DB (or VersionedDB) method.
+func isDBReadMethod(name string) bool {
+	switch name {
+	case "FindOpenIssueByCorrelationID", "FindIssueBySlackPostID", "FindActiveChannels", "LoadOpenIssuesInChannel",
+		"FindMoveMapping", "FindChannelProcessingState", "FindOpenVersionedIssueByCorrelationID",
+		"FindVersionedIssueBySlackPostID", "LoadOpenVersionedIssuesInChannel":
+		return true
+	default:
+		return false
+	}
+}
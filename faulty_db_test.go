@@ -0,0 +1,271 @@
+package types_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/dbtests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultyDBOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	var opts *types.FaultyDBOptions
+	require.Error(t, opts.Validate())
+
+	opts = &types.FaultyDBOptions{Faults: []types.FaultRule{{Method: "SaveIssue", Call: 1, Probability: 0.5}}}
+	require.NoError(t, opts.Validate())
+
+	opts.Faults = append(opts.Faults, types.FaultRule{Method: "SaveEverything"})
+	require.ErrorContains(t, opts.Validate(), "faults[1]: method 'SaveEverything' is not a DB method")
+
+	opts.Faults[1] = types.FaultRule{Probability: 1.5}
+	require.ErrorContains(t, opts.Validate(), "probability")
+
+	opts.Faults[1] = types.FaultRule{Latency: -time.Second}
+	require.ErrorContains(t, opts.Validate(), "latency")
+
+	_, err := types.NewFaultyDB(types.NewInMemoryDB(), opts)
+	require.ErrorContains(t, err, "invalid faulty db options")
+
+	db, err := types.NewFaultyDB(types.NewInMemoryDB(), nil)
+	require.NoError(t, err)
+	require.Error(t, db.AddFault(types.FaultRule{Call: -1}))
+}
+
+func TestFaultyDB(t *testing.T) {
+	t.Parallel()
+
+	t.Run("faulty db without faults should pass the db tests", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := types.NewFaultyDB(types.NewInMemoryDB(), nil)
+		require.NoError(t, err)
+
+		dbtests.RunAllTests(t, db.DB())
+	})
+
+	t.Run("faulty db should only implement VersionedDB if the wrapped db does", func(t *testing.T) {
+		t.Parallel()
+
+		faulty := newFaultyDB(t, nil)
+
+		db, ok := faulty.DB().(types.VersionedDB)
+		require.True(t, ok, "faulty db should implement VersionedDB if the wrapped db does")
+
+		_, err := db.FindOpenVersionedIssueByCorrelationID(context.Background(), "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, faulty.Calls("FindOpenVersionedIssueByCorrelationID"))
+
+		_, ok = any(faulty).(types.VersionedDB)
+		assert.False(t, ok, "faulty db should only implement VersionedDB through DB()")
+
+		faulty, err = types.NewFaultyDB(unversionedDB{types.NewInMemoryDB()}, nil)
+		require.NoError(t, err)
+
+		_, ok = faulty.DB().(types.VersionedDB)
+		assert.False(t, ok, "faulty db should only implement VersionedDB if the wrapped db does")
+	})
+
+	t.Run("nth call should fail", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		db := newFaultyDB(t, &types.FaultyDBOptions{
+			Faults: []types.FaultRule{{Method: "FindChannelProcessingState", Call: 2, Err: errDBUnavailable}},
+		})
+
+		for i := 1; i <= 3; i++ {
+			_, err := db.FindChannelProcessingState(ctx, "C000000001")
+			if i == 2 {
+				require.ErrorIs(t, err, errDBUnavailable)
+			} else {
+				require.NoError(t, err)
+			}
+		}
+
+		assert.Equal(t, 3, db.Calls("FindChannelProcessingState"))
+		assert.Zero(t, db.Calls("SaveIssue"))
+	})
+
+	t.Run("writes should be dropped silently", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		db := newFaultyDB(t, &types.FaultyDBOptions{
+			Faults: []types.FaultRule{{Method: "SaveChannelProcessingState", DropWrite: true}},
+		})
+
+		require.NoError(t, db.SaveChannelProcessingState(ctx, types.NewChannelProcessingState("C000000001")))
+
+		state, err := db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.Nil(t, state)
+	})
+
+	t.Run("stale reads should return the previous result", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		db := newFaultyDB(t, nil)
+
+		state := types.NewChannelProcessingState("C000000001")
+		state.OpenIssues = 1
+		require.NoError(t, db.SaveChannelProcessingState(ctx, state))
+
+		found, err := db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.Equal(t, 1, found.OpenIssues)
+
+		state.OpenIssues = 2
+		require.NoError(t, db.SaveChannelProcessingState(ctx, state))
+		require.NoError(t, db.AddFault(types.FaultRule{StaleRead: true}))
+
+		found, err = db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.Equal(t, 1, found.OpenIssues, "stale read should return the previous state")
+
+		db.ClearFaults()
+
+		found, err = db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.Equal(t, 2, found.OpenIssues)
+	})
+
+	t.Run("stale reads should not share results with the caller", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		faulty := newFaultyDB(t, &types.FaultyDBOptions{Faults: []types.FaultRule{{Call: 2, StaleRead: true}}})
+
+		db, ok := faulty.DB().(types.VersionedDB)
+		require.True(t, ok)
+		require.NoError(t, db.SaveIssue(ctx, newTestIssue("issue-1", "C000000001", "corr-1", "post-1")))
+
+		channels, err := db.FindActiveChannels(ctx)
+		require.NoError(t, err)
+		channels[0] = "C000000002"
+
+		channels, err = db.FindActiveChannels(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"C000000001"}, channels)
+
+		issues, err := db.LoadOpenIssuesInChannel(ctx, "C000000001")
+		require.NoError(t, err)
+		copy(issues["issue-1"], "XXXX")
+		issues["issue-2"] = issues["issue-1"]
+
+		issues, err = db.LoadOpenIssuesInChannel(ctx, "C000000001")
+		require.NoError(t, err)
+		require.Len(t, issues, 1)
+		assert.Equal(t, "issue-1", testIssueFromJSON(t, issues["issue-1"]).ID)
+
+		issue, err := db.FindOpenVersionedIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		issue.Version = 42
+		copy(issue.Body, "XXXX")
+
+		issue, err = db.FindOpenVersionedIssueByCorrelationID(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.NotEqual(t, int64(42), issue.Version)
+		assert.Equal(t, "issue-1", testIssueFromJSON(t, issue.Body).ID)
+
+		versioned, err := db.LoadOpenVersionedIssuesInChannel(ctx, "C000000001")
+		require.NoError(t, err)
+		versioned["issue-1"].Version = 42
+		delete(versioned, "issue-1")
+
+		versioned, err = db.LoadOpenVersionedIssuesInChannel(ctx, "C000000001")
+		require.NoError(t, err)
+		require.Contains(t, versioned, "issue-1")
+		assert.NotEqual(t, int64(42), versioned["issue-1"].Version)
+
+		assert.Equal(t, 2, faulty.Calls("LoadOpenVersionedIssuesInChannel"))
+	})
+
+	t.Run("latency should be interrupted by context cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		db := newFaultyDB(t, &types.FaultyDBOptions{
+			Faults: []types.FaultRule{{Latency: time.Minute}},
+		})
+
+		start := time.Now()
+		_, err := db.FindActiveChannels(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("context cancellation can be ignored", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		db := newFaultyDB(t, &types.FaultyDBOptions{
+			IgnoreContextCancellation: true,
+			Faults:                    []types.FaultRule{{Method: "SaveChannelProcessingState", Latency: 50 * time.Millisecond}},
+		})
+
+		start := time.Now()
+		require.NoError(t, db.SaveChannelProcessingState(ctx, types.NewChannelProcessingState("C000000001")))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+		state, err := db.FindChannelProcessingState(context.Background(), "C000000001")
+		require.NoError(t, err)
+		assert.NotNil(t, state)
+	})
+
+	t.Run("scenario should be reproducible from the seed", func(t *testing.T) {
+		t.Parallel()
+
+		run := func(seed uint64) []bool {
+			db := newFaultyDB(t, &types.FaultyDBOptions{
+				Seed:   seed,
+				Faults: []types.FaultRule{{Method: "FindActiveChannels", Probability: 0.5, Err: errDBUnavailable}},
+			})
+
+			failures := make([]bool, 50)
+			for i := range failures {
+				_, err := db.FindActiveChannels(context.Background())
+				failures[i] = err != nil
+			}
+
+			return failures
+		}
+
+		first := run(42)
+		assert.Equal(t, first, run(42))
+		assert.Contains(t, first, true)
+		assert.Contains(t, first, false)
+	})
+
+	t.Run("retrying db should recover from transient faults", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		faulty := newFaultyDB(t, &types.FaultyDBOptions{
+			Faults: []types.FaultRule{
+				{Method: "SaveChannelProcessingState", Call: 1, Err: errDBUnavailable},
+				{Method: "SaveChannelProcessingState", Call: 2, Err: errDBUnavailable},
+			},
+		})
+
+		db := types.WithRetry(faulty, types.RetryPolicy{InitialBackoff: time.Millisecond})
+
+		require.NoError(t, db.SaveChannelProcessingState(ctx, types.NewChannelProcessingState("C000000001")))
+		assert.Equal(t, 3, faulty.Calls("SaveChannelProcessingState"))
+
+		state, err := db.FindChannelProcessingState(ctx, "C000000001")
+		require.NoError(t, err)
+		assert.NotNil(t, state)
+	})
+}
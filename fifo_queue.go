@@ -0,0 +1,89 @@
+package types
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	// DefaultFifoQueueVisibilityTimeout is the default time a received message is hidden from other receivers,
+	// before it is redelivered (unless it is acknowledged).
+	DefaultFifoQueueVisibilityTimeout = 30 * time.Second
+
+	// DefaultFifoQueueDeduplicationWindow is the default deduplication window of a FifoQueue (the same as SQS FIFO queues).
+	DefaultFifoQueueDeduplicationWindow = 5 * time.Minute
+)
+
+// FifoQueue is an interface for a FIFO message queue, used to buffer alerts and other messages between the
+// API and the manager. It must be implemented by any queue driver used by the Slack Manager.
+//
+// Messages are grouped by Slack channel ID (the message group). Implementations must deliver the messages in each
+// group in the order they were sent. Messages in different groups may be delivered in any order (and concurrently).
+//
+// Each received message must be acknowledged with FifoQueueItem.Ack once it has been processed, or negatively
+// acknowledged with FifoQueueItem.Nack if processing failed. Nacked messages must be redelivered.
+// Implementations should also set the AckFunc, NackWithDelayFunc and ExtendVisibilityFunc fields of received items,
+// which back FifoQueueItem.AckE, FifoQueueItem.NackWithDelay and FifoQueueItem.ExtendVisibility.
+// Received items must have the SentTimestamp and ReceiveCount fields set, so that queue latency and redeliveries
+// can be observed.
+//
+// The queuetests package provides a test suite that checks these semantics.
+type FifoQueue interface {
+	// Name returns the name of the queue (for logging purposes).
+	Name() string
+
+	// Send sends a message to the queue, in the message group of the specified Slack channel ID.
+	//
+	// dedupID is the deduplication ID of the message. Messages with the same deduplication ID, sent within the
+	// deduplication window of the implementation (typically DefaultFifoQueueDeduplicationWindow), are accepted but
+	// delivered only once. This makes it safe for producers to retry failed sends.
+	// If dedupID is empty, the deduplication ID is derived from the message body (see ContentDeduplicationID),
+	// i.e. messages with the same body are deduplicated.
+	//
+	// An error is returned if the context is canceled before the message is accepted by the queue.
+	Send(ctx context.Context, slackChannelID, dedupID, body string) error
+
+	// SendWithDelay sends a message to the queue, like Send, but the message is not visible to receivers until the
+	// delay has passed. The message takes its place in the message group when it becomes visible, i.e. after any
+	// messages in the group that were sent later, but became visible earlier. A zero delay is the same as Send.
+	//
+	// An error is returned if the delay is negative, or larger than the maximum delay of the implementation.
+	SendWithDelay(ctx context.Context, slackChannelID, dedupID, body string, delay time.Duration) error
+
+	// SendMessage sends a message to the queue, like Send and SendWithDelay, with optional message attributes.
+	// The attributes are delivered with the message, in FifoQueueItem.Attributes.
+	//
+	// An error is returned if the message is invalid (see QueueMessage.Validate).
+	SendMessage(ctx context.Context, msg *QueueMessage) error
+
+	// SendBatch sends up to MaxQueueBatchSize messages to the queue, in order, and returns a result for each message
+	// (in the same order). A message that is not accepted does not prevent the other messages from being sent.
+	// Duplicate messages are accepted, with the message ID of the original message.
+	//
+	// An error is returned (with nil results) if the batch is empty or larger than MaxQueueBatchSize, or if the context
+	// is canceled before any message is sent.
+	SendBatch(ctx context.Context, msgs []QueueMessage) ([]SendResult, error)
+
+	// Receive receives messages from the queue, and writes them to the specified sink channel, until the context
+	// is canceled or a fatal error occurs. Receive blocks until then.
+	//
+	// The sink channel is closed when Receive returns. The context error is returned if the context is canceled.
+	Receive(ctx context.Context, sinkCh chan<- *FifoQueueItem) error
+
+	// ReceiveBatch receives up to maxItems (1 to MaxQueueBatchSize) messages from the queue, as an alternative to
+	// Receive for consumers that pull messages. It returns immediately if any messages are available. Otherwise, it
+	// waits up to the wait duration for messages (long polling), and returns an empty slice if none become available.
+	// Messages from the same message group are returned in order.
+	//
+	// An error is returned if maxItems is out of range, the wait duration is negative, or the context is canceled.
+	ReceiveBatch(ctx context.Context, maxItems int, wait time.Duration) ([]*FifoQueueItem, error)
+}
+
+// ContentDeduplicationID returns the deduplication ID used for content-based deduplication, i.e. when a message
+// is sent without an explicit deduplication ID. It is the hex encoded SHA-256 hash of the message body.
+func ContentDeduplicationID(body string) string {
+	hash := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,738 @@
+package types
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// fifoQueueNackedReason is the last error reported to the dead-letter queue for a message nacked without an error.
+	fifoQueueNackedReason = "message was nacked"
+
+	// fifoQueueRestoredReason is the last error reported to the dead-letter queue for a restored message that had
+	// already reached the max receive count, e.g. because processing it crashed the process.
+	fifoQueueRestoredReason = "max receive count reached before the queue was restored"
+)
+
+// fifoQueueConfig defines the behavior of a fifoQueueEngine.
+type fifoQueueConfig struct {
+	bufferSize          int
+	writeTimeout        time.Duration
+	visibilityTimeout   time.Duration
+	maxReceiveCount     int
+	deduplicationWindow time.Duration
+	deadLetterQueue     DeadLetterQueue
+	logger              Logger
+
+	// source is the queue using the engine. It is passed to the dead-letter queue, for redrive.
+	source FifoQueue
+}
+
+// fifoQueueStore persists the messages of a fifoQueueEngine.
+// The methods are called with the engine lock held, in the order of the operations.
+type fifoQueueStore interface {
+	// append persists a new message. The message is not added to the queue if an error is returned.
+	append(msg *fifoQueueMessage) error
+
+	// remove persists that a message has been removed from the queue (acknowledged, dropped or dead-lettered).
+	remove(msg *fifoQueueMessage) error
+
+	// receive persists that a message has been received, i.e. that its receive count has been incremented.
+	receive(msg *fifoQueueMessage) error
+}
+
+// fifoQueueEngine implements the FifoQueue semantics shared by InMemoryFifoQueue and FileFifoQueue:
+// message groups by Slack channel ID, delayed delivery, redelivery of nacked and timed out messages, the max receive
+// count with dead-letter queue, and deduplication.
+type fifoQueueEngine struct {
+	cfg   fifoQueueConfig
+	store fifoQueueStore
+
+	mu       sync.Mutex
+	seq      int64
+	orders   int64
+	receipts int64
+	pending  []*fifoQueueMessage
+	delayed  fifoQueueDelayHeap
+	inFlight map[string]*fifoQueueMessage
+	changed  chan struct{}
+
+	// inFlightGroups contains the Slack channel IDs with a message in flight.
+	inFlightGroups map[string]struct{}
+
+	// dedupIDs maps the deduplication IDs sent within the deduplication window to the sent message.
+	dedupIDs map[string]fifoQueueDedupEntry
+
+	// dedupExpiry contains the entries of dedupIDs in expiry order, so that expired entries can be removed from the front.
+	dedupExpiry []fifoQueueDedupEntry
+}
+
+// fifoQueueDedupEntry is a deduplication ID sent within the deduplication window.
+type fifoQueueDedupEntry struct {
+	// dedupID is the deduplication ID.
+	dedupID string
+
+	// messageID is the ID of the message sent with the deduplication ID.
+	messageID string
+
+	// expires is the time when the deduplication window of the deduplication ID ends.
+	expires time.Time
+}
+
+// fifoQueueMessage is a message stored in a fifoQueueEngine.
+type fifoQueueMessage struct {
+	seq            int64
+	id             string
+	slackChannelID string
+	dedupID        string
+	body           string
+	sentAt         time.Time
+	receiveCount   int
+	attributes     map[string]string
+
+	// lastError describes why the last delivery of the message failed. It is reported to the dead-letter queue.
+	lastError string
+
+	// nacked is true if the current delivery has been nacked, i.e. it did not fail by timing out.
+	nacked bool
+
+	// order is the position of the message in its message group, assigned when the message first becomes visible.
+	// For delayed messages, this is when the delay has passed, i.e. after messages sent later without delay.
+	order int64
+
+	// receipt identifies the current delivery of an in-flight message. Ack and Nack for earlier deliveries are ignored.
+	receipt int64
+
+	// visibleAt is the time when a delayed message becomes visible, or when an in-flight message becomes
+	// visible again (unless it is acknowledged).
+	visibleAt time.Time
+}
+
+// fifoQueueDeadLetter is a message to be moved to the dead-letter queue, as captured when it expired.
+type fifoQueueDeadLetter struct {
+	deadLetter *DeadLetter
+
+	// receipt is the delivery of the message that is acknowledged once the message has been added to the
+	// dead-letter queue.
+	receipt int64
+}
+
+// fifoQueueDelayHeap is a min-heap of delayed messages, ordered by the time they become visible.
+type fifoQueueDelayHeap []*fifoQueueMessage
+
+func (h fifoQueueDelayHeap) Len() int { return len(h) }
+
+func (h fifoQueueDelayHeap) Less(i, j int) bool {
+	if h[i].visibleAt.Equal(h[j].visibleAt) {
+		return h[i].seq < h[j].seq
+	}
+
+	return h[i].visibleAt.Before(h[j].visibleAt)
+}
+
+func (h fifoQueueDelayHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *fifoQueueDelayHeap) Push(x any) {
+	if msg, ok := x.(*fifoQueueMessage); ok {
+		*h = append(*h, msg)
+	}
+}
+
+func (h *fifoQueueDelayHeap) Pop() any {
+	old := *h
+	msg := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+
+	return msg
+}
+
+// newFifoQueueEngine creates a new, empty fifoQueueEngine. The store is optional, NoopLogger is used if the logger is nil.
+func newFifoQueueEngine(cfg fifoQueueConfig, store fifoQueueStore) *fifoQueueEngine {
+	if cfg.logger == nil {
+		cfg.logger = &NoopLogger{}
+	}
+
+	return &fifoQueueEngine{
+		cfg:            cfg,
+		store:          store,
+		inFlight:       make(map[string]*fifoQueueMessage),
+		changed:        make(chan struct{}),
+		inFlightGroups: make(map[string]struct{}),
+		dedupIDs:       make(map[string]fifoQueueDedupEntry),
+	}
+}
+
+// restore adds previously stored messages (in send order) to the queue, and registers their deduplication IDs.
+// Messages with a visibleAt time in the future are delayed until then. Messages that have already reached the max
+// receive count are dead-lettered (or dropped) by the next receive, without being delivered again.
+// seq is the highest sequence number used so far.
+func (e *fifoQueueEngine) restore(msgs []*fifoQueueMessage, dedupIDs map[string]fifoQueueDedupEntry, seq int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.seq = max(e.seq, seq)
+	now := time.Now()
+
+	for _, msg := range msgs {
+		_, groupInFlight := e.inFlightGroups[msg.slackChannelID]
+
+		if e.cfg.maxReceiveCount > 0 && msg.receiveCount >= e.cfg.maxReceiveCount && !groupInFlight {
+			// Mark the message as an expired delivery, so that it is handled like any other message that has
+			// reached the max receive count
+			e.setInFlightLocked(msg, now)
+			msg.visibleAt = now
+			msg.nacked = true
+			msg.lastError = fifoQueueRestoredReason
+
+			continue
+		}
+
+		e.enqueueLocked(msg, now)
+	}
+
+	for dedupID, entry := range dedupIDs {
+		entry.dedupID = dedupID
+		e.dedupIDs[dedupID] = entry
+		e.dedupExpiry = append(e.dedupExpiry, entry)
+	}
+
+	sort.SliceStable(e.dedupExpiry, func(i, j int) bool {
+		return e.dedupExpiry[i].expires.Before(e.dedupExpiry[j].expires)
+	})
+}
+
+// send adds a message to the queue, which becomes visible to receivers after the message delay, and returns
+// the message ID.
+// Duplicate messages (same deduplication ID within the deduplication window) are accepted, but not added.
+// The ID of the original message is returned for duplicates.
+// If the deduplication ID is empty, content-based deduplication is used.
+// An error is returned if the context is canceled, the message is invalid, the write timeout is reached or the
+// message cannot be stored.
+func (e *fifoQueueEngine) send(ctx context.Context, m *QueueMessage) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if err := m.Validate(); err != nil {
+		return "", err
+	}
+
+	dedupID := m.DedupID
+
+	if dedupID == "" {
+		dedupID = ContentDeduplicationID(m.Body)
+	}
+
+	timeout := time.NewTimer(e.cfg.writeTimeout)
+	defer timeout.Stop()
+
+	for {
+		e.mu.Lock()
+
+		if messageID, ok := e.isDuplicateLocked(dedupID, time.Now()); ok {
+			e.mu.Unlock()
+			return messageID, nil
+		}
+
+		if len(e.pending)+len(e.delayed)+len(e.inFlight) < e.cfg.bufferSize {
+			messageID, err := e.appendLocked(m, dedupID)
+			e.mu.Unlock()
+
+			return messageID, err
+		}
+
+		changed := e.changed
+		e.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timeout.C:
+			return "", errors.New("timeout while writing to queue")
+		case <-changed:
+		}
+	}
+}
+
+// sendBatch sends the messages in order, and returns the result for each message.
+// An error is only returned if the batch is empty or too large, or the context is canceled before the first
+// message is sent. If the context is canceled later, the context error is returned for the remaining messages.
+func (e *fifoQueueEngine) sendBatch(ctx context.Context, msgs []QueueMessage) ([]SendResult, error) {
+	if err := validateQueueBatchSize(len(msgs)); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]SendResult, len(msgs))
+
+	for i := range msgs {
+		results[i].MessageID, results[i].Err = e.send(ctx, &msgs[i])
+	}
+
+	return results, nil
+}
+
+// appendLocked stores a new message, adds it to the pending (or delayed) messages, and returns the message ID.
+func (e *fifoQueueEngine) appendLocked(m *QueueMessage, dedupID string) (string, error) {
+	now := time.Now()
+
+	msg := &fifoQueueMessage{
+		seq:            e.seq + 1,
+		id:             uuid.New().String(),
+		slackChannelID: m.SlackChannelID,
+		dedupID:        dedupID,
+		body:           m.Body,
+		sentAt:         now,
+		attributes:     maps.Clone(m.Attributes),
+	}
+
+	if m.Delay > 0 {
+		msg.visibleAt = now.Add(m.Delay)
+	}
+
+	if e.store != nil {
+		if err := e.store.append(msg); err != nil {
+			return "", fmt.Errorf("failed to store message: %w", err)
+		}
+	}
+
+	e.seq = msg.seq
+	e.enqueueLocked(msg, now)
+	e.addDedupIDLocked(dedupID, msg.id, now)
+
+	return msg.id, nil
+}
+
+// enqueueLocked adds a new message to the pending messages, or to the delayed messages if it is not yet visible.
+func (e *fifoQueueEngine) enqueueLocked(msg *fifoQueueMessage, now time.Time) {
+	if msg.visibleAt.After(now) {
+		heap.Push(&e.delayed, msg)
+		e.notifyLocked()
+
+		return
+	}
+
+	e.orders++
+	msg.order = e.orders
+	e.insertPendingLocked(msg)
+}
+
+// promoteDelayed moves the delayed messages that have become visible to the pending messages.
+func (e *fifoQueueEngine) promoteDelayed(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for len(e.delayed) > 0 && !e.delayed[0].visibleAt.After(now) {
+		msg, _ := heap.Pop(&e.delayed).(*fifoQueueMessage)
+		e.enqueueLocked(msg, now)
+	}
+}
+
+// receive writes messages to the specified sink channel, until the context is canceled.
+// The sink channel is closed when the function returns.
+func (e *fifoQueueEngine) receive(ctx context.Context, sinkCh chan<- *FifoQueueItem) error {
+	defer close(sinkCh)
+
+	for {
+		if err := e.prepare(ctx); err != nil {
+			return err
+		}
+
+		msg, wait, changed := e.next(time.Now())
+
+		if msg == nil {
+			if err := waitForChange(ctx, changed, wait); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			e.release(msg)
+			return ctx.Err()
+		case sinkCh <- e.newItem(msg):
+		}
+	}
+}
+
+// receiveBatch returns up to maxItems available messages. If no message is available, it waits up to the wait
+// duration for a message to become available, and returns an empty slice if none does.
+func (e *fifoQueueEngine) receiveBatch(ctx context.Context, maxItems int, wait time.Duration) ([]*FifoQueueItem, error) {
+	if err := validateQueueBatchSize(maxItems); err != nil {
+		return nil, err
+	}
+
+	if wait < 0 {
+		return nil, fmt.Errorf("wait %s cannot be negative", wait)
+	}
+
+	deadline := time.Now().Add(wait)
+
+	for {
+		if err := e.prepare(ctx); err != nil {
+			return nil, err
+		}
+
+		var (
+			items   []*FifoQueueItem
+			next    time.Duration
+			changed <-chan struct{}
+		)
+
+		for len(items) < maxItems {
+			var msg *fifoQueueMessage
+
+			if msg, next, changed = e.next(time.Now()); msg == nil {
+				break
+			}
+
+			items = append(items, e.newItem(msg))
+		}
+
+		remaining := time.Until(deadline)
+
+		if len(items) > 0 || remaining <= 0 {
+			return items, nil
+		}
+
+		if next == 0 || next > remaining {
+			next = remaining
+		}
+
+		if err := waitForChange(ctx, changed, next); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// prepare makes delayed messages visible, and requeues (or dead-letters) timed out in-flight messages.
+func (e *fifoQueueEngine) prepare(ctx context.Context) error {
+	e.promoteDelayed(time.Now())
+
+	deadLetters := e.requeueExpired(time.Now())
+
+	return e.sendToDeadLetterQueue(ctx, deadLetters)
+}
+
+// waitForChange waits until the changed channel is closed, or the wait duration has passed (if positive).
+// An error is returned if the context is canceled.
+func waitForChange(ctx context.Context, changed <-chan struct{}, wait time.Duration) error {
+	var timeout <-chan time.Time
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+	case <-timeout:
+	}
+
+	return nil
+}
+
+// next marks the first available message as in flight, and returns it.
+// A pending message is available if no other message in the same group is in flight.
+// If no message is available, it returns the time until the next delayed or in-flight message becomes visible
+// (zero if none), and a channel that is closed when the queue changes.
+func (e *fifoQueueEngine) next(now time.Time) (*fifoQueueMessage, time.Duration, <-chan struct{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, msg := range e.pending {
+		if _, ok := e.inFlightGroups[msg.slackChannelID]; ok {
+			continue
+		}
+
+		e.pending = append(e.pending[:i], e.pending[i+1:]...)
+
+		msg.receiveCount++
+		e.setInFlightLocked(msg, now)
+
+		if e.store != nil {
+			// The receive count is persisted on a best-effort basis: the message is delivered even if it cannot be
+			// stored, and a delivery that is released again (see release) stays counted.
+			_ = e.store.receive(msg)
+		}
+
+		return msg, 0, nil
+	}
+
+	var wait time.Duration
+
+	for _, msg := range e.inFlight {
+		if d := max(msg.visibleAt.Sub(now), time.Millisecond); wait == 0 || d < wait {
+			wait = d
+		}
+	}
+
+	if len(e.delayed) > 0 {
+		if d := max(e.delayed[0].visibleAt.Sub(now), time.Millisecond); wait == 0 || d < wait {
+			wait = d
+		}
+	}
+
+	return nil, wait, e.changed
+}
+
+// release makes an in-flight message available again, without counting the delivery as a receive.
+// It is used when a message could not be written to the sink channel.
+func (e *fifoQueueEngine) release(msg *fifoQueueMessage) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if current, ok := e.inFlight[msg.id]; ok && current.receipt == msg.receipt {
+		e.deleteInFlightLocked(msg)
+		msg.receiveCount--
+		e.insertPendingLocked(msg)
+	}
+}
+
+// requeueExpired moves in-flight messages that have passed their visibility timeout back to the pending list,
+// in their original order. Messages that have reached the max receive count are returned instead, to be moved to
+// the dead-letter queue. They are kept in flight (with a new receipt) until they have been sent to the dead-letter queue.
+// Without a dead-letter queue, they are dropped.
+func (e *fifoQueueEngine) requeueExpired(now time.Time) []fifoQueueDeadLetter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var expired []*fifoQueueMessage
+
+	for _, msg := range e.inFlight {
+		if msg.visibleAt.After(now) {
+			continue
+		}
+
+		if !msg.nacked {
+			msg.lastError = "visibility timeout expired"
+		}
+
+		if e.cfg.maxReceiveCount <= 0 || msg.receiveCount < e.cfg.maxReceiveCount {
+			e.deleteInFlightLocked(msg)
+			e.insertPendingLocked(msg)
+		} else if e.cfg.deadLetterQueue == nil {
+			_ = e.removeLocked(msg)
+		} else {
+			e.setInFlightLocked(msg, now)
+			expired = append(expired, msg)
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].seq < expired[j].seq })
+
+	// The dead letters are created while holding the lock, since the messages may change once it is released
+	deadLetters := make([]fifoQueueDeadLetter, 0, len(expired))
+
+	for _, msg := range expired {
+		deadLetters = append(deadLetters, fifoQueueDeadLetter{
+			receipt: msg.receipt,
+			deadLetter: &DeadLetter{
+				MessageID:           msg.id,
+				SourceQueue:         e.cfg.source.Name(),
+				SlackChannelID:      msg.slackChannelID,
+				Body:                msg.body,
+				Attributes:          maps.Clone(msg.attributes),
+				SentTimestamp:       msg.sentAt,
+				DeadLetterTimestamp: now,
+				ReceiveCount:        msg.receiveCount,
+				LastError:           msg.lastError,
+			},
+		})
+	}
+
+	return deadLetters
+}
+
+// sendToDeadLetterQueue adds the dead letters to the dead-letter queue, and removes their messages from the queue.
+// Messages that cannot be added are logged and kept in flight, and retried after the visibility timeout.
+// An error is only returned if the context is canceled.
+func (e *fifoQueueEngine) sendToDeadLetterQueue(ctx context.Context, deadLetters []fifoQueueDeadLetter) error {
+	for _, d := range deadLetters {
+		if err := e.cfg.deadLetterQueue.Add(ctx, e.cfg.source, d.deadLetter); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+
+			e.cfg.logger.WithFields(map[string]any{
+				"queue":             e.cfg.source.Name(),
+				"dead_letter_queue": e.cfg.deadLetterQueue.Name(),
+				"message_id":        d.deadLetter.MessageID,
+				"error":             err.Error(),
+			}).Error("Failed to add message to dead-letter queue")
+
+			continue
+		}
+
+		_ = e.ack(d.deadLetter.MessageID, d.receipt)
+	}
+
+	return nil
+}
+
+// ack removes an in-flight message from the queue.
+// ErrQueueItemExpired is returned if the delivery is no longer in flight.
+func (e *fifoQueueEngine) ack(id string, receipt int64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	msg, ok := e.inFlight[id]
+	if !ok || msg.receipt != receipt {
+		return ErrQueueItemExpired
+	}
+
+	return e.removeLocked(msg)
+}
+
+// nack makes an in-flight message visible again after the specified delay, and records why the delivery failed.
+// ErrQueueItemExpired is returned if the delivery is no longer in flight.
+func (e *fifoQueueEngine) nack(id string, receipt int64, delay time.Duration, reason string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	msg, ok := e.inFlight[id]
+	if !ok || msg.receipt != receipt {
+		return ErrQueueItemExpired
+	}
+
+	msg.nacked = true
+	msg.lastError = reason
+	msg.visibleAt = time.Now().Add(delay)
+	e.notifyLocked()
+
+	return nil
+}
+
+// extendVisibility keeps an in-flight message hidden for the specified timeout (from now).
+// ErrQueueItemExpired is returned if the delivery is no longer in flight.
+func (e *fifoQueueEngine) extendVisibility(id string, receipt int64, timeout time.Duration) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	msg, ok := e.inFlight[id]
+	if !ok || msg.receipt != receipt {
+		return ErrQueueItemExpired
+	}
+
+	msg.visibleAt = time.Now().Add(timeout)
+	e.notifyLocked()
+
+	return nil
+}
+
+func (e *fifoQueueEngine) newItem(msg *fifoQueueMessage) *FifoQueueItem {
+	id, receipt := msg.id, msg.receipt
+
+	return &FifoQueueItem{
+		MessageID:        id,
+		SlackChannelID:   msg.slackChannelID,
+		ReceiveTimestamp: time.Now(),
+		Body:             msg.body,
+		SentTimestamp:    msg.sentAt,
+		ReceiveCount:     msg.receiveCount,
+		Attributes:       maps.Clone(msg.attributes),
+		Ack:              func() { _ = e.ack(id, receipt) },
+		Nack:             func() { _ = e.nack(id, receipt, 0, fifoQueueNackedReason) },
+		AckFunc:          func() error { return e.ack(id, receipt) },
+		NackWithDelayFunc: func(delay time.Duration) error {
+			return e.nack(id, receipt, delay, fifoQueueNackedReason)
+		},
+		NackWithErrorFunc: func(cause error) {
+			_ = e.nack(id, receipt, 0, cause.Error())
+		},
+		ExtendVisibilityFunc: func(timeout time.Duration) error {
+			return e.extendVisibility(id, receipt, timeout)
+		},
+	}
+}
+
+// isDuplicateLocked returns the ID of the original message, and true, if the deduplication ID has been sent
+// within the deduplication window. Expired deduplication IDs are removed.
+func (e *fifoQueueEngine) isDuplicateLocked(dedupID string, now time.Time) (string, bool) {
+	for len(e.dedupExpiry) > 0 && !e.dedupExpiry[0].expires.After(now) {
+		expired := e.dedupExpiry[0]
+		e.dedupExpiry[0] = fifoQueueDedupEntry{}
+		e.dedupExpiry = e.dedupExpiry[1:]
+
+		// The map entry may have been replaced by a later message with the same deduplication ID
+		if entry, ok := e.dedupIDs[expired.dedupID]; ok && entry.messageID == expired.messageID {
+			delete(e.dedupIDs, expired.dedupID)
+		}
+	}
+
+	entry, ok := e.dedupIDs[dedupID]
+
+	return entry.messageID, ok
+}
+
+// addDedupIDLocked registers a sent deduplication ID, if deduplication is enabled.
+func (e *fifoQueueEngine) addDedupIDLocked(dedupID, messageID string, now time.Time) {
+	if e.cfg.deduplicationWindow > 0 {
+		entry := fifoQueueDedupEntry{dedupID: dedupID, messageID: messageID, expires: now.Add(e.cfg.deduplicationWindow)}
+		e.dedupIDs[dedupID] = entry
+		e.dedupExpiry = append(e.dedupExpiry, entry)
+	}
+}
+
+// setInFlightLocked marks a message as in flight, with a new receipt, until the visibility timeout has passed.
+func (e *fifoQueueEngine) setInFlightLocked(msg *fifoQueueMessage, now time.Time) {
+	e.receipts++
+	msg.receipt = e.receipts
+	msg.nacked = false
+	msg.visibleAt = now.Add(e.cfg.visibilityTimeout)
+	e.inFlight[msg.id] = msg
+	e.inFlightGroups[msg.slackChannelID] = struct{}{}
+}
+
+// deleteInFlightLocked removes a message from the in-flight messages, which unblocks its group.
+func (e *fifoQueueEngine) deleteInFlightLocked(msg *fifoQueueMessage) {
+	delete(e.inFlight, msg.id)
+	delete(e.inFlightGroups, msg.slackChannelID)
+	e.notifyLocked()
+}
+
+// removeLocked removes an in-flight message from the queue, and from the store (if any).
+// The message is removed from the queue even if the store returns an error.
+func (e *fifoQueueEngine) removeLocked(msg *fifoQueueMessage) error {
+	e.deleteInFlightLocked(msg)
+
+	if e.store != nil {
+		if err := e.store.remove(msg); err != nil {
+			return fmt.Errorf("failed to remove message from store: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// insertPendingLocked inserts a message in the pending list, keeping the list sorted by message order.
+func (e *fifoQueueEngine) insertPendingLocked(msg *fifoQueueMessage) {
+	i := sort.Search(len(e.pending), func(i int) bool { return e.pending[i].order > msg.order })
+	e.pending = append(e.pending, nil)
+	copy(e.pending[i+1:], e.pending[i:])
+	e.pending[i] = msg
+	e.notifyLocked()
+}
+
+// notifyLocked wakes up all goroutines waiting for the queue to change.
+func (e *fifoQueueEngine) notifyLocked() {
+	close(e.changed)
+	e.changed = make(chan struct{})
+}
@@ -1,9 +1,21 @@
 package types
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
+var (
+	// ErrQueueOperationNotSupported is returned by FifoQueueItem.NackWithDelay and FifoQueueItem.ExtendVisibility if
+	// the queue implementation does not support the operation.
+	ErrQueueOperationNotSupported = errors.New("operation not supported by the queue")
+
+	// ErrQueueItemExpired is returned when acknowledging (or changing the visibility of) an item that is no longer
+	// in flight, typically because its visibility timeout has passed. The message has been, or will be, redelivered.
+	ErrQueueItemExpired = errors.New("queue item is no longer in flight")
+)
+
 // FifoQueueItem represents an item received from a FIFO queue.
 type FifoQueueItem struct {
 	// MessageID is the unique identifier of the message (as defined by the queue implementation).
@@ -18,6 +30,16 @@ type FifoQueueItem struct {
 	// Body is the body of the message.
 	Body string
 
+	// SentTimestamp is the time when the message was sent to the queue.
+	SentTimestamp time.Time
+
+	// ReceiveCount is the number of times the message has been received, including this time,
+	// i.e. 1 for the first delivery and >1 for redeliveries.
+	ReceiveCount int
+
+	// Attributes are the message attributes set by the producer (see QueueMessage.Attributes), or nil if none.
+	Attributes map[string]string
+
 	// Ack acknowledges the successful processing of the message, effectively removing it from the queue.
 	// This function cannot be nil.
 	//
@@ -33,4 +55,90 @@ type FifoQueueItem struct {
 	// complete regardless of the caller's context state. Each queue implementation is responsible for
 	// managing its own timeouts and retry logic internally.
 	Nack func()
+
+	// AckFunc acknowledges the message, like Ack, but returns an error if the acknowledgement failed.
+	// It is used by AckE, and is optional: queue implementations that don't set it fall back to Ack.
+	AckFunc func() error
+
+	// NackWithDelayFunc negatively acknowledges the message, and makes it available for reprocessing after the delay.
+	// It is used by NackWithDelay, and is optional.
+	NackWithDelayFunc func(delay time.Duration) error
+
+	// NackWithErrorFunc negatively acknowledges the message, like Nack, and records the error that caused the
+	// processing to fail. It is used by NackWithError, and is optional.
+	NackWithErrorFunc func(cause error)
+
+	// ExtendVisibilityFunc hides the message from other receivers for the specified timeout (from now), e.g. as a
+	// heartbeat during long-running processing. It is used by ExtendVisibility, and is optional.
+	ExtendVisibilityFunc func(timeout time.Duration) error
+}
+
+// AckE acknowledges the successful processing of the message, like Ack, and returns an error if the
+// acknowledgement failed (e.g. ErrQueueItemExpired). A failed acknowledgement means that the message may be
+// redelivered.
+//
+// If the queue implementation does not set AckFunc, Ack is called, and nil is returned.
+func (i *FifoQueueItem) AckE() error {
+	if i.AckFunc != nil {
+		return i.AckFunc()
+	}
+
+	i.Ack()
+
+	return nil
+}
+
+// NackWithDelay negatively acknowledges the processing of the message, and makes it available for reprocessing
+// after the delay (e.g. for backoff between retries). Later messages in the same message group are not delivered
+// until then. A zero delay is the same as Nack.
+//
+// ErrQueueOperationNotSupported is returned for a non-zero delay if the queue implementation does not set
+// NackWithDelayFunc.
+func (i *FifoQueueItem) NackWithDelay(delay time.Duration) error {
+	if delay < 0 {
+		return fmt.Errorf("delay %s cannot be negative", delay)
+	}
+
+	if i.NackWithDelayFunc != nil {
+		return i.NackWithDelayFunc(delay)
+	}
+
+	if delay > 0 {
+		return ErrQueueOperationNotSupported
+	}
+
+	i.Nack()
+
+	return nil
+}
+
+// NackWithError negatively acknowledges the processing of the message, like Nack, and records the error that
+// caused the processing to fail. The last error is reported with the message if it is moved to the dead-letter queue
+// (see DeadLetter.LastError).
+//
+// If cause is nil, or the queue implementation does not set NackWithErrorFunc, Nack is called.
+func (i *FifoQueueItem) NackWithError(cause error) {
+	if cause != nil && i.NackWithErrorFunc != nil {
+		i.NackWithErrorFunc(cause)
+		return
+	}
+
+	i.Nack()
+}
+
+// ExtendVisibility hides the message from other receivers for the specified timeout, counted from now.
+// Call it periodically during long-running processing, to prevent the message from being redelivered when
+// the visibility timeout of the queue has passed.
+//
+// ErrQueueOperationNotSupported is returned if the queue implementation does not set ExtendVisibilityFunc.
+func (i *FifoQueueItem) ExtendVisibility(timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("visibility timeout %s must be positive", timeout)
+	}
+
+	if i.ExtendVisibilityFunc == nil {
+		return ErrQueueOperationNotSupported
+	}
+
+	return i.ExtendVisibilityFunc(timeout)
 }
@@ -0,0 +1,80 @@
+package types_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFifoQueueItemExtendedAPI(t *testing.T) {
+	t.Parallel()
+
+	t.Run("queue without extended funcs should fall back to Ack and Nack", func(t *testing.T) {
+		t.Parallel()
+
+		var acked, nacked int
+
+		item := &types.FifoQueueItem{
+			Ack:  func() { acked++ },
+			Nack: func() { nacked++ },
+		}
+
+		require.NoError(t, item.AckE())
+		assert.Equal(t, 1, acked)
+
+		require.NoError(t, item.NackWithDelay(0))
+		assert.Equal(t, 1, nacked)
+
+		item.NackWithError(errors.New("processing failed"))
+		assert.Equal(t, 2, nacked)
+
+		require.ErrorIs(t, item.NackWithDelay(time.Second), types.ErrQueueOperationNotSupported)
+		require.ErrorIs(t, item.ExtendVisibility(time.Second), types.ErrQueueOperationNotSupported)
+		assert.Equal(t, 2, nacked)
+	})
+
+	t.Run("extended funcs should be used when set", func(t *testing.T) {
+		t.Parallel()
+
+		ackErr := errors.New("ack failed")
+
+		var (
+			nackDelay, visibilityTimeout time.Duration
+			nackCause                    error
+		)
+
+		item := &types.FifoQueueItem{
+			Ack:                  func() { t.Error("Ack should not be called") },
+			Nack:                 func() { t.Error("Nack should not be called") },
+			AckFunc:              func() error { return ackErr },
+			NackWithDelayFunc:    func(delay time.Duration) error { nackDelay = delay; return nil },
+			NackWithErrorFunc:    func(cause error) { nackCause = cause },
+			ExtendVisibilityFunc: func(timeout time.Duration) error { visibilityTimeout = timeout; return nil },
+		}
+
+		require.ErrorIs(t, item.AckE(), ackErr)
+		require.NoError(t, item.NackWithDelay(0))
+		require.NoError(t, item.NackWithDelay(time.Second))
+		assert.Equal(t, time.Second, nackDelay)
+		require.NoError(t, item.ExtendVisibility(time.Minute))
+		assert.Equal(t, time.Minute, visibilityTimeout)
+		item.NackWithError(ackErr)
+		require.ErrorIs(t, nackCause, ackErr)
+	})
+
+	t.Run("invalid durations should be rejected", func(t *testing.T) {
+		t.Parallel()
+
+		item := &types.FifoQueueItem{
+			NackWithDelayFunc:    func(time.Duration) error { return nil },
+			ExtendVisibilityFunc: func(time.Duration) error { return nil },
+		}
+
+		require.ErrorContains(t, item.NackWithDelay(-time.Second), "cannot be negative")
+		require.ErrorContains(t, item.ExtendVisibility(0), "must be positive")
+	})
+}
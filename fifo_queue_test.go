@@ -0,0 +1,18 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentDeduplicationID(t *testing.T) {
+	t.Parallel()
+
+	id := types.ContentDeduplicationID("body")
+	assert.Len(t, id, 64)
+	assert.Equal(t, "230d8358dc8e8890b4c58deeb62912ee2f20357ae92a5cc861b98e68fe31acb5", id)
+	assert.Equal(t, id, types.ContentDeduplicationID("body"))
+	assert.NotEqual(t, id, types.ContentDeduplicationID("body "))
+}
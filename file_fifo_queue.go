@@ -0,0 +1,928 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileFifoQueueSyncMode defines when a FileFifoQueue flushes its writes to disk (fsync).
+type FileFifoQueueSyncMode string
+
+const (
+	// FileFifoQueueSyncAlways flushes every send and acknowledgement to disk before returning.
+	// This is the most durable, and the slowest, mode.
+	FileFifoQueueSyncAlways FileFifoQueueSyncMode = "always"
+
+	// FileFifoQueueSyncInterval flushes writes to disk periodically, as specified by FileFifoQueueOptions.SyncInterval.
+	// Writes since the last flush may be lost if the host crashes (but not if only the process crashes).
+	FileFifoQueueSyncInterval FileFifoQueueSyncMode = "interval"
+
+	// FileFifoQueueSyncNever leaves flushing to the operating system.
+	FileFifoQueueSyncNever FileFifoQueueSyncMode = "never"
+)
+
+const (
+	fileFifoQueueLogExt     = ".log"
+	fileFifoQueueAckExt     = ".ack"
+	fileFifoQueueReceiveExt = ".rcv"
+
+	// fileFifoQueueRecordHeaderSize is the size of the record header: the payload length and the CRC-32 checksum.
+	fileFifoQueueRecordHeaderSize = 8
+
+	// fileFifoQueueSeqSize is the size of an entry in an ack or receive file: the sequence number of the message.
+	fileFifoQueueSeqSize = 8
+)
+
+// errFileFifoQueueRecordIncomplete is returned when decoding a record that is cut off by the end of the data.
+var errFileFifoQueueRecordIncomplete = errors.New("incomplete record")
+
+// FileFifoQueueSyncModeIsValid returns true if the provided FileFifoQueueSyncMode is valid.
+func FileFifoQueueSyncModeIsValid(m FileFifoQueueSyncMode) bool {
+	switch m {
+	case FileFifoQueueSyncAlways, FileFifoQueueSyncInterval, FileFifoQueueSyncNever:
+		return true
+	}
+	return false
+}
+
+// FileFifoQueueOptions defines the behavior of a FileFifoQueue.
+type FileFifoQueueOptions struct {
+	// BufferSize is the maximum number of messages that can be stored in the queue, including received
+	// messages that are not yet acknowledged.
+	BufferSize int
+
+	// WriteTimeout is the maximum time to wait for space in the queue when sending a message.
+	WriteTimeout time.Duration
+
+	// VisibilityTimeout is the time a received message is hidden from other receivers. If the message is not
+	// acknowledged within the visibility timeout, it is redelivered.
+	VisibilityTimeout time.Duration
+
+	// MaxReceiveCount is the maximum number of times a message is received before it is moved to the
+	// dead-letter queue, instead of being redelivered. Zero means no limit.
+	// Receive counts are persisted, so that a message that crashes the process is dead-lettered after MaxReceiveCount
+	// restarts. When a restored message has already reached MaxReceiveCount, it is dead-lettered without being
+	// delivered again.
+	MaxReceiveCount int
+
+	// DeduplicationWindow is the time window in which messages with the same deduplication ID are delivered only once.
+	// Zero disables deduplication.
+	DeduplicationWindow time.Duration
+
+	// DeadLetterQueue receives the messages that have been received MaxReceiveCount times without being acknowledged.
+	// If nil, such messages are dropped.
+	DeadLetterQueue DeadLetterQueue
+
+	// Logger logs failures that are not returned to the caller, such as messages that cannot be added to the
+	// dead-letter queue (they are retried after the visibility timeout). NoopLogger is used if nil.
+	Logger Logger
+
+	// SegmentSize is the size (in bytes) after which a new log segment is started.
+	// Segments are deleted when all their messages have been acknowledged, and the deduplication window of their
+	// last message has passed.
+	SegmentSize int64
+
+	// SyncMode defines when writes are flushed to disk.
+	SyncMode FileFifoQueueSyncMode
+
+	// SyncInterval is the flush interval, when SyncMode is FileFifoQueueSyncInterval.
+	SyncInterval time.Duration
+}
+
+// DefaultFileFifoQueueOptions returns a new FileFifoQueueOptions with the default settings.
+func DefaultFileFifoQueueOptions() *FileFifoQueueOptions {
+	return &FileFifoQueueOptions{
+		BufferSize:          10000,
+		WriteTimeout:        time.Second,
+		VisibilityTimeout:   DefaultFifoQueueVisibilityTimeout,
+		DeduplicationWindow: DefaultFifoQueueDeduplicationWindow,
+		SegmentSize:         16 * 1024 * 1024,
+		SyncMode:            FileFifoQueueSyncInterval,
+		SyncInterval:        time.Second,
+	}
+}
+
+// Validate returns an error if the options are invalid.
+func (o *FileFifoQueueOptions) Validate() error {
+	if o == nil {
+		return errors.New("file queue options are nil")
+	}
+
+	if o.BufferSize <= 0 {
+		return errors.New("bufferSize must be >0")
+	}
+
+	if o.WriteTimeout <= 0 {
+		return errors.New("writeTimeout must be >0")
+	}
+
+	if o.VisibilityTimeout <= 0 {
+		return errors.New("visibilityTimeout must be >0")
+	}
+
+	if o.MaxReceiveCount < 0 {
+		return errors.New("maxReceiveCount must be >=0")
+	}
+
+	if o.DeduplicationWindow < 0 {
+		return errors.New("deduplicationWindow must be >=0")
+	}
+
+	if o.SegmentSize <= 0 {
+		return errors.New("segmentSize must be >0")
+	}
+
+	if !FileFifoQueueSyncModeIsValid(o.SyncMode) {
+		return fmt.Errorf("syncMode '%s' is not valid", o.SyncMode)
+	}
+
+	if o.SyncMode == FileFifoQueueSyncInterval && o.SyncInterval <= 0 {
+		return errors.New("syncInterval must be >0 when syncMode is 'interval'")
+	}
+
+	return nil
+}
+
+// FileFifoQueue is a durable FIFO queue, stored in a directory on the local disk.
+// It is intended for single-node deployments and local development, where running an external queue is not desired.
+//
+// FileFifoQueue has the same semantics as InMemoryFifoQueue (message groups by Slack channel ID, redelivery,
+// max receive count with dead-letter queue, deduplication and delayed delivery). In addition, it survives process restarts:
+// messages are appended to a segmented log, and acknowledgements and receives are appended to sidecar ack and receive
+// files per segment. When the queue is opened, all messages that have not been acknowledged are restored (in-flight
+// messages are redelivered, and keep their receive count), as are the deduplication IDs within the deduplication window.
+// Segments are deleted when all their messages have been acknowledged, and the deduplication window of their last
+// message has passed.
+//
+// Opening the queue fails if a segment is corrupt. Only a partially written record at the end of the last segment
+// (i.e. an interrupted write) is removed automatically.
+//
+// The directory must not be shared by multiple FileFifoQueue instances, or by multiple processes.
+// Close must be called when the queue is no longer used.
+type FileFifoQueue struct {
+	name   string
+	store  *fileFifoQueueStore
+	engine *fifoQueueEngine
+	stop   chan struct{}
+	wg     sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// fileFifoQueueStore is the fifoQueueStore of a FileFifoQueue, i.e. the segmented log on disk.
+type fileFifoQueueStore struct {
+	dir  string
+	opts FileFifoQueueOptions
+
+	mu       sync.Mutex
+	segments []*fileFifoQueueSegment
+	dirty    bool
+	closed   bool
+}
+
+// fileFifoQueueSegment is a log segment, with its ack file.
+type fileFifoQueueSegment struct {
+	// base is the sequence number of the first message in the segment, which is also the name of the segment files.
+	base int64
+
+	// log is the open log file. Only the last (active) segment has an open log file.
+	log *os.File
+
+	// ack is the open ack file, if any acknowledgements have been written since the queue was opened.
+	ack *os.File
+
+	// rcv is the open receive file, if any receives have been written since the queue was opened.
+	rcv *os.File
+
+	size    int64
+	unacked int
+
+	// lastSentAt is the latest sent timestamp of the messages in the segment. The segment is kept until the
+	// deduplication window has passed since then, so that the deduplication IDs are restored when the queue is opened.
+	lastSentAt time.Time
+}
+
+// fileFifoQueueState is the queue state restored from disk.
+type fileFifoQueueState struct {
+	// pending contains the messages that have not been acknowledged, in send order.
+	pending []*fifoQueueMessage
+
+	// dedupIDs maps the deduplication IDs sent within the deduplication window to the sent message.
+	dedupIDs map[string]fifoQueueDedupEntry
+
+	// seq is the highest sequence number found.
+	seq int64
+}
+
+// fileFifoQueueRecord is a message, as stored in a log segment.
+type fileFifoQueueRecord struct {
+	Seq            int64     `json:"seq"`
+	ID             string    `json:"id"`
+	SlackChannelID string    `json:"slackChannelId"`
+	DedupID        string    `json:"dedupId"`
+	Body           string    `json:"body"`
+	SentTimestamp  time.Time `json:"sentTimestamp"`
+
+	// VisibleTimestamp is the time when a delayed message becomes visible (zero if the message is not delayed).
+	VisibleTimestamp time.Time `json:"visibleTimestamp,omitzero"`
+
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// NewFileFifoQueue opens (or creates) a FileFifoQueue in the specified directory, and restores any messages that
+// have not been acknowledged.
+// name is the name of the queue (for logging purposes only).
+// The default options are used if opts is nil.
+func NewFileFifoQueue(name, dir string, opts *FileFifoQueueOptions) (*FileFifoQueue, error) {
+	if opts == nil {
+		opts = DefaultFileFifoQueueOptions()
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid file queue options: %w", err)
+	}
+
+	store, state, err := openFileFifoQueueStore(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &FileFifoQueue{
+		name:  name,
+		store: store,
+		stop:  make(chan struct{}),
+	}
+
+	cfg := fifoQueueConfig{
+		bufferSize:          opts.BufferSize,
+		writeTimeout:        opts.WriteTimeout,
+		visibilityTimeout:   opts.VisibilityTimeout,
+		maxReceiveCount:     opts.MaxReceiveCount,
+		deduplicationWindow: opts.DeduplicationWindow,
+		deadLetterQueue:     opts.DeadLetterQueue,
+		logger:              opts.Logger,
+		source:              q,
+	}
+
+	q.engine = newFifoQueueEngine(cfg, store)
+
+	q.engine.restore(state.pending, state.dedupIDs, state.seq)
+
+	if opts.SyncMode == FileFifoQueueSyncInterval {
+		q.wg.Add(1)
+		go q.syncPeriodically(opts.SyncInterval)
+	}
+
+	return q, nil
+}
+
+// Name returns the name of the queue.
+func (q *FileFifoQueue) Name() string {
+	return q.name
+}
+
+// Send sends a message to the queue.
+// Duplicate messages (same deduplication ID within the deduplication window) are accepted, but not delivered.
+// If dedupID is empty, content-based deduplication is used.
+// An error is returned if the context is canceled, the write timeout is reached, or the message cannot be written
+// to disk (e.g. because the queue is closed).
+func (q *FileFifoQueue) Send(ctx context.Context, slackChannelID, dedupID, body string) error {
+	_, err := q.engine.send(ctx, &QueueMessage{SlackChannelID: slackChannelID, DedupID: dedupID, Body: body})
+	return err
+}
+
+// SendWithDelay sends a message to the queue, which becomes visible to receivers after the specified delay.
+// An error is returned if the delay is negative, the context is canceled or the write timeout is reached.
+func (q *FileFifoQueue) SendWithDelay(ctx context.Context, slackChannelID, dedupID, body string, delay time.Duration) error {
+	_, err := q.engine.send(ctx, &QueueMessage{SlackChannelID: slackChannelID, DedupID: dedupID, Body: body, Delay: delay})
+	return err
+}
+
+// SendMessage sends a message to the queue, with optional message attributes.
+// An error is returned if the message is invalid, the context is canceled or the write timeout is reached.
+func (q *FileFifoQueue) SendMessage(ctx context.Context, msg *QueueMessage) error {
+	_, err := q.engine.send(ctx, msg)
+	return err
+}
+
+// SendBatch sends up to MaxQueueBatchSize messages to the queue, in order, and returns the result for each message.
+// An error is returned if the batch is empty or too large, or the context is canceled before the batch is sent.
+func (q *FileFifoQueue) SendBatch(ctx context.Context, msgs []QueueMessage) ([]SendResult, error) {
+	return q.engine.sendBatch(ctx, msgs)
+}
+
+// Receive receives messages from the queue, to the specified sink channel.
+// An error is returned if the context is canceled.
+// The sink channel is closed when the function returns.
+func (q *FileFifoQueue) Receive(ctx context.Context, sinkCh chan<- *FifoQueueItem) error {
+	return q.engine.receive(ctx, sinkCh)
+}
+
+// ReceiveBatch returns up to maxItems (at most MaxQueueBatchSize) available messages. If no message is available,
+// it waits up to the wait duration for a message, and returns an empty slice if none becomes available.
+// At most one message is returned per message group, since the next message in a group is not delivered until
+// the previous message has been acknowledged.
+// An error is returned if the context is canceled.
+func (q *FileFifoQueue) ReceiveBatch(ctx context.Context, maxItems int, wait time.Duration) ([]*FifoQueueItem, error) {
+	return q.engine.receiveBatch(ctx, maxItems, wait)
+}
+
+// Close flushes all writes to disk, and closes the queue files.
+// Sending to a closed queue fails, and acknowledgements after Close are not persisted (the messages are
+// redelivered when the queue is opened again). Close is idempotent.
+func (q *FileFifoQueue) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.stop)
+		q.wg.Wait()
+
+		q.closeErr = q.store.close()
+	})
+
+	return q.closeErr
+}
+
+func (q *FileFifoQueue) syncPeriodically(interval time.Duration) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+			_ = q.store.sync()
+		}
+	}
+}
+
+// openFileFifoQueueStore opens the log segments in the directory, and returns the restored queue state.
+//
+// Segments that are no longer needed (see isObsolete) are deleted, and a partially written record at the end
+// of the last segment (e.g. after a crash) is truncated. An error is returned if a segment is corrupt.
+func openFileFifoQueueStore(dir string, opts *FileFifoQueueOptions) (*fileFifoQueueStore, *fileFifoQueueState, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read queue directory: %w", err)
+	}
+
+	var bases []int64
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasSuffix(name, fileFifoQueueLogExt) {
+			continue
+		}
+
+		base, err := strconv.ParseInt(strings.TrimSuffix(name, fileFifoQueueLogExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		bases = append(bases, base)
+	}
+
+	sort.Slice(bases, func(i, j int) bool { return bases[i] < bases[j] })
+
+	store := &fileFifoQueueStore{dir: dir, opts: *opts}
+	state := &fileFifoQueueState{dedupIDs: make(map[string]fifoQueueDedupEntry)}
+
+	now := time.Now()
+
+	for i, base := range bases {
+		seg := &fileFifoQueueSegment{base: base}
+
+		if err := store.readSegment(seg, state, i == len(bases)-1); err != nil {
+			store.closeFiles()
+			return nil, nil, err
+		}
+
+		if store.isObsolete(seg, now) {
+			if err := store.deleteSegment(seg); err != nil {
+				store.closeFiles()
+				return nil, nil, err
+			}
+
+			continue
+		}
+
+		if i == len(bases)-1 {
+			seg.log, err = os.OpenFile(store.logPath(base), os.O_WRONLY|os.O_APPEND, 0o600)
+			if err != nil {
+				store.closeFiles()
+				return nil, nil, fmt.Errorf("failed to open queue segment: %w", err)
+			}
+		}
+
+		store.segments = append(store.segments, seg)
+	}
+
+	return store, state, nil
+}
+
+// readSegment reads the messages, acknowledgements and receives of a segment, and adds them to the state.
+// Acknowledged messages are only used to restore the deduplication IDs.
+// A partially written record is only truncated at the end of the last segment, since it can only be the result of an
+// interrupted write there. An incomplete record followed by a complete record (e.g. a corrupt length header) is not
+// a partially written record. Any other invalid record is reported as an error.
+func (s *fileFifoQueueStore) readSegment(seg *fileFifoQueueSegment, state *fileFifoQueueState, last bool) error {
+	acked, err := s.readSequences(s.ackPath(seg.base))
+	if err != nil {
+		return err
+	}
+
+	received, err := s.readSequences(s.receivePath(seg.base))
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(s.logPath(seg.base))
+	if err != nil {
+		return fmt.Errorf("failed to read queue segment: %w", err)
+	}
+
+	now := time.Now()
+	offset := 0
+
+	for offset < len(data) {
+		record, n, err := decodeFileFifoQueueRecord(data[offset:])
+		if err != nil {
+			incomplete := errors.Is(err, errFileFifoQueueRecordIncomplete)
+			followed := incomplete && containsFileFifoQueueRecord(data[offset+1:])
+
+			if !last || (!incomplete && offset+n < len(data)) || followed {
+				return fmt.Errorf("queue segment %s is corrupt at offset %d: %w", s.logPath(seg.base), offset, err)
+			}
+
+			// Remove the partially written record, so that new records can be appended after the last complete record
+			if err := os.Truncate(s.logPath(seg.base), int64(offset)); err != nil {
+				return fmt.Errorf("failed to truncate queue segment: %w", err)
+			}
+
+			break
+		}
+
+		offset += n
+		state.seq = max(state.seq, record.Seq)
+
+		if record.SentTimestamp.After(seg.lastSentAt) {
+			seg.lastSentAt = record.SentTimestamp
+		}
+
+		if expires := record.SentTimestamp.Add(s.opts.DeduplicationWindow); s.opts.DeduplicationWindow > 0 && expires.After(now) {
+			state.dedupIDs[record.DedupID] = fifoQueueDedupEntry{messageID: record.ID, expires: expires}
+		}
+
+		if acked[record.Seq] > 0 {
+			continue
+		}
+
+		seg.unacked++
+
+		state.pending = append(state.pending, &fifoQueueMessage{
+			seq:            record.Seq,
+			id:             record.ID,
+			slackChannelID: record.SlackChannelID,
+			dedupID:        record.DedupID,
+			body:           record.Body,
+			sentAt:         record.SentTimestamp,
+			receiveCount:   received[record.Seq],
+			visibleAt:      record.VisibleTimestamp,
+			attributes:     record.Attributes,
+		})
+	}
+
+	seg.size = int64(offset)
+
+	return nil
+}
+
+// readSequences reads an ack or receive file, and returns the number of entries per sequence number.
+func (s *fileFifoQueueStore) readSequences(path string) (map[int64]int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[int64]int{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read queue file: %w", err)
+	}
+
+	// Remove a partially written entry, so that new entries are aligned
+	if rest := len(data) % fileFifoQueueSeqSize; rest != 0 {
+		data = data[:len(data)-rest]
+
+		if err := os.Truncate(path, int64(len(data))); err != nil {
+			return nil, fmt.Errorf("failed to truncate queue file: %w", err)
+		}
+	}
+
+	counts := make(map[int64]int, len(data)/fileFifoQueueSeqSize)
+
+	for i := 0; i < len(data); i += fileFifoQueueSeqSize {
+		counts[int64(binary.BigEndian.Uint64(data[i:]))]++
+	}
+
+	return counts, nil
+}
+
+// append writes a message to the active segment, starting a new segment if needed.
+func (s *fileFifoQueueStore) append(msg *fifoQueueMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("queue is closed")
+	}
+
+	data, err := encodeFileFifoQueueRecord(&fileFifoQueueRecord{
+		Seq:              msg.seq,
+		ID:               msg.id,
+		SlackChannelID:   msg.slackChannelID,
+		DedupID:          msg.dedupID,
+		Body:             msg.body,
+		SentTimestamp:    msg.sentAt,
+		VisibleTimestamp: msg.visibleAt,
+		Attributes:       msg.attributes,
+	})
+	if err != nil {
+		return err
+	}
+
+	seg := s.activeSegment()
+
+	if seg == nil || seg.size >= s.opts.SegmentSize {
+		if seg, err = s.startSegment(msg.seq); err != nil {
+			return err
+		}
+	}
+
+	if _, err := seg.log.Write(data); err != nil {
+		// Remove any partially written data, so that the segment stays readable
+		_ = seg.log.Truncate(seg.size)
+		return fmt.Errorf("failed to write to queue segment: %w", err)
+	}
+
+	seg.size += int64(len(data))
+	seg.unacked++
+
+	if msg.sentAt.After(seg.lastSentAt) {
+		seg.lastSentAt = msg.sentAt
+	}
+
+	return s.syncIfNeeded(seg.log)
+}
+
+// remove writes an acknowledgement to the ack file of the message segment, and deletes the segments that are no
+// longer needed.
+func (s *fileFifoQueueStore) remove(msg *fifoQueueMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("queue is closed")
+	}
+
+	i, err := s.segmentIndex(msg)
+	if err != nil {
+		return err
+	}
+
+	seg := s.segments[i]
+
+	if err := s.appendSequence(&seg.ack, s.ackPath(seg.base), msg.seq); err != nil {
+		return fmt.Errorf("failed to write to queue ack file: %w", err)
+	}
+
+	seg.unacked--
+
+	if err := s.syncIfNeeded(seg.ack); err != nil {
+		return err
+	}
+
+	return s.deleteObsoleteSegmentsLocked(time.Now())
+}
+
+// receive writes a receive to the receive file of the message segment, so that the receive count of the message
+// is restored when the queue is opened again.
+func (s *fileFifoQueueStore) receive(msg *fifoQueueMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("queue is closed")
+	}
+
+	i, err := s.segmentIndex(msg)
+	if err != nil {
+		return err
+	}
+
+	seg := s.segments[i]
+
+	if err := s.appendSequence(&seg.rcv, s.receivePath(seg.base), msg.seq); err != nil {
+		return fmt.Errorf("failed to write to queue receive file: %w", err)
+	}
+
+	return s.syncIfNeeded(seg.rcv)
+}
+
+// segmentIndex returns the index of the segment holding the message.
+func (s *fileFifoQueueStore) segmentIndex(msg *fifoQueueMessage) (int, error) {
+	i := sort.Search(len(s.segments), func(i int) bool { return s.segments[i].base > msg.seq }) - 1
+	if i < 0 {
+		return 0, fmt.Errorf("no queue segment found for message %s", msg.id)
+	}
+
+	return i, nil
+}
+
+// appendSequence appends a sequence number to an ack or receive file, opening the file first if f is nil.
+func (s *fileFifoQueueStore) appendSequence(f **os.File, path string, seq int64) error {
+	if *f == nil {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+
+		*f = file
+	}
+
+	var data [fileFifoQueueSeqSize]byte
+	binary.BigEndian.PutUint64(data[:], uint64(seq))
+
+	_, err := (*f).Write(data[:])
+
+	return err
+}
+
+// activeSegment returns the last segment, if it is open for writing.
+func (s *fileFifoQueueStore) activeSegment() *fileFifoQueueSegment {
+	if len(s.segments) == 0 {
+		return nil
+	}
+
+	if seg := s.segments[len(s.segments)-1]; seg.log != nil {
+		return seg
+	}
+
+	return nil
+}
+
+// startSegment closes the log file of the active segment (if any), and creates a new segment.
+func (s *fileFifoQueueStore) startSegment(base int64) (*fileFifoQueueSegment, error) {
+	if active := s.activeSegment(); active != nil {
+		if err := active.log.Sync(); err != nil {
+			return nil, fmt.Errorf("failed to sync queue segment: %w", err)
+		}
+
+		if err := active.log.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close queue segment: %w", err)
+		}
+
+		active.log = nil
+
+		if err := s.deleteObsoleteSegmentsLocked(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(s.logPath(base), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queue segment: %w", err)
+	}
+
+	seg := &fileFifoQueueSegment{base: base, log: f}
+	s.segments = append(s.segments, seg)
+
+	return seg, nil
+}
+
+// isObsolete returns true if the segment is no longer needed: all its messages have been acknowledged, and the
+// deduplication window of its last message has passed.
+func (s *fileFifoQueueStore) isObsolete(seg *fileFifoQueueSegment, now time.Time) bool {
+	return seg.unacked == 0 && !seg.lastSentAt.Add(s.opts.DeduplicationWindow).After(now)
+}
+
+// deleteObsoleteSegmentsLocked deletes the segments that are no longer needed.
+// The active segment is only deleted when it is full, since new messages are appended to it.
+func (s *fileFifoQueueStore) deleteObsoleteSegmentsLocked(now time.Time) error {
+	active := s.activeSegment()
+	segments := s.segments[:0]
+
+	var firstErr error
+
+	for _, seg := range s.segments {
+		if !s.isObsolete(seg, now) || (seg == active && seg.size < s.opts.SegmentSize) {
+			segments = append(segments, seg)
+			continue
+		}
+
+		if err := s.deleteSegment(seg); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	clear(s.segments[len(segments):])
+	s.segments = segments
+
+	return firstErr
+}
+
+// deleteSegment closes and deletes the files of a segment.
+func (s *fileFifoQueueStore) deleteSegment(seg *fileFifoQueueSegment) error {
+	seg.closeFiles()
+
+	if err := os.Remove(s.logPath(seg.base)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete queue segment: %w", err)
+	}
+
+	if err := os.Remove(s.ackPath(seg.base)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete queue ack file: %w", err)
+	}
+
+	if err := os.Remove(s.receivePath(seg.base)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete queue receive file: %w", err)
+	}
+
+	return nil
+}
+
+// syncIfNeeded flushes the file to disk in FileFifoQueueSyncAlways mode, or marks the store as dirty.
+func (s *fileFifoQueueStore) syncIfNeeded(f *os.File) error {
+	if s.opts.SyncMode != FileFifoQueueSyncAlways {
+		s.dirty = true
+		return nil
+	}
+
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync queue file: %w", err)
+	}
+
+	return nil
+}
+
+// sync flushes all open files to disk, if anything has been written since the last flush.
+func (s *fileFifoQueueStore) sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.syncLocked()
+}
+
+func (s *fileFifoQueueStore) syncLocked() error {
+	if !s.dirty || s.closed {
+		return nil
+	}
+
+	var errs []error
+
+	for _, seg := range s.segments {
+		for _, f := range []*os.File{seg.log, seg.ack, seg.rcv} {
+			if f != nil {
+				errs = append(errs, f.Sync())
+			}
+		}
+	}
+
+	s.dirty = false
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to sync queue files: %w", err)
+	}
+
+	return nil
+}
+
+// close flushes and closes all files.
+func (s *fileFifoQueueStore) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dirty = true
+	err := s.syncLocked()
+
+	s.closeFiles()
+	s.closed = true
+
+	return err
+}
+
+func (s *fileFifoQueueStore) closeFiles() {
+	for _, seg := range s.segments {
+		seg.closeFiles()
+	}
+}
+
+func (s *fileFifoQueueStore) logPath(base int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", base, fileFifoQueueLogExt))
+}
+
+func (s *fileFifoQueueStore) ackPath(base int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", base, fileFifoQueueAckExt))
+}
+
+func (s *fileFifoQueueStore) receivePath(base int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", base, fileFifoQueueReceiveExt))
+}
+
+func (seg *fileFifoQueueSegment) closeFiles() {
+	if seg.log != nil {
+		_ = seg.log.Close()
+		seg.log = nil
+	}
+
+	if seg.ack != nil {
+		_ = seg.ack.Close()
+		seg.ack = nil
+	}
+
+	if seg.rcv != nil {
+		_ = seg.rcv.Close()
+		seg.rcv = nil
+	}
+}
+
+// encodeFileFifoQueueRecord encodes a record as a header (payload length and CRC-32 checksum), followed by the
+// JSON payload.
+func encodeFileFifoQueueRecord(record *fileFifoQueueRecord) ([]byte, error) {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode queue record: %w", err)
+	}
+
+	data := make([]byte, fileFifoQueueRecordHeaderSize, fileFifoQueueRecordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(payload))
+
+	return append(data, payload...), nil
+}
+
+// decodeFileFifoQueueRecord decodes the first record in data, and returns the record and its encoded size.
+// errFileFifoQueueRecordIncomplete is returned if the record is cut off by the end of the data. If the record is
+// corrupt, an error is returned together with the encoded size, as read from the record header.
+func decodeFileFifoQueueRecord(data []byte) (*fileFifoQueueRecord, int, error) {
+	if len(data) < fileFifoQueueRecordHeaderSize {
+		return nil, 0, errFileFifoQueueRecordIncomplete
+	}
+
+	size := int(binary.BigEndian.Uint32(data[0:4]))
+	end := fileFifoQueueRecordHeaderSize + size
+
+	if len(data) < end {
+		return nil, 0, errFileFifoQueueRecordIncomplete
+	}
+
+	payload := data[fileFifoQueueRecordHeaderSize:end]
+
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(data[4:8]) {
+		return nil, end, errors.New("record checksum mismatch")
+	}
+
+	var record fileFifoQueueRecord
+
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return nil, end, fmt.Errorf("failed to decode record: %w", err)
+	}
+
+	return &record, end, nil
+}
+
+// containsFileFifoQueueRecord returns true if a complete, valid record can be decoded anywhere in data.
+// Only the offsets where a record payload may start are checked: JSON-encoded records start with the sequence number,
+// and that prefix cannot occur inside a JSON string.
+func containsFileFifoQueueRecord(data []byte) bool {
+	prefix := []byte(`{"seq":`)
+
+	for i := fileFifoQueueRecordHeaderSize; i < len(data); i++ {
+		j := bytes.Index(data[i:], prefix)
+		if j < 0 {
+			return false
+		}
+
+		i += j
+
+		if _, _, err := decodeFileFifoQueueRecord(data[i-fileFifoQueueRecordHeaderSize:]); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,490 @@
+package types_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/queuetests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileFifoQueueCompliance(t *testing.T) {
+	t.Parallel()
+
+	queuetests.RunAllTests(t, func(t *testing.T) types.FifoQueue {
+		t.Helper()
+		return openFileFifoQueue(t, t.TempDir(), nil)
+	})
+}
+
+func TestFileFifoQueueOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, types.DefaultFileFifoQueueOptions().Validate())
+
+	var opts *types.FileFifoQueueOptions
+	require.Error(t, opts.Validate())
+
+	opts = types.DefaultFileFifoQueueOptions()
+	opts.SegmentSize = 0
+	require.ErrorContains(t, opts.Validate(), "segmentSize")
+
+	opts = types.DefaultFileFifoQueueOptions()
+	opts.SyncMode = "sometimes"
+	require.ErrorContains(t, opts.Validate(), "syncMode 'sometimes' is not valid")
+
+	opts = types.DefaultFileFifoQueueOptions()
+	opts.SyncInterval = 0
+	require.ErrorContains(t, opts.Validate(), "syncInterval")
+
+	opts.SyncMode = types.FileFifoQueueSyncNever
+	require.NoError(t, opts.Validate())
+
+	_, err := types.NewFileFifoQueue("alerts", t.TempDir(), &types.FileFifoQueueOptions{})
+	require.ErrorContains(t, err, "invalid file queue options")
+
+	assert.True(t, types.FileFifoQueueSyncModeIsValid(types.FileFifoQueueSyncAlways))
+	assert.False(t, types.FileFifoQueueSyncModeIsValid(""))
+}
+
+func TestFileFifoQueuePersistence(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unacknowledged items should survive a restart", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		queue := openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+		require.NoError(t, queue.Send(ctx, "C000000002", "dedupID_3", "body_3"))
+
+		items := startFileReceiver(ctx, t, queue)
+		item := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_1", item.Body)
+		item.Ack()
+
+		// body_2 or body_3 is received, but not acknowledged before the restart
+		receiveInMemoryItem(t, items)
+
+		cancel()
+		require.NoError(t, queue.Close())
+
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+
+		queue = openFileFifoQueue(t, dir, nil)
+		items = startFileReceiver(ctx, t, queue)
+
+		var bodies []string
+
+		for range 2 {
+			item := receiveInMemoryItem(t, items)
+			bodies = append(bodies, item.Body)
+			item.Ack()
+		}
+
+		assert.ElementsMatch(t, []string{"body_2", "body_3"}, bodies)
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+	})
+
+	t.Run("deduplication IDs should survive a restart", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		queue := openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Close())
+
+		queue = openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		items := startFileReceiver(ctx, t, queue)
+		item := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_1", item.Body)
+		item.Ack()
+		item = receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_2", item.Body)
+		item.Ack()
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+	})
+
+	t.Run("acknowledged segments should be deleted", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultFileFifoQueueOptions()
+		opts.SegmentSize = 1
+		opts.DeduplicationWindow = 0
+		queue := openFileFifoQueue(t, dir, opts)
+
+		for _, body := range []string{"body_1", "body_2", "body_3"} {
+			require.NoError(t, queue.Send(ctx, "C000000001", "", body))
+		}
+
+		assert.Len(t, filesWithExt(t, dir, ".log"), 3)
+
+		items := startFileReceiver(ctx, t, queue)
+
+		for range 2 {
+			receiveInMemoryItem(t, items).Ack()
+		}
+
+		assert.Len(t, filesWithExt(t, dir, ".log"), 1)
+		assert.Empty(t, filesWithExt(t, dir, ".ack"))
+
+		receiveInMemoryItem(t, items).Ack()
+		assert.Empty(t, filesWithExt(t, dir, ".log"))
+	})
+
+	t.Run("deduplication IDs of acknowledged items should survive a restart", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultFileFifoQueueOptions()
+		opts.SegmentSize = 1
+		queue := openFileFifoQueue(t, dir, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items, err := queue.ReceiveBatch(ctx, 1, time.Second)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.NoError(t, items[0].AckE())
+		require.NoError(t, queue.Close())
+
+		// The segment is kept until the deduplication window has passed
+		assert.Len(t, filesWithExt(t, dir, ".log"), 1)
+
+		queue = openFileFifoQueue(t, dir, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+
+		received := startFileReceiver(ctx, t, queue)
+		item := receiveInMemoryItem(t, received)
+		assert.Equal(t, "body_2", item.Body)
+		item.Ack()
+		expectNoInMemoryItem(t, received, 50*time.Millisecond)
+	})
+
+	t.Run("acknowledged segments should be deleted after the deduplication window", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultFileFifoQueueOptions()
+		opts.SegmentSize = 1
+		opts.DeduplicationWindow = 50 * time.Millisecond
+		queue := openFileFifoQueue(t, dir, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+
+		items := startFileReceiver(ctx, t, queue)
+		receiveInMemoryItem(t, items).Ack()
+		assert.Len(t, filesWithExt(t, dir, ".log"), 2)
+
+		time.Sleep(50 * time.Millisecond)
+		receiveInMemoryItem(t, items).Ack()
+		assert.Empty(t, filesWithExt(t, dir, ".log"))
+	})
+
+	t.Run("partially written record should be truncated", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		queue := openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Close())
+
+		logs := filesWithExt(t, dir, ".log")
+		require.Len(t, logs, 1)
+
+		f, err := os.OpenFile(logs[0], os.O_WRONLY|os.O_APPEND, 0o600)
+		require.NoError(t, err)
+		_, err = f.WriteString("\x00\x00\x01")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		queue = openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+		require.NoError(t, queue.Close())
+
+		queue = openFileFifoQueue(t, dir, nil)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		items := startFileReceiver(ctx, t, queue)
+		item := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_1", item.Body)
+		item.Ack()
+		assert.Equal(t, "body_2", receiveInMemoryItem(t, items).Body)
+	})
+
+	t.Run("corrupt record followed by valid records should fail to open", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		queue := openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+		require.NoError(t, queue.Close())
+
+		logs := filesWithExt(t, dir, ".log")
+		require.Len(t, logs, 1)
+
+		data, err := os.ReadFile(logs[0])
+		require.NoError(t, err)
+		copy(data[bytes.Index(data, []byte("body_1")):], "BODY_1")
+		require.NoError(t, os.WriteFile(logs[0], data, 0o600))
+
+		_, err = types.NewFileFifoQueue("alerts", dir, nil)
+		require.ErrorContains(t, err, "is corrupt at offset 0: record checksum mismatch")
+
+		// The segment should be left untouched
+		corrupt, err := os.ReadFile(logs[0])
+		require.NoError(t, err)
+		assert.Equal(t, data, corrupt)
+	})
+
+	t.Run("corrupt record length followed by valid records should fail to open", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		queue := openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_3", "body_3"))
+		require.NoError(t, queue.Close())
+
+		logs := filesWithExt(t, dir, ".log")
+		require.Len(t, logs, 1)
+
+		// The length of the second record now runs past the end of the segment
+		data, err := os.ReadFile(logs[0])
+		require.NoError(t, err)
+		second := int(binary.BigEndian.Uint32(data[0:4])) + 8
+		binary.BigEndian.PutUint32(data[second:], uint32(len(data)))
+		require.NoError(t, os.WriteFile(logs[0], data, 0o600))
+
+		_, err = types.NewFileFifoQueue("alerts", dir, nil)
+		require.ErrorContains(t, err, fmt.Sprintf("is corrupt at offset %d: incomplete record", second))
+
+		// The segment should be left untouched
+		corrupt, err := os.ReadFile(logs[0])
+		require.NoError(t, err)
+		assert.Equal(t, data, corrupt)
+	})
+
+	t.Run("partially written record in a sealed segment should fail to open", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		opts := types.DefaultFileFifoQueueOptions()
+		opts.SegmentSize = 1
+		queue := openFileFifoQueue(t, dir, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+		require.NoError(t, queue.Close())
+
+		logs := filesWithExt(t, dir, ".log")
+		require.Len(t, logs, 2)
+
+		f, err := os.OpenFile(logs[0], os.O_WRONLY|os.O_APPEND, 0o600)
+		require.NoError(t, err)
+		_, err = f.WriteString("\x00\x00\x01")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		_, err = types.NewFileFifoQueue("alerts", dir, opts)
+		require.ErrorContains(t, err, "incomplete record")
+	})
+
+	t.Run("receive counts should survive a restart", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+		deadLetterQueue := types.NewInMemoryDeadLetterQueue("alerts-dlq")
+
+		opts := types.DefaultFileFifoQueueOptions()
+		opts.MaxReceiveCount = 2
+		opts.DeadLetterQueue = deadLetterQueue
+
+		queue := openFileFifoQueue(t, dir, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "poison"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+
+		// The item is received, but the process crashes before it is acknowledged
+		for expected := range 2 {
+			items, err := queue.ReceiveBatch(ctx, 1, time.Second)
+			require.NoError(t, err)
+			require.Len(t, items, 1)
+			assert.Equal(t, "poison", items[0].Body)
+			assert.Equal(t, expected+1, items[0].ReceiveCount)
+
+			require.NoError(t, queue.Close())
+			queue = openFileFifoQueue(t, dir, opts)
+		}
+
+		// The item has reached the max receive count, and should be dead-lettered instead of redelivered
+		items, err := queue.ReceiveBatch(ctx, 1, time.Second)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "body_2", items[0].Body)
+		assert.Equal(t, 1, items[0].ReceiveCount)
+		require.NoError(t, items[0].AckFunc())
+
+		letters, err := deadLetterQueue.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assert.Equal(t, "poison", letters[0].Body)
+		assert.Equal(t, 2, letters[0].ReceiveCount)
+		assert.Equal(t, "max receive count reached before the queue was restored", letters[0].LastError)
+	})
+
+	t.Run("sync always mode should persist items", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		opts := types.DefaultFileFifoQueueOptions()
+		opts.SyncMode = types.FileFifoQueueSyncAlways
+		queue := openFileFifoQueue(t, dir, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Close())
+
+		queue = openFileFifoQueue(t, dir, opts)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		items := startFileReceiver(ctx, t, queue)
+		assert.Equal(t, "body_1", receiveInMemoryItem(t, items).Body)
+	})
+
+	t.Run("delayed items should stay delayed after a restart", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		queue := openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.SendWithDelay(ctx, "C000000001", "dedupID_1", "body_1", 150*time.Millisecond))
+		require.NoError(t, queue.Close())
+
+		queue = openFileFifoQueue(t, dir, nil)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		items := startFileReceiver(ctx, t, queue)
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+		assert.Equal(t, "body_1", receiveInMemoryItem(t, items).Body)
+	})
+
+	t.Run("attributes and sent timestamp should survive a restart", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		queue := openFileFifoQueue(t, dir, nil)
+		require.NoError(t, queue.SendMessage(ctx, &types.QueueMessage{
+			SlackChannelID: "C000000001",
+			DedupID:        "dedupID_1",
+			Body:           "body_1",
+			Attributes:     map[string]string{types.QueueAttributeContentType: "application/json"},
+		}))
+		require.NoError(t, queue.Close())
+
+		time.Sleep(10 * time.Millisecond)
+		queue = openFileFifoQueue(t, dir, nil)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		items := startFileReceiver(ctx, t, queue)
+		item := receiveInMemoryItem(t, items)
+		assert.Equal(t, map[string]string{types.QueueAttributeContentType: "application/json"}, item.Attributes)
+		assert.Equal(t, 1, item.ReceiveCount)
+		assert.Greater(t, item.ReceiveTimestamp.Sub(item.SentTimestamp), 10*time.Millisecond)
+	})
+
+	t.Run("send to closed queue should fail", func(t *testing.T) {
+		t.Parallel()
+
+		queue := openFileFifoQueue(t, t.TempDir(), nil)
+		require.NoError(t, queue.Close())
+		require.NoError(t, queue.Close())
+		require.ErrorContains(t, queue.Send(context.Background(), "C000000001", "dedupID_1", "body_1"), "queue is closed")
+	})
+}
+
+func openFileFifoQueue(t *testing.T, dir string, opts *types.FileFifoQueueOptions) *types.FileFifoQueue {
+	t.Helper()
+
+	queue, err := types.NewFileFifoQueue("alerts", dir, opts)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = queue.Close() })
+
+	return queue
+}
+
+func startFileReceiver(ctx context.Context, t *testing.T, queue *types.FileFifoQueue) <-chan *types.FifoQueueItem {
+	t.Helper()
+
+	items := make(chan *types.FifoQueueItem)
+
+	go func() {
+		err := queue.Receive(ctx, items)
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+
+	return items
+}
+
+func filesWithExt(t *testing.T, dir, ext string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	require.NoError(t, err)
+
+	return files
+}
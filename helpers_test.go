@@ -0,0 +1,234 @@
+package types_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+	"testing"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/require"
+)
+
+// errDBUnavailable is a temporary error, returned by the faults injected in tests.
+var errDBUnavailable = &temporaryError{msg: "database unavailable"}
+
+// temporaryError is an error that reports whether it is temporary, like the errors of many database drivers.
+type temporaryError struct {
+	msg       string
+	permanent bool
+}
+
+func (e *temporaryError) Error() string { return e.msg }
+
+func (e *temporaryError) Temporary() bool { return !e.permanent }
+
+// newFaultyDB returns a FaultyDB wrapping a new InMemoryDB.
+func newFaultyDB(t *testing.T, opts *types.FaultyDBOptions) *types.FaultyDB {
+	t.Helper()
+
+	db, err := types.NewFaultyDB(types.NewInMemoryDB(), opts)
+	require.NoError(t, err)
+
+	return db
+}
+
+// newFlakyDB returns a FaultyDB wrapping a new InMemoryDB, where the first calls to the specified methods fail
+// with errDBUnavailable.
+func newFlakyDB(t *testing.T, failures int, methods ...string) *types.FaultyDB {
+	t.Helper()
+
+	var faults []types.FaultRule
+
+	for _, method := range methods {
+		for call := 1; call <= failures; call++ {
+			faults = append(faults, types.FaultRule{Method: method, Call: call, Err: errDBUnavailable})
+		}
+	}
+
+	return newFaultyDB(t, &types.FaultyDBOptions{Faults: faults})
+}
+
+// testIssue is a minimal Issue implementation.
+type testIssue struct {
+	ID            string `json:"id"`
+	Channel       string `json:"channel"`
+	CorrelationID string `json:"correlationId"`
+	PostID        string `json:"postId"`
+}
+
+func newTestIssue(id, channelID, correlationID, postID string) *testIssue {
+	return &testIssue{ID: id, Channel: channelID, CorrelationID: correlationID, PostID: postID}
+}
+
+func (i *testIssue) ChannelID() string        { return i.Channel }
+func (i *testIssue) UniqueID() string         { return i.ID }
+func (i *testIssue) GetCorrelationID() string { return i.CorrelationID }
+func (i *testIssue) IsOpen() bool             { return true }
+func (i *testIssue) CurrentPostID() string    { return i.PostID }
+
+func (i *testIssue) MarshalJSON() ([]byte, error) {
+	type alias testIssue
+
+	return json.Marshal((*alias)(i))
+}
+
+func testIssueFromJSON(t *testing.T, body json.RawMessage) *testIssue {
+	t.Helper()
+
+	var issue testIssue
+	require.NoError(t, json.Unmarshal(body, &issue))
+
+	return &issue
+}
+
+// testMoveMapping is a minimal MoveMapping implementation.
+type testMoveMapping struct {
+	Channel       string `json:"channel"`
+	CorrelationID string `json:"correlationId"`
+}
+
+func (m *testMoveMapping) ChannelID() string        { return m.Channel }
+func (m *testMoveMapping) UniqueID() string         { return m.Channel + "-" + m.CorrelationID }
+func (m *testMoveMapping) GetCorrelationID() string { return m.CorrelationID }
+
+func (m *testMoveMapping) MarshalJSON() ([]byte, error) {
+	type alias testMoveMapping
+
+	return json.Marshal((*alias)(m))
+}
+
+// versionedTestIssue is a testIssue with optimistic concurrency control.
+type versionedTestIssue struct {
+	*testIssue
+
+	version int64
+}
+
+func (i *versionedTestIssue) Version() int64 { return i.version }
+
+// unversionedDB hides the VersionedDB methods of the wrapped DB.
+type unversionedDB struct {
+	types.DB
+}
+
+// recordingMetrics is a Metrics implementation that records the registered metrics, counters and observations.
+type recordingMetrics struct {
+	types.NoopMetrics
+
+	mu         sync.Mutex
+	registered []string
+	counters   map[string]float64
+	observed   map[string]int
+}
+
+func (m *recordingMetrics) RegisterCounter(name, _ string, _ ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.registered = append(m.registered, name)
+}
+
+func (m *recordingMetrics) RegisterHistogram(name, _ string, _ []float64, _ ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.registered = append(m.registered, name)
+}
+
+func (m *recordingMetrics) CounterAdd(name string, value float64, labelValues ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.counters == nil {
+		m.counters = map[string]float64{}
+	}
+
+	m.counters[fmt.Sprint(name, labelValues)] += value
+}
+
+func (m *recordingMetrics) CounterInc(name string, labelValues ...string) {
+	m.CounterAdd(name, 1, labelValues...)
+}
+
+func (m *recordingMetrics) Observe(name string, _ float64, labelValues ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.observed == nil {
+		m.observed = map[string]int{}
+	}
+
+	m.observed[fmt.Sprint(name, labelValues)]++
+}
+
+func (m *recordingMetrics) counter(name string, labelValues ...string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counters[fmt.Sprint(name, labelValues)]
+}
+
+func (m *recordingMetrics) observations(name string, labelValues ...string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.observed[fmt.Sprint(name, labelValues)]
+}
+
+// recordingLogger is a Logger implementation that records the logged messages, with their fields.
+// Loggers created with WithField and WithFields share the entries of their parent.
+type recordingLogger struct {
+	types.NoopLogger
+
+	mu      sync.Mutex
+	fields  map[string]any
+	entries []recordedLogEntry
+	root    *recordingLogger
+}
+
+type recordedLogEntry struct {
+	msg    string
+	fields map[string]any
+}
+
+func (l *recordingLogger) Debug(msg string) {
+	l.record(msg)
+}
+
+func (l *recordingLogger) Error(msg string) {
+	l.record(msg)
+}
+
+func (l *recordingLogger) record(msg string) {
+	root := l.rootLogger()
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	root.entries = append(root.entries, recordedLogEntry{msg: msg, fields: maps.Clone(l.fields)})
+}
+
+func (l *recordingLogger) WithField(key string, value any) types.Logger { //nolint:ireturn
+	return l.WithFields(map[string]any{key: value})
+}
+
+func (l *recordingLogger) WithFields(fields map[string]any) types.Logger { //nolint:ireturn
+	merged := maps.Clone(l.fields)
+	if merged == nil {
+		merged = map[string]any{}
+	}
+
+	maps.Copy(merged, fields)
+
+	return &recordingLogger{fields: merged, root: l.rootLogger()}
+}
+
+func (l *recordingLogger) rootLogger() *recordingLogger {
+	if l.root != nil {
+		return l.root
+	}
+
+	return l
+}
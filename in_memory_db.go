@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,7 +9,7 @@ import (
 	"sync"
 )
 
-// InMemoryDB is an in-memory implementation of the DB interface.
+// InMemoryDB is an in-memory implementation of the DB and VersionedDB interfaces.
 // For TEST purposes only! Do not use in production!
 type InMemoryDB struct {
 	mu                      sync.RWMutex
@@ -24,6 +25,11 @@ type inMemoryIssueRecord struct {
 	postID        string
 	isOpen        bool
 	body          json.RawMessage
+	version       int64
+}
+
+func (r *inMemoryIssueRecord) stored(id string) *StoredIssue {
+	return &StoredIssue{ID: id, Body: bytes.Clone(r.body), Version: r.version}
 }
 
 // NewInMemoryDB creates a new InMemoryDB instance.
@@ -62,43 +68,76 @@ func (db *InMemoryDB) SaveAlert(_ context.Context, alert *Alert) error {
 }
 
 // SaveIssue creates or updates a single issue.
-func (db *InMemoryDB) SaveIssue(_ context.Context, issue Issue) error {
-	if issue == nil {
-		return errors.New("issue is nil")
-	}
+// Returns a *ConflictError if the issue is a VersionedIssue with a version that does not match the stored issue.
+func (db *InMemoryDB) SaveIssue(ctx context.Context, issue Issue) error {
+	return db.SaveIssues(ctx, issue)
+}
 
-	body, err := issue.MarshalJSON()
-	if err != nil {
-		return fmt.Errorf("failed to marshal issue: %w", err)
+// SaveIssues creates or updates multiple issues.
+// The issues are saved atomically: if any issue is invalid or conflicts, none of the issues are saved.
+func (db *InMemoryDB) SaveIssues(_ context.Context, issues ...Issue) error {
+	records := make([]*inMemoryIssueRecord, len(issues))
+
+	for i, issue := range issues {
+		if issue == nil {
+			return errors.New("issue is nil")
+		}
+
+		body, err := issue.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal issue: %w", err)
+		}
+
+		records[i] = &inMemoryIssueRecord{
+			channelID:     issue.ChannelID(),
+			correlationID: issue.GetCorrelationID(),
+			postID:        issue.CurrentPostID(),
+			isOpen:        issue.IsOpen(),
+			body:          body,
+		}
 	}
 
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
-	db.issues[issue.UniqueID()] = &inMemoryIssueRecord{
-		channelID:     issue.ChannelID(),
-		correlationID: issue.GetCorrelationID(),
-		postID:        issue.CurrentPostID(),
-		isOpen:        issue.IsOpen(),
-		body:          body,
-	}
-
-	return nil
-}
+	// The same issue may be saved more than once, so the versions are checked against the pending writes
+	versions := make(map[string]int64, len(issues))
 
-// SaveIssues creates or updates multiple issues.
-func (db *InMemoryDB) SaveIssues(ctx context.Context, issues ...Issue) error {
 	for _, issue := range issues {
-		if err := db.SaveIssue(ctx, issue); err != nil {
+		id := issue.UniqueID()
+
+		version, ok := versions[id]
+		if !ok {
+			if existing, exists := db.issues[id]; exists {
+				version = existing.version
+			}
+		}
+
+		if err := checkIssueVersion(issue, version); err != nil {
 			return err
 		}
+
+		versions[id] = version + 1
+	}
+
+	for i, issue := range issues {
+		id := issue.UniqueID()
+		version := int64(1)
+
+		if existing, ok := db.issues[id]; ok {
+			version = existing.version + 1
+		}
+
+		records[i].version = version
+		db.issues[id] = records[i]
 	}
 
 	return nil
 }
 
 // MoveIssue moves an issue from one channel to another.
-// Returns an error if sourceChannelID and targetChannelID are the same.
+// Returns an error if sourceChannelID and targetChannelID are the same, and a *ConflictError if the issue
+// is a VersionedIssue with a version that does not match the stored issue.
 // If the issue does not exist in the store, this is a no-op.
 func (db *InMemoryDB) MoveIssue(_ context.Context, issue Issue, sourceChannelID, targetChannelID string) error {
 	if sourceChannelID == targetChannelID {
@@ -118,6 +157,16 @@ func (db *InMemoryDB) MoveIssue(_ context.Context, issue Issue, sourceChannelID,
 	defer db.mu.Unlock()
 
 	record, ok := db.issues[issue.UniqueID()]
+
+	var version int64
+	if ok {
+		version = record.version
+	}
+
+	if err := checkIssueVersion(issue, version); err != nil {
+		return err
+	}
+
 	if !ok {
 		return nil
 	}
@@ -126,54 +175,65 @@ func (db *InMemoryDB) MoveIssue(_ context.Context, issue Issue, sourceChannelID,
 	record.postID = issue.CurrentPostID()
 	record.isOpen = issue.IsOpen()
 	record.body = body
+	record.version++
 
 	return nil
 }
 
 // FindOpenIssueByCorrelationID finds a single open issue by channel ID and correlation ID.
 // Returns an error if channelID or correlationID are empty, or if multiple open issues match.
-func (db *InMemoryDB) FindOpenIssueByCorrelationID(_ context.Context, channelID, correlationID string) (string, json.RawMessage, error) {
+func (db *InMemoryDB) FindOpenIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (string, json.RawMessage, error) {
+	return unversionedIssue(db.FindOpenVersionedIssueByCorrelationID(ctx, channelID, correlationID))
+}
+
+// FindOpenVersionedIssueByCorrelationID finds a single open issue by channel ID and correlation ID, with its version.
+// Returns an error if channelID or correlationID are empty, or if multiple open issues match.
+func (db *InMemoryDB) FindOpenVersionedIssueByCorrelationID(_ context.Context, channelID, correlationID string) (*StoredIssue, error) {
 	if channelID == "" {
-		return "", nil, errors.New("channelID is required")
+		return nil, errors.New("channelID is required")
 	}
 
 	if correlationID == "" {
-		return "", nil, errors.New("correlationID is required")
+		return nil, errors.New("correlationID is required")
 	}
 
 	db.mu.RLock()
 	defer db.mu.RUnlock()
 
-	var foundID string
-	var foundRecord *inMemoryIssueRecord
+	var found *StoredIssue
 
 	for id, record := range db.issues {
 		if record.channelID == channelID && record.correlationID == correlationID && record.isOpen {
-			if foundRecord != nil {
-				return "", nil, fmt.Errorf("multiple open issues found for channel %q and correlationID %q", channelID, correlationID)
+			if found != nil {
+				return nil, fmt.Errorf("multiple open issues found for channel %q and correlationID %q", channelID, correlationID)
 			}
 
-			foundID = id
-			foundRecord = record
+			found = record.stored(id)
 		}
 	}
 
-	if foundRecord == nil {
-		return "", nil, nil
+	if found == nil {
+		return nil, nil //nolint:nilnil // DB interface contract: return nil, nil when not found
 	}
 
-	return foundID, foundRecord.body, nil
+	return found, nil
 }
 
 // FindIssueBySlackPostID finds a single issue by channel ID and Slack post ID.
 // Returns an error if channelID or postID are empty.
-func (db *InMemoryDB) FindIssueBySlackPostID(_ context.Context, channelID, postID string) (string, json.RawMessage, error) {
+func (db *InMemoryDB) FindIssueBySlackPostID(ctx context.Context, channelID, postID string) (string, json.RawMessage, error) {
+	return unversionedIssue(db.FindVersionedIssueBySlackPostID(ctx, channelID, postID))
+}
+
+// FindVersionedIssueBySlackPostID finds a single issue by channel ID and Slack post ID, with its version.
+// Returns an error if channelID or postID are empty.
+func (db *InMemoryDB) FindVersionedIssueBySlackPostID(_ context.Context, channelID, postID string) (*StoredIssue, error) {
 	if channelID == "" {
-		return "", nil, errors.New("channelID is required")
+		return nil, errors.New("channelID is required")
 	}
 
 	if postID == "" {
-		return "", nil, errors.New("postID is required")
+		return nil, errors.New("postID is required")
 	}
 
 	db.mu.RLock()
@@ -181,11 +241,11 @@ func (db *InMemoryDB) FindIssueBySlackPostID(_ context.Context, channelID, postI
 
 	for id, record := range db.issues {
 		if record.channelID == channelID && record.postID == postID {
-			return id, record.body, nil
+			return record.stored(id), nil
 		}
 	}
 
-	return "", nil, nil
+	return nil, nil //nolint:nilnil // DB interface contract: return nil, nil when not found
 }
 
 // FindActiveChannels returns a list of all channels that have at least one open issue.
@@ -210,15 +270,25 @@ func (db *InMemoryDB) FindActiveChannels(_ context.Context) ([]string, error) {
 }
 
 // LoadOpenIssuesInChannel loads all open issues for the specified channel.
-func (db *InMemoryDB) LoadOpenIssuesInChannel(_ context.Context, channelID string) (map[string]json.RawMessage, error) {
+func (db *InMemoryDB) LoadOpenIssuesInChannel(ctx context.Context, channelID string) (map[string]json.RawMessage, error) {
+	issues, err := db.LoadOpenVersionedIssuesInChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return unversionedIssues(issues), nil
+}
+
+// LoadOpenVersionedIssuesInChannel loads all open issues for the specified channel, with their versions.
+func (db *InMemoryDB) LoadOpenVersionedIssuesInChannel(_ context.Context, channelID string) (map[string]*StoredIssue, error) {
 	db.mu.RLock()
 	defer db.mu.RUnlock()
 
-	result := make(map[string]json.RawMessage)
+	result := make(map[string]*StoredIssue)
 
 	for id, record := range db.issues {
 		if record.channelID == channelID && record.isOpen {
-			result[id] = record.body
+			result[id] = record.stored(id)
 		}
 	}
 
@@ -1,10 +1,14 @@
 package types_test
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/slackmgr/types"
 	"github.com/slackmgr/types/dbtests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInMemoryDB(t *testing.T) {
@@ -12,3 +16,65 @@ func TestInMemoryDB(t *testing.T) {
 
 	dbtests.RunAllTests(t, types.NewInMemoryDB())
 }
+
+func TestInMemoryDBSaveIssuesIsAtomic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := types.NewInMemoryDB()
+
+	existing := newTestIssue("issue-1", "C000000001", "corr-1", "")
+	require.NoError(t, db.SaveIssue(ctx, existing))
+
+	created := newTestIssue("issue-2", "C000000001", "corr-2", "")
+
+	err := db.SaveIssues(ctx,
+		&versionedTestIssue{testIssue: created, version: 0},
+		&versionedTestIssue{testIssue: existing, version: 0},
+	)
+	require.ErrorIs(t, err, types.ErrConflict)
+	require.EqualError(t, err, "issue issue-1 has version 1, expected version 0: issue version conflict")
+
+	id, _, err := db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-2")
+	require.NoError(t, err)
+	assert.Empty(t, id, "no issues should be saved if any issue conflicts")
+
+	// Saving the same issue twice in one call checks the second write against the first
+	require.NoError(t, db.SaveIssues(ctx,
+		&versionedTestIssue{testIssue: existing, version: 1},
+		&versionedTestIssue{testIssue: existing, version: 2},
+	))
+
+	stored, err := db.FindOpenVersionedIssueByCorrelationID(ctx, "C000000001", "corr-1")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, int64(3), stored.Version)
+}
+
+func TestInMemoryDBVersionedLookupsReturnCopies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := types.NewInMemoryDB()
+
+	require.NoError(t, db.SaveIssue(ctx, newTestIssue("issue-1", "C000000001", "corr-1", "1700000000.000001")))
+
+	stored, err := db.FindOpenVersionedIssueByCorrelationID(ctx, "C000000001", "corr-1")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	stored.Body[0] = 'x'
+
+	stored, err = db.FindVersionedIssueBySlackPostID(ctx, "C000000001", "1700000000.000001")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	stored.Body[0] = 'x'
+
+	issues, err := db.LoadOpenVersionedIssuesInChannel(ctx, "C000000001")
+	require.NoError(t, err)
+	require.Contains(t, issues, "issue-1")
+	issues["issue-1"].Body[0] = 'x'
+
+	_, body, err := db.FindOpenIssueByCorrelationID(ctx, "C000000001", "corr-1")
+	require.NoError(t, err)
+	assert.True(t, json.Valid(body), "modifying a returned issue should not modify the stored issue")
+}
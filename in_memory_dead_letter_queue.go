@@ -0,0 +1,203 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// InMemoryDeadLetterQueue is an in-memory DeadLetterQueue implementation, to be used with InMemoryFifoQueue or
+// FileFifoQueue. The dead letters are lost when the process exits.
+// For TEST purposes only! Do not use in production!
+type InMemoryDeadLetterQueue struct {
+	name    string
+	mu      sync.Mutex
+	letters []*inMemoryDeadLetter
+}
+
+// inMemoryDeadLetter is a dead letter stored in an InMemoryDeadLetterQueue, with its source queue.
+type inMemoryDeadLetter struct {
+	letter *DeadLetter
+	source FifoQueue
+}
+
+// NewInMemoryDeadLetterQueue creates a new, empty InMemoryDeadLetterQueue.
+// name is the name of the dead-letter queue (for logging purposes only).
+//
+// For TEST purposes only! Do not use in production!
+func NewInMemoryDeadLetterQueue(name string) *InMemoryDeadLetterQueue {
+	return &InMemoryDeadLetterQueue{
+		name: name,
+	}
+}
+
+// Name returns the name of the dead-letter queue.
+func (q *InMemoryDeadLetterQueue) Name() string {
+	return q.name
+}
+
+// Add adds a dead letter, moved from the source queue.
+// A dead letter with the same message ID replaces the existing one.
+func (q *InMemoryDeadLetterQueue) Add(ctx context.Context, source FifoQueue, letter *DeadLetter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if source == nil {
+		return errors.New("source queue cannot be nil")
+	}
+
+	if letter == nil || letter.MessageID == "" {
+		return errors.New("dead letter must have a message ID")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored := &inMemoryDeadLetter{letter: cloneDeadLetter(letter), source: source}
+
+	if i := q.indexLocked(letter.MessageID); i >= 0 {
+		q.letters[i] = stored
+	} else {
+		q.letters = append(q.letters, stored)
+	}
+
+	return nil
+}
+
+// List returns all dead letters, in the order they were added.
+func (q *InMemoryDeadLetterQueue) List(_ context.Context) ([]*DeadLetter, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letters := make([]*DeadLetter, 0, len(q.letters))
+
+	for _, stored := range q.letters {
+		letters = append(letters, cloneDeadLetter(stored.letter))
+	}
+
+	return letters, nil
+}
+
+// Get returns the dead letter with the specified message ID.
+// Returns nil without an error if no dead letter is found.
+func (q *InMemoryDeadLetterQueue) Get(_ context.Context, messageID string) (*DeadLetter, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.indexLocked(messageID)
+	if i < 0 {
+		return nil, nil //nolint:nilnil // DeadLetterQueue interface contract: return nil, nil when not found
+	}
+
+	return cloneDeadLetter(q.letters[i].letter), nil
+}
+
+// Redrive sends the dead letter with the specified message ID back to its source queue, and removes it from the
+// dead-letter queue.
+// The lock is not held while sending. If the same dead letter is redriven concurrently, the redriven messages have
+// the same deduplication ID, so the message is only delivered once within the deduplication window.
+func (q *InMemoryDeadLetterQueue) Redrive(ctx context.Context, messageID string) error {
+	q.mu.Lock()
+	i := q.indexLocked(messageID)
+
+	if i < 0 {
+		q.mu.Unlock()
+		return fmt.Errorf("dead letter %s not found", messageID)
+	}
+
+	stored := q.letters[i]
+	q.mu.Unlock()
+
+	if err := redrive(ctx, stored); err != nil {
+		return err
+	}
+
+	q.remove(stored)
+
+	return nil
+}
+
+// RedriveAll redrives all dead letters, in the order they were added, and returns the number of redriven dead letters.
+// Dead letters added while redriving are not redriven.
+func (q *InMemoryDeadLetterQueue) RedriveAll(ctx context.Context) (int, error) {
+	q.mu.Lock()
+	letters := slices.Clone(q.letters)
+	q.mu.Unlock()
+
+	var err error
+
+	count := 0
+
+	for _, stored := range letters {
+		if err = redrive(ctx, stored); err != nil {
+			break
+		}
+
+		count++
+	}
+
+	q.remove(letters[:count]...)
+
+	return count, err
+}
+
+// Delete removes the dead letter with the specified message ID, without redriving it.
+func (q *InMemoryDeadLetterQueue) Delete(_ context.Context, messageID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if i := q.indexLocked(messageID); i >= 0 {
+		q.letters = slices.Delete(q.letters, i, i+1)
+	}
+
+	return nil
+}
+
+// remove removes the stored dead letters. Dead letters that have been replaced (by an Add with the same message ID)
+// or deleted in the meantime are left as they are.
+func (q *InMemoryDeadLetterQueue) remove(letters ...*inMemoryDeadLetter) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	removed := make(map[*inMemoryDeadLetter]struct{}, len(letters))
+
+	for _, stored := range letters {
+		removed[stored] = struct{}{}
+	}
+
+	q.letters = slices.DeleteFunc(q.letters, func(stored *inMemoryDeadLetter) bool {
+		_, ok := removed[stored]
+		return ok
+	})
+}
+
+func (q *InMemoryDeadLetterQueue) indexLocked(messageID string) int {
+	return slices.IndexFunc(q.letters, func(stored *inMemoryDeadLetter) bool { return stored.letter.MessageID == messageID })
+}
+
+// redrive sends a stored dead letter back to its source queue.
+func redrive(ctx context.Context, stored *inMemoryDeadLetter) error {
+	msg := &QueueMessage{
+		SlackChannelID: stored.letter.SlackChannelID,
+		DedupID:        "redrive-" + stored.letter.MessageID,
+		Body:           stored.letter.Body,
+		Attributes:     maps.Clone(stored.letter.Attributes),
+	}
+
+	if err := stored.source.SendMessage(ctx, msg); err != nil {
+		return fmt.Errorf("failed to redrive dead letter %s to queue %s: %w", stored.letter.MessageID, stored.source.Name(), err)
+	}
+
+	return nil
+}
+
+func cloneDeadLetter(letter *DeadLetter) *DeadLetter {
+	c := *letter
+	c.Attributes = maps.Clone(letter.Attributes)
+
+	return &c
+}
@@ -0,0 +1,255 @@
+package types_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/queuetests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryDeadLetterQueueCompliance(t *testing.T) {
+	t.Parallel()
+
+	queuetests.RunAllDeadLetterQueueTests(t, func(t *testing.T) types.DeadLetterQueue {
+		t.Helper()
+		return types.NewInMemoryDeadLetterQueue("alerts-dlq")
+	})
+}
+
+func TestInMemoryDeadLetterQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("failing item should be dead-lettered, and processed after redrive", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		deadLetterQueue := types.NewInMemoryDeadLetterQueue("alerts-dlq")
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.MaxReceiveCount = 2
+		opts.VisibilityTimeout = 50 * time.Millisecond
+		opts.DeadLetterQueue = deadLetterQueue
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "poison"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		// The first delivery fails with an error, the second times out (e.g. the processor panicked)
+		first := receiveInMemoryItem(t, items)
+		assert.Equal(t, "poison", first.Body)
+		first.NackWithError(errors.New("invalid alert"))
+		assert.Equal(t, "poison", receiveInMemoryItem(t, items).Body)
+
+		// The next item in the channel should be delivered once the failing item has been dead-lettered
+		item := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_2", item.Body)
+		item.Ack()
+
+		letter, err := deadLetterQueue.Get(ctx, first.MessageID)
+		require.NoError(t, err)
+		require.NotNil(t, letter)
+		assert.Equal(t, "poison", letter.Body)
+		assert.Equal(t, 2, letter.ReceiveCount)
+		assert.Equal(t, "visibility timeout expired", letter.LastError)
+
+		require.NoError(t, deadLetterQueue.Redrive(ctx, first.MessageID))
+
+		item = receiveInMemoryItem(t, items)
+		assert.Equal(t, "poison", item.Body)
+		assert.Equal(t, 1, item.ReceiveCount)
+		assert.NotEqual(t, first.MessageID, item.MessageID)
+		item.Ack()
+
+		letters, err := deadLetterQueue.List(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, letters)
+	})
+
+	t.Run("nack reasons should be reported as last error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		deadLetterQueue := types.NewInMemoryDeadLetterQueue("alerts-dlq")
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.MaxReceiveCount = 1
+		opts.DeadLetterQueue = deadLetterQueue
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000002", "dedupID_2", "body_2"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		for range 2 {
+			item := receiveInMemoryItem(t, items)
+			if item.Body == "body_1" {
+				item.NackWithError(errors.New("invalid alert"))
+			} else {
+				item.Nack()
+			}
+		}
+
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+
+		letters, err := deadLetterQueue.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, letters, 2)
+
+		reasons := map[string]string{}
+		for _, letter := range letters {
+			reasons[letter.Body] = letter.LastError
+		}
+
+		assert.Equal(t, map[string]string{"body_1": "invalid alert", "body_2": "message was nacked"}, reasons)
+	})
+
+	t.Run("dead-letter queue errors should be logged, and retried after the visibility timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		deadLetterQueue := &failingDeadLetterQueue{InMemoryDeadLetterQueue: types.NewInMemoryDeadLetterQueue("alerts-dlq")}
+		deadLetterQueue.fail.Store(true)
+		logger := &recordingLogger{}
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.MaxReceiveCount = 1
+		opts.VisibilityTimeout = 25 * time.Millisecond
+		opts.DeadLetterQueue = deadLetterQueue
+		opts.Logger = logger
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "poison"))
+		require.NoError(t, queue.Send(ctx, "C000000002", "dedupID_2", "body_2"))
+
+		items, err := queue.ReceiveBatch(ctx, 2, time.Second)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.NoError(t, items[1].AckFunc())
+
+		// The failing dead-letter queue should not end the receive
+		time.Sleep(50 * time.Millisecond)
+		items, err = queue.ReceiveBatch(ctx, 1, 0)
+		require.NoError(t, err)
+		assert.Empty(t, items)
+
+		logger.mu.Lock()
+		require.NotEmpty(t, logger.entries)
+		entry := logger.entries[0]
+		logger.mu.Unlock()
+		assert.Equal(t, "Failed to add message to dead-letter queue", entry.msg)
+		assert.Equal(t, errDeadLetterQueueUnavailable.Error(), entry.fields["error"])
+
+		deadLetterQueue.fail.Store(false)
+		time.Sleep(50 * time.Millisecond)
+		_, err = queue.ReceiveBatch(ctx, 1, 0)
+		require.NoError(t, err)
+
+		letters, err := deadLetterQueue.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assert.Equal(t, "poison", letters[0].Body)
+	})
+
+	t.Run("source queue should be able to use the dead-letter queue while redriving", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		deadLetterQueue := types.NewInMemoryDeadLetterQueue("alerts-dlq")
+		source := &listingFifoQueue{InMemoryFifoQueue: types.NewInMemoryFifoQueue("alerts", 10, time.Second), deadLetterQueue: deadLetterQueue}
+
+		require.NoError(t, deadLetterQueue.Add(ctx, source, &types.DeadLetter{MessageID: "msg-1", SlackChannelID: "C000000001", Body: "body_1"}))
+		require.NoError(t, deadLetterQueue.Add(ctx, source, &types.DeadLetter{MessageID: "msg-2", SlackChannelID: "C000000002", Body: "body_2"}))
+
+		require.NoError(t, deadLetterQueue.Redrive(ctx, "msg-1"))
+
+		count, err := deadLetterQueue.RedriveAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		assert.Equal(t, []int{2, 1}, source.listed)
+
+		letters, err := deadLetterQueue.List(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, letters)
+	})
+
+	t.Run("returned dead letters should be copies", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		deadLetterQueue := types.NewInMemoryDeadLetterQueue("alerts-dlq")
+		source := types.NewInMemoryFifoQueue("alerts", 10, time.Second)
+
+		letter := &types.DeadLetter{MessageID: "msg-1", Body: "body_1", Attributes: map[string]string{"a": "1"}}
+		require.NoError(t, deadLetterQueue.Add(ctx, source, letter))
+		letter.Attributes["a"] = "2"
+
+		stored, err := deadLetterQueue.Get(ctx, "msg-1")
+		require.NoError(t, err)
+		assert.Equal(t, "1", stored.Attributes["a"])
+
+		stored.Body = "changed"
+		stored, err = deadLetterQueue.Get(ctx, "msg-1")
+		require.NoError(t, err)
+		assert.Equal(t, "body_1", stored.Body)
+	})
+
+	t.Run("invalid dead letters should be rejected", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		deadLetterQueue := types.NewInMemoryDeadLetterQueue("alerts-dlq")
+		source := types.NewInMemoryFifoQueue("alerts", 10, time.Second)
+
+		require.Error(t, deadLetterQueue.Add(ctx, nil, &types.DeadLetter{MessageID: "msg-1"}))
+		require.Error(t, deadLetterQueue.Add(ctx, source, nil))
+		require.Error(t, deadLetterQueue.Add(ctx, source, &types.DeadLetter{}))
+	})
+}
+
+var errDeadLetterQueueUnavailable = errors.New("dead-letter queue unavailable")
+
+// failingDeadLetterQueue is an InMemoryDeadLetterQueue where Add fails while fail is set.
+type failingDeadLetterQueue struct {
+	*types.InMemoryDeadLetterQueue
+
+	fail atomic.Bool
+}
+
+func (q *failingDeadLetterQueue) Add(ctx context.Context, source types.FifoQueue, letter *types.DeadLetter) error {
+	if q.fail.Load() {
+		return errDeadLetterQueueUnavailable
+	}
+
+	return q.InMemoryDeadLetterQueue.Add(ctx, source, letter)
+}
+
+// listingFifoQueue is an InMemoryFifoQueue that lists the dead letters in the dead-letter queue on every send, and
+// records the number of dead letters found.
+type listingFifoQueue struct {
+	*types.InMemoryFifoQueue
+
+	deadLetterQueue types.DeadLetterQueue
+	listed          []int
+}
+
+func (q *listingFifoQueue) SendMessage(ctx context.Context, msg *types.QueueMessage) error {
+	letters, err := q.deadLetterQueue.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	q.listed = append(q.listed, len(letters))
+
+	return q.InMemoryFifoQueue.SendMessage(ctx, msg)
+}
@@ -3,31 +3,145 @@ package types
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
-
-	"github.com/google/uuid"
 )
 
+// InMemoryFifoQueueOptions defines the behavior of an InMemoryFifoQueue.
+type InMemoryFifoQueueOptions struct {
+	// BufferSize is the maximum number of messages that can be stored in the queue, including received
+	// messages that are not yet acknowledged.
+	BufferSize int
+
+	// WriteTimeout is the maximum time to wait for space in the queue when sending a message.
+	WriteTimeout time.Duration
+
+	// VisibilityTimeout is the time a received message is hidden from other receivers. If the message is not
+	// acknowledged within the visibility timeout, it is redelivered.
+	VisibilityTimeout time.Duration
+
+	// MaxReceiveCount is the maximum number of times a message is received before it is moved to the
+	// dead-letter queue, instead of being redelivered. Zero means no limit.
+	MaxReceiveCount int
+
+	// DeduplicationWindow is the time window in which messages with the same deduplication ID are delivered only once.
+	// Zero disables deduplication.
+	DeduplicationWindow time.Duration
+
+	// DeadLetterQueue receives the messages that have been received MaxReceiveCount times without being acknowledged.
+	// If nil, such messages are dropped.
+	DeadLetterQueue DeadLetterQueue
+
+	// Logger logs failures that are not returned to the caller, such as messages that cannot be added to the
+	// dead-letter queue (they are retried after the visibility timeout). NoopLogger is used if nil.
+	Logger Logger
+}
+
+// DefaultInMemoryFifoQueueOptions returns a new InMemoryFifoQueueOptions with the default settings.
+func DefaultInMemoryFifoQueueOptions() *InMemoryFifoQueueOptions {
+	return &InMemoryFifoQueueOptions{
+		BufferSize:          1000,
+		WriteTimeout:        time.Second,
+		VisibilityTimeout:   DefaultFifoQueueVisibilityTimeout,
+		DeduplicationWindow: DefaultFifoQueueDeduplicationWindow,
+	}
+}
+
+// Validate returns an error if the options are invalid.
+func (o *InMemoryFifoQueueOptions) Validate() error {
+	if o == nil {
+		return errors.New("in-memory queue options are nil")
+	}
+
+	if o.BufferSize <= 0 {
+		return errors.New("bufferSize must be >0")
+	}
+
+	if o.WriteTimeout <= 0 {
+		return errors.New("writeTimeout must be >0")
+	}
+
+	if o.VisibilityTimeout <= 0 {
+		return errors.New("visibilityTimeout must be >0")
+	}
+
+	if o.MaxReceiveCount < 0 {
+		return errors.New("maxReceiveCount must be >=0")
+	}
+
+	if o.DeduplicationWindow < 0 {
+		return errors.New("deduplicationWindow must be >=0")
+	}
+
+	return nil
+}
+
 // InMemoryFifoQueue is an in-memory FIFO queue implementation
 // For TEST purposes only! Do not use in production!
+//
+// The queue models the semantics of SQS FIFO queues, with the Slack channel ID as message group:
+// messages in a group are delivered in order, and a message is not delivered while an earlier message in the same
+// group is in flight (received, but not yet acknowledged). Messages in different groups are delivered independently.
+// Nacked messages are redelivered immediately, received messages that are not acknowledged within the visibility
+// timeout are redelivered, and messages that have been received MaxReceiveCount times are moved to the dead-letter queue.
+// Messages with a deduplication ID that has been sent within the deduplication window are accepted, but not delivered.
 type InMemoryFifoQueue struct {
-	name         string
-	items        chan *FifoQueueItem
-	writeTimeout time.Duration
+	name   string
+	engine *fifoQueueEngine
 }
 
 // NewInMemoryFifoQueue creates a new InMemoryFifoQueue instance.
 // name is the name of the queue (for logging purposes only).
-// bufferSize is the maximum number of items that can be stored in the queue.
+// bufferSize is the maximum number of items that can be stored in the queue. Since items are stored until they are
+// acknowledged, a bufferSize of zero (or less) is treated as 1.
 // writeTimeout is the maximum time to wait for writing an item to the queue.
+// The default options are used for all other settings. Use NewInMemoryFifoQueueWithOptions to validate the settings.
 //
 // For TEST purposes only! Do not use in production!
 func NewInMemoryFifoQueue(name string, bufferSize int, writeTimeout time.Duration) *InMemoryFifoQueue {
-	return &InMemoryFifoQueue{
-		name:         name,
-		items:        make(chan *FifoQueueItem, bufferSize),
-		writeTimeout: writeTimeout,
+	opts := DefaultInMemoryFifoQueueOptions()
+	opts.BufferSize = max(bufferSize, 1)
+	opts.WriteTimeout = writeTimeout
+
+	return newInMemoryFifoQueue(name, opts)
+}
+
+// NewInMemoryFifoQueueWithOptions creates a new InMemoryFifoQueue instance with the specified options.
+// name is the name of the queue (for logging purposes only).
+// The default options are used if opts is nil. An error is returned if the options are invalid.
+//
+// For TEST purposes only! Do not use in production!
+func NewInMemoryFifoQueueWithOptions(name string, opts *InMemoryFifoQueueOptions) (*InMemoryFifoQueue, error) {
+	if opts == nil {
+		opts = DefaultInMemoryFifoQueueOptions()
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid in-memory queue options: %w", err)
 	}
+
+	return newInMemoryFifoQueue(name, opts), nil
+}
+
+func newInMemoryFifoQueue(name string, opts *InMemoryFifoQueueOptions) *InMemoryFifoQueue {
+	q := &InMemoryFifoQueue{
+		name: name,
+	}
+
+	cfg := fifoQueueConfig{
+		bufferSize:          opts.BufferSize,
+		writeTimeout:        opts.WriteTimeout,
+		visibilityTimeout:   opts.VisibilityTimeout,
+		maxReceiveCount:     opts.MaxReceiveCount,
+		deduplicationWindow: opts.DeduplicationWindow,
+		deadLetterQueue:     opts.DeadLetterQueue,
+		logger:              opts.Logger,
+		source:              q,
+	}
+
+	q.engine = newFifoQueueEngine(cfg, nil)
+
+	return q
 }
 
 // Name returns the name of the queue.
@@ -36,43 +150,46 @@ func (q *InMemoryFifoQueue) Name() string {
 }
 
 // Send sends a message to the queue.
+// Duplicate messages (same deduplication ID within the deduplication window) are accepted, but not delivered.
+// If dedupID is empty, content-based deduplication is used.
 // An error is returned if the context is canceled or the write timeout is reached.
-func (q *InMemoryFifoQueue) Send(ctx context.Context, slackChannelID, _, body string) error {
-	item := &FifoQueueItem{
-		MessageID:        uuid.New().String(),
-		SlackChannelID:   slackChannelID,
-		ReceiveTimestamp: time.Now(),
-		Body:             body,
-		Ack:              func() {},
-		Nack:             func() {},
-	}
+func (q *InMemoryFifoQueue) Send(ctx context.Context, slackChannelID, dedupID, body string) error {
+	_, err := q.engine.send(ctx, &QueueMessage{SlackChannelID: slackChannelID, DedupID: dedupID, Body: body})
+	return err
+}
 
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(q.writeTimeout):
-		return errors.New("timeout while writing to queue")
-	case q.items <- item:
-		return nil
-	}
+// SendWithDelay sends a message to the queue, which becomes visible to receivers after the specified delay.
+// An error is returned if the delay is negative, the context is canceled or the write timeout is reached.
+func (q *InMemoryFifoQueue) SendWithDelay(ctx context.Context, slackChannelID, dedupID, body string, delay time.Duration) error {
+	_, err := q.engine.send(ctx, &QueueMessage{SlackChannelID: slackChannelID, DedupID: dedupID, Body: body, Delay: delay})
+	return err
+}
+
+// SendMessage sends a message to the queue, with optional message attributes.
+// An error is returned if the message is invalid, the context is canceled or the write timeout is reached.
+func (q *InMemoryFifoQueue) SendMessage(ctx context.Context, msg *QueueMessage) error {
+	_, err := q.engine.send(ctx, msg)
+	return err
+}
+
+// SendBatch sends up to MaxQueueBatchSize messages to the queue, in order, and returns the result for each message.
+// An error is returned if the batch is empty or too large, or the context is canceled before the batch is sent.
+func (q *InMemoryFifoQueue) SendBatch(ctx context.Context, msgs []QueueMessage) ([]SendResult, error) {
+	return q.engine.sendBatch(ctx, msgs)
 }
 
 // Receive receives messages from the queue, to the specified sink channel.
 // An error is returned if the context is canceled.
 // The sink channel is closed when the function returns.
 func (q *InMemoryFifoQueue) Receive(ctx context.Context, sinkCh chan<- *FifoQueueItem) error {
-	defer close(sinkCh)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case item := <-q.items:
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case sinkCh <- item:
-			}
-		}
-	}
+	return q.engine.receive(ctx, sinkCh)
+}
+
+// ReceiveBatch returns up to maxItems (at most MaxQueueBatchSize) available messages. If no message is available,
+// it waits up to the wait duration for a message, and returns an empty slice if none becomes available.
+// At most one message is returned per message group, since the next message in a group is not delivered until
+// the previous message has been acknowledged.
+// An error is returned if the context is canceled.
+func (q *InMemoryFifoQueue) ReceiveBatch(ctx context.Context, maxItems int, wait time.Duration) ([]*FifoQueueItem, error) {
+	return q.engine.receiveBatch(ctx, maxItems, wait)
 }
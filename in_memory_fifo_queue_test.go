@@ -2,14 +2,574 @@ package types_test
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/queuetests"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func TestInMemoryFifoQueueCompliance(t *testing.T) {
+	t.Parallel()
+
+	newQueue := func(_ *testing.T) types.FifoQueue {
+		return types.NewInMemoryFifoQueue("alerts", 100, time.Second)
+	}
+
+	queuetests.RunAllTests(t, newQueue)
+}
+
+func TestDefaultInMemoryFifoQueueOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := types.DefaultInMemoryFifoQueueOptions()
+	assert.Equal(t, types.DefaultFifoQueueVisibilityTimeout, opts.VisibilityTimeout)
+	assert.Positive(t, opts.BufferSize)
+	assert.Positive(t, opts.WriteTimeout)
+	assert.Equal(t, types.DefaultFifoQueueDeduplicationWindow, opts.DeduplicationWindow)
+	assert.Zero(t, opts.MaxReceiveCount)
+	assert.Nil(t, opts.DeadLetterQueue)
+
+	queue, err := types.NewInMemoryFifoQueueWithOptions("alerts", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "alerts", queue.Name())
+}
+
+func TestInMemoryFifoQueueOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, types.DefaultInMemoryFifoQueueOptions().Validate())
+
+	var nilOpts *types.InMemoryFifoQueueOptions
+	require.Error(t, nilOpts.Validate())
+
+	tests := map[string]func(o *types.InMemoryFifoQueueOptions){
+		"bufferSize must be >0":           func(o *types.InMemoryFifoQueueOptions) { o.BufferSize = 0 },
+		"writeTimeout must be >0":         func(o *types.InMemoryFifoQueueOptions) { o.WriteTimeout = 0 },
+		"visibilityTimeout must be >0":    func(o *types.InMemoryFifoQueueOptions) { o.VisibilityTimeout = 0 },
+		"maxReceiveCount must be >=0":     func(o *types.InMemoryFifoQueueOptions) { o.MaxReceiveCount = -1 },
+		"deduplicationWindow must be >=0": func(o *types.InMemoryFifoQueueOptions) { o.DeduplicationWindow = -time.Second },
+	}
+
+	for expected, modify := range tests {
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		modify(opts)
+		require.ErrorContains(t, opts.Validate(), expected)
+
+		_, err := types.NewInMemoryFifoQueueWithOptions("alerts", opts)
+		require.ErrorContains(t, err, "invalid in-memory queue options: "+expected)
+	}
+}
+
+func newInMemoryFifoQueue(t *testing.T, opts *types.InMemoryFifoQueueOptions) *types.InMemoryFifoQueue {
+	t.Helper()
+
+	queue, err := types.NewInMemoryFifoQueueWithOptions("alerts", opts)
+	require.NoError(t, err)
+
+	return queue
+}
+
+func TestInMemoryFifoQueueRedelivery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nacked item should be redelivered", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 10, time.Second)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		first := receiveInMemoryItem(t, items)
+		first.Nack()
+
+		second := receiveInMemoryItem(t, items)
+		assert.Equal(t, first.MessageID, second.MessageID)
+		assert.Equal(t, "body_1", second.Body)
+		second.Ack()
+
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+	})
+
+	t.Run("un-acked item should be redelivered after the visibility timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.VisibilityTimeout = 50 * time.Millisecond
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		first := receiveInMemoryItem(t, items)
+		expectNoInMemoryItem(t, items, 25*time.Millisecond)
+
+		second := receiveInMemoryItem(t, items)
+		assert.Equal(t, first.MessageID, second.MessageID)
+
+		// Ack and Nack for an expired delivery should be ignored
+		first.Ack()
+		first.Nack()
+		expectNoInMemoryItem(t, items, 25*time.Millisecond)
+
+		second.Ack()
+		expectNoInMemoryItem(t, items, 100*time.Millisecond)
+	})
+
+	t.Run("operations on an expired delivery should return an error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.VisibilityTimeout = 50 * time.Millisecond
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		first := receiveInMemoryItem(t, items)
+		second := receiveInMemoryItem(t, items)
+		assert.Equal(t, first.MessageID, second.MessageID)
+
+		require.ErrorIs(t, first.AckE(), types.ErrQueueItemExpired)
+		require.ErrorIs(t, first.NackWithDelay(time.Second), types.ErrQueueItemExpired)
+		require.ErrorIs(t, first.ExtendVisibility(time.Second), types.ErrQueueItemExpired)
+
+		require.NoError(t, second.AckE())
+		require.ErrorIs(t, second.AckE(), types.ErrQueueItemExpired)
+		expectNoInMemoryItem(t, items, 100*time.Millisecond)
+	})
+
+	t.Run("extended visibility should prevent redelivery after the visibility timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.VisibilityTimeout = 50 * time.Millisecond
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+		item := receiveInMemoryItem(t, items)
+
+		// Heartbeat well past the original visibility timeout
+		for range 4 {
+			require.NoError(t, item.ExtendVisibility(50*time.Millisecond))
+			expectNoInMemoryItem(t, items, 25*time.Millisecond)
+		}
+
+		// Without further heartbeats, the item should be redelivered
+		redelivered := receiveInMemoryItem(t, items)
+		assert.Equal(t, item.MessageID, redelivered.MessageID)
+		assert.Equal(t, 2, redelivered.ReceiveCount)
+		redelivered.Ack()
+	})
+
+	t.Run("item nacked with delay should count towards max receive count", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.MaxReceiveCount = 2
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		require.NoError(t, receiveInMemoryItem(t, items).NackWithDelay(25*time.Millisecond))
+		require.NoError(t, receiveInMemoryItem(t, items).NackWithDelay(25*time.Millisecond))
+		expectNoInMemoryItem(t, items, 100*time.Millisecond)
+	})
+
+	t.Run("unacknowledged items should count towards the buffer size", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 1, 10*time.Millisecond)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+		item := receiveInMemoryItem(t, items)
+
+		require.ErrorContains(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"), "timeout")
+
+		item.Ack()
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+		assert.Equal(t, "body_2", receiveInMemoryItem(t, items).Body)
+	})
+
+	t.Run("item should be moved to the dead-letter queue after max receive count", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		deadLetterQueue := types.NewInMemoryDeadLetterQueue("alerts-dlq")
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.MaxReceiveCount = 3
+		opts.DeadLetterQueue = deadLetterQueue
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.SendMessage(ctx, &types.QueueMessage{
+			SlackChannelID: "C000000001",
+			DedupID:        "dedupID_1",
+			Body:           "body_1",
+			Attributes:     map[string]string{types.QueueAttributeProducerID: "api-1"},
+		}))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		var messageID string
+
+		for i := range 3 {
+			item := receiveInMemoryItem(t, items)
+			assert.Equal(t, i+1, item.ReceiveCount)
+			messageID = item.MessageID
+			item.NackWithError(fmt.Errorf("processing failed %d", i+1))
+		}
+
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+
+		deadLetters, err := deadLetterQueue.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, deadLetters, 1)
+
+		deadLetter := deadLetters[0]
+		assert.Equal(t, messageID, deadLetter.MessageID)
+		assert.Equal(t, "alerts", deadLetter.SourceQueue)
+		assert.Equal(t, "C000000001", deadLetter.SlackChannelID)
+		assert.Equal(t, map[string]string{types.QueueAttributeProducerID: "api-1"}, deadLetter.Attributes)
+		assert.Equal(t, "body_1", deadLetter.Body)
+		assert.Equal(t, 3, deadLetter.ReceiveCount)
+		assert.Equal(t, "processing failed 3", deadLetter.LastError)
+		assert.False(t, deadLetter.SentTimestamp.IsZero())
+		assert.False(t, deadLetter.DeadLetterTimestamp.Before(deadLetter.SentTimestamp))
+	})
+
+	t.Run("item should be dropped after max receive count without a dead-letter queue", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.MaxReceiveCount = 1
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		receiveInMemoryItem(t, items).Nack()
+		item := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_2", item.Body)
+		item.Ack()
+
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+	})
+}
+
+func TestInMemoryFifoQueueDeduplication(t *testing.T) {
+	t.Parallel()
+
+	t.Run("duplicate should be delivered again after the deduplication window", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.DeduplicationWindow = 50 * time.Millisecond
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		time.Sleep(75 * time.Millisecond)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+		first := receiveInMemoryItem(t, items)
+		first.Ack()
+		second := receiveInMemoryItem(t, items)
+		assert.NotEqual(t, first.MessageID, second.MessageID)
+		second.Ack()
+
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+	})
+
+	t.Run("deduplication IDs should expire in send order", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.DeduplicationWindow = 100 * time.Millisecond
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+
+		// dedupID_1 has expired, dedupID_2 has not
+		time.Sleep(75 * time.Millisecond)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		for _, body := range []string{"body_1", "body_2", "body_1"} {
+			item := receiveInMemoryItem(t, items)
+			assert.Equal(t, body, item.Body)
+			item.Ack()
+		}
+
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+	})
+
+	t.Run("duplicate should be accepted when the queue is full", func(t *testing.T) {
+		t.Parallel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 1, time.Millisecond)
+		require.NoError(t, queue.Send(context.Background(), "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(context.Background(), "C000000001", "dedupID_1", "body_1"))
+		require.ErrorContains(t, queue.Send(context.Background(), "C000000001", "dedupID_2", "body_1"), "timeout")
+	})
+
+	t.Run("zero deduplication window should disable deduplication", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.DeduplicationWindow = 0
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+		receiveInMemoryItem(t, items).Ack()
+		receiveInMemoryItem(t, items).Ack()
+	})
+}
+
+func TestInMemoryFifoQueueSendWithDelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delayed items should be delivered in order of visibility", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 10, time.Second)
+		require.NoError(t, queue.SendWithDelay(ctx, "C000000001", "dedupID_1", "body_1", 150*time.Millisecond))
+		require.NoError(t, queue.SendWithDelay(ctx, "C000000002", "dedupID_2", "body_2", 50*time.Millisecond))
+		require.NoError(t, queue.SendWithDelay(ctx, "C000000001", "dedupID_3", "body_3", 100*time.Millisecond))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+		expectNoInMemoryItem(t, items, 25*time.Millisecond)
+
+		var bodies []string
+
+		for range 3 {
+			item := receiveInMemoryItem(t, items)
+			bodies = append(bodies, item.Body)
+			item.Ack()
+		}
+
+		assert.Equal(t, []string{"body_2", "body_3", "body_1"}, bodies)
+	})
+
+	t.Run("delayed items should count towards the buffer size", func(t *testing.T) {
+		t.Parallel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 1, time.Millisecond)
+		require.NoError(t, queue.SendWithDelay(context.Background(), "C000000001", "dedupID_1", "body_1", time.Hour))
+		require.ErrorContains(t, queue.Send(context.Background(), "C000000002", "dedupID_2", "body_2"), "timeout")
+	})
+
+	t.Run("delayed duplicate should not be delivered", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 10, time.Second)
+		require.NoError(t, queue.SendWithDelay(ctx, "C000000001", "dedupID_1", "body_1", 25*time.Millisecond))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+		receiveInMemoryItem(t, items).Ack()
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+	})
+}
+
+func TestInMemoryFifoQueueReceiveBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("batch should contain at most one item per channel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		queue := types.NewInMemoryFifoQueue("alerts", 10, time.Second)
+
+		results, err := queue.SendBatch(ctx, []types.QueueMessage{
+			{SlackChannelID: "C000000001", Body: "body_1"},
+			{SlackChannelID: "C000000001", Body: "body_2"},
+			{SlackChannelID: "C000000002", Body: "body_3"},
+		})
+		require.NoError(t, err)
+
+		for _, result := range results {
+			require.NoError(t, result.Err)
+		}
+
+		items, err := queue.ReceiveBatch(ctx, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "body_1", items[0].Body)
+		assert.Equal(t, "body_3", items[1].Body)
+
+		items[0].Ack()
+
+		items, err = queue.ReceiveBatch(ctx, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "body_2", items[0].Body)
+	})
+
+	t.Run("negative wait should return an error", func(t *testing.T) {
+		t.Parallel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 10, time.Second)
+		_, err := queue.ReceiveBatch(context.Background(), 1, -time.Second)
+		require.ErrorContains(t, err, "wait -1s cannot be negative")
+	})
+}
+
+func TestInMemoryFifoQueueMessageGroups(t *testing.T) {
+	t.Parallel()
+
+	t.Run("item should not be delivered while an earlier item in the same channel is in flight", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 10, time.Second)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+		require.NoError(t, queue.Send(ctx, "C000000002", "dedupID_3", "body_3"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		first := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_1", first.Body)
+
+		// The other channel should not be blocked by the un-acked item
+		other := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_3", other.Body)
+
+		expectNoInMemoryItem(t, items, 50*time.Millisecond)
+
+		first.Ack()
+		second := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_2", second.Body)
+	})
+
+	t.Run("group should be unblocked when the in-flight item times out", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := types.DefaultInMemoryFifoQueueOptions()
+		opts.VisibilityTimeout = 50 * time.Millisecond
+		queue := newInMemoryFifoQueue(t, opts)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_2", "body_2"))
+
+		items := startInMemoryReceiver(ctx, t, queue)
+
+		assert.Equal(t, "body_1", receiveInMemoryItem(t, items).Body)
+
+		// The timed out item should be redelivered before the next item in the channel
+		redelivered := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_1", redelivered.Body)
+		redelivered.Ack()
+
+		assert.Equal(t, "body_2", receiveInMemoryItem(t, items).Body)
+	})
+
+	t.Run("concurrent receivers should not break channel ordering", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 100, time.Second)
+
+		for i := range 20 {
+			require.NoError(t, queue.Send(ctx, fmt.Sprintf("C00000000%d", i%2), fmt.Sprintf("dedupID_%d", i), strconv.Itoa(i)))
+		}
+
+		items := make(chan *types.FifoQueueItem)
+
+		for range 3 {
+			go func() {
+				sink := make(chan *types.FifoQueueItem)
+
+				go func() {
+					for item := range sink {
+						select {
+						case items <- item:
+						case <-ctx.Done():
+						}
+					}
+				}()
+
+				err := queue.Receive(ctx, sink)
+				assert.ErrorIs(t, err, context.Canceled)
+			}()
+		}
+
+		last := map[string]int{"C000000000": -1, "C000000001": -1}
+
+		for range 20 {
+			item := receiveInMemoryItem(t, items)
+			n, err := strconv.Atoi(item.Body)
+			require.NoError(t, err)
+			assert.Greater(t, n, last[item.SlackChannelID])
+			last[item.SlackChannelID] = n
+			item.Ack()
+		}
+
+		assert.Equal(t, map[string]int{"C000000000": 18, "C000000001": 19}, last)
+	})
+}
+
 func TestInMemoryFifoQueue(t *testing.T) {
 	t.Parallel()
 
@@ -26,6 +586,25 @@ func TestInMemoryFifoQueue(t *testing.T) {
 		require.ErrorContains(t, err, "timeout")
 	})
 
+	t.Run("zero buffer size should store one item", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		queue := types.NewInMemoryFifoQueue("alerts", 0, time.Millisecond)
+		require.NoError(t, queue.Send(ctx, "C000000001", "dedupID_1", "body_1"))
+		require.ErrorContains(t, queue.Send(ctx, "C000000002", "dedupID_2", "body_2"), "timeout")
+
+		items := startInMemoryReceiver(ctx, t, queue)
+		item := receiveInMemoryItem(t, items)
+		assert.Equal(t, "body_1", item.Body)
+		item.Ack()
+
+		require.NoError(t, queue.Send(ctx, "C000000002", "dedupID_2", "body_2"))
+		assert.Equal(t, "body_2", receiveInMemoryItem(t, items).Body)
+	})
+
 	t.Run("cancelled context should return context error", func(t *testing.T) {
 		t.Parallel()
 
@@ -97,3 +676,39 @@ func TestInMemoryFifoQueue(t *testing.T) {
 		}
 	})
 }
+
+func startInMemoryReceiver(ctx context.Context, t *testing.T, queue *types.InMemoryFifoQueue) <-chan *types.FifoQueueItem {
+	t.Helper()
+
+	items := make(chan *types.FifoQueueItem)
+
+	go func() {
+		err := queue.Receive(ctx, items)
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+
+	return items
+}
+
+func receiveInMemoryItem(t *testing.T, items <-chan *types.FifoQueueItem) *types.FifoQueueItem {
+	t.Helper()
+
+	select {
+	case item, ok := <-items:
+		require.True(t, ok, "sink channel closed unexpectedly")
+		return item
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timeout waiting for item")
+		return nil
+	}
+}
+
+func expectNoInMemoryItem(t *testing.T, items <-chan *types.FifoQueueItem, wait time.Duration) {
+	t.Helper()
+
+	select {
+	case item := <-items:
+		require.FailNow(t, "unexpected item", "body: %s", item.Body)
+	case <-time.After(wait):
+	}
+}
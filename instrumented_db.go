@@ -0,0 +1,275 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// DBCallDurationMetric is the histogram metric with the duration (in seconds) of DB calls made through
+	// InstrumentDB, labelled by DB method.
+	DBCallDurationMetric = "db_call_duration_seconds"
+
+	// DBCallsMetric is the counter metric with the number of DB calls made through InstrumentDB, labelled by DB method.
+	DBCallsMetric = "db_calls_total"
+
+	// DBErrorsMetric is the counter metric with the number of failed DB calls made through InstrumentDB,
+	// labelled by DB method.
+	DBErrorsMetric = "db_errors_total"
+
+	// DBMethodLabel is the label with the DB method name (such as "SaveIssue") of the InstrumentDB metrics.
+	DBMethodLabel = "method"
+)
+
+// instrumentedDB is a DB decorator that records metrics and debug logs for each call. See InstrumentDB.
+type instrumentedDB struct {
+	db      DB
+	metrics Metrics
+	logger  Logger
+}
+
+// versionedInstrumentedDB is an instrumentedDB that wraps a VersionedDB.
+type versionedInstrumentedDB struct {
+	*instrumentedDB
+
+	versioned VersionedDB
+}
+
+// InstrumentDB returns a DB that wraps db, and records the duration, number of calls and number of errors for each
+// DB method (see DBCallDurationMetric, DBCallsMetric and DBErrorsMetric), and logs each call at debug level, with
+// the channel and correlation IDs involved.
+//
+// The metrics are registered with the metrics instance when InstrumentDB is called, so it should be called once per
+// metrics instance. NoopMetrics and NoopLogger are used if metrics or logger is nil.
+//
+// The returned DB implements VersionedDB if db does.
+func InstrumentDB(db DB, metrics Metrics, logger Logger) DB { //nolint:ireturn
+	if metrics == nil {
+		metrics = &NoopMetrics{}
+	}
+
+	if logger == nil {
+		logger = &NoopLogger{}
+	}
+
+	metrics.RegisterHistogram(DBCallDurationMetric, "Duration of database calls, in seconds",
+		[]float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}, DBMethodLabel)
+	metrics.RegisterCounter(DBCallsMetric, "Number of database calls", DBMethodLabel)
+	metrics.RegisterCounter(DBErrorsMetric, "Number of failed database calls", DBMethodLabel)
+
+	d := &instrumentedDB{
+		db:      db,
+		metrics: metrics,
+		logger:  logger,
+	}
+
+	if versioned, ok := db.(VersionedDB); ok {
+		return &versionedInstrumentedDB{instrumentedDB: d, versioned: versioned}
+	}
+
+	return d
+}
+
+func (d *instrumentedDB) Init(ctx context.Context, skipSchemaValidation bool) error {
+	start := time.Now()
+	err := d.db.Init(ctx, skipSchemaValidation)
+	d.record("Init", start, err, nil)
+
+	return err
+}
+
+func (d *instrumentedDB) SaveAlert(ctx context.Context, alert *Alert) error {
+	start := time.Now()
+	err := d.db.SaveAlert(ctx, alert)
+
+	var fields map[string]any
+	if alert != nil {
+		fields = map[string]any{"channel_id": alert.SlackChannelID, "correlation_id": alert.CorrelationID}
+	}
+
+	d.record("SaveAlert", start, err, fields)
+
+	return err
+}
+
+func (d *instrumentedDB) SaveIssue(ctx context.Context, issue Issue) error {
+	start := time.Now()
+	err := d.db.SaveIssue(ctx, issue)
+	d.record("SaveIssue", start, err, issueLogFields(issue))
+
+	return err
+}
+
+func (d *instrumentedDB) SaveIssues(ctx context.Context, issues ...Issue) error {
+	start := time.Now()
+	err := d.db.SaveIssues(ctx, issues...)
+	d.record("SaveIssues", start, err, map[string]any{"issue_count": len(issues)})
+
+	return err
+}
+
+func (d *instrumentedDB) MoveIssue(ctx context.Context, issue Issue, sourceChannelID, targetChannelID string) error {
+	start := time.Now()
+	err := d.db.MoveIssue(ctx, issue, sourceChannelID, targetChannelID)
+
+	fields := issueLogFields(issue)
+	if fields == nil {
+		fields = map[string]any{}
+	}
+
+	fields["source_channel_id"] = sourceChannelID
+	fields["target_channel_id"] = targetChannelID
+
+	d.record("MoveIssue", start, err, fields)
+
+	return err
+}
+
+func (d *instrumentedDB) FindOpenIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (string, json.RawMessage, error) {
+	start := time.Now()
+	id, body, err := d.db.FindOpenIssueByCorrelationID(ctx, channelID, correlationID)
+	d.record("FindOpenIssueByCorrelationID", start, err, map[string]any{"channel_id": channelID, "correlation_id": correlationID})
+
+	return id, body, err
+}
+
+func (d *instrumentedDB) FindIssueBySlackPostID(ctx context.Context, channelID, postID string) (string, json.RawMessage, error) {
+	start := time.Now()
+	id, body, err := d.db.FindIssueBySlackPostID(ctx, channelID, postID)
+	d.record("FindIssueBySlackPostID", start, err, map[string]any{"channel_id": channelID, "post_id": postID})
+
+	return id, body, err
+}
+
+func (d *versionedInstrumentedDB) FindOpenVersionedIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (*StoredIssue, error) {
+	start := time.Now()
+	issue, err := d.versioned.FindOpenVersionedIssueByCorrelationID(ctx, channelID, correlationID)
+	d.record("FindOpenVersionedIssueByCorrelationID", start, err, map[string]any{"channel_id": channelID, "correlation_id": correlationID})
+
+	return issue, err
+}
+
+func (d *versionedInstrumentedDB) FindVersionedIssueBySlackPostID(ctx context.Context, channelID, postID string) (*StoredIssue, error) {
+	start := time.Now()
+	issue, err := d.versioned.FindVersionedIssueBySlackPostID(ctx, channelID, postID)
+	d.record("FindVersionedIssueBySlackPostID", start, err, map[string]any{"channel_id": channelID, "post_id": postID})
+
+	return issue, err
+}
+
+func (d *instrumentedDB) FindActiveChannels(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	channels, err := d.db.FindActiveChannels(ctx)
+	d.record("FindActiveChannels", start, err, nil)
+
+	return channels, err
+}
+
+func (d *instrumentedDB) LoadOpenIssuesInChannel(ctx context.Context, channelID string) (map[string]json.RawMessage, error) {
+	start := time.Now()
+	issues, err := d.db.LoadOpenIssuesInChannel(ctx, channelID)
+	d.record("LoadOpenIssuesInChannel", start, err, map[string]any{"channel_id": channelID})
+
+	return issues, err
+}
+
+func (d *versionedInstrumentedDB) LoadOpenVersionedIssuesInChannel(ctx context.Context, channelID string) (map[string]*StoredIssue, error) {
+	start := time.Now()
+	issues, err := d.versioned.LoadOpenVersionedIssuesInChannel(ctx, channelID)
+	d.record("LoadOpenVersionedIssuesInChannel", start, err, map[string]any{"channel_id": channelID})
+
+	return issues, err
+}
+
+func (d *instrumentedDB) SaveMoveMapping(ctx context.Context, moveMapping MoveMapping) error {
+	start := time.Now()
+	err := d.db.SaveMoveMapping(ctx, moveMapping)
+
+	var fields map[string]any
+	if moveMapping != nil {
+		fields = map[string]any{"channel_id": moveMapping.ChannelID(), "correlation_id": moveMapping.GetCorrelationID()}
+	}
+
+	d.record("SaveMoveMapping", start, err, fields)
+
+	return err
+}
+
+func (d *instrumentedDB) FindMoveMapping(ctx context.Context, channelID, correlationID string) (json.RawMessage, error) {
+	start := time.Now()
+	body, err := d.db.FindMoveMapping(ctx, channelID, correlationID)
+	d.record("FindMoveMapping", start, err, map[string]any{"channel_id": channelID, "correlation_id": correlationID})
+
+	return body, err
+}
+
+func (d *instrumentedDB) DeleteMoveMapping(ctx context.Context, channelID, correlationID string) error {
+	start := time.Now()
+	err := d.db.DeleteMoveMapping(ctx, channelID, correlationID)
+	d.record("DeleteMoveMapping", start, err, map[string]any{"channel_id": channelID, "correlation_id": correlationID})
+
+	return err
+}
+
+func (d *instrumentedDB) SaveChannelProcessingState(ctx context.Context, state *ChannelProcessingState) error {
+	start := time.Now()
+	err := d.db.SaveChannelProcessingState(ctx, state)
+
+	var fields map[string]any
+	if state != nil {
+		fields = map[string]any{"channel_id": state.ChannelID}
+	}
+
+	d.record("SaveChannelProcessingState", start, err, fields)
+
+	return err
+}
+
+func (d *instrumentedDB) FindChannelProcessingState(ctx context.Context, channelID string) (*ChannelProcessingState, error) {
+	start := time.Now()
+	state, err := d.db.FindChannelProcessingState(ctx, channelID)
+	d.record("FindChannelProcessingState", start, err, map[string]any{"channel_id": channelID})
+
+	return state, err
+}
+
+func (d *instrumentedDB) DropAllData(ctx context.Context) error {
+	start := time.Now()
+	err := d.db.DropAllData(ctx)
+	d.record("DropAllData", start, err, nil)
+
+	return err
+}
+
+// record emits the metrics and the debug log for a completed DB call.
+func (d *instrumentedDB) record(method string, start time.Time, err error, fields map[string]any) {
+	duration := time.Since(start)
+
+	d.metrics.Observe(DBCallDurationMetric, duration.Seconds(), method)
+	d.metrics.CounterInc(DBCallsMetric, method)
+
+	logger := d.logger.WithFields(fields).WithField("db_method", method).WithField("duration", duration)
+
+	if err != nil {
+		d.metrics.CounterInc(DBErrorsMetric, method)
+		logger.WithField("error", err.Error()).Debug("Database call failed")
+
+		return
+	}
+
+	logger.Debug("Database call completed")
+}
+
+// issueLogFields returns the log fields identifying an issue, or nil if the issue is nil.
+func issueLogFields(issue Issue) map[string]any {
+	if issue == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"channel_id":     issue.ChannelID(),
+		"correlation_id": issue.GetCorrelationID(),
+		"issue_id":       issue.UniqueID(),
+	}
+}
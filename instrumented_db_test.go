@@ -0,0 +1,106 @@
+package types_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/dbtests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentDB(t *testing.T) {
+	t.Parallel()
+
+	t.Run("instrumented db should pass the db tests", func(t *testing.T) {
+		t.Parallel()
+
+		dbtests.RunAllTests(t, types.InstrumentDB(types.NewInMemoryDB(), &recordingMetrics{}, &recordingLogger{}))
+	})
+
+	t.Run("instrumented unversioned db should pass the db tests", func(t *testing.T) {
+		t.Parallel()
+
+		db := types.InstrumentDB(unversionedDB{types.NewInMemoryDB()}, nil, nil)
+
+		_, ok := db.(types.VersionedDB)
+		require.False(t, ok, "instrumented db should only implement VersionedDB if the wrapped db does")
+
+		dbtests.RunAllTests(t, db)
+	})
+
+	t.Run("metrics should be registered", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := &recordingMetrics{}
+		types.InstrumentDB(types.NewInMemoryDB(), metrics, nil)
+
+		assert.ElementsMatch(t, []string{types.DBCallDurationMetric, types.DBCallsMetric, types.DBErrorsMetric}, metrics.registered)
+	})
+
+	t.Run("calls should be counted and timed per method", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		metrics := &recordingMetrics{}
+		logger := &recordingLogger{}
+		db := types.InstrumentDB(types.NewInMemoryDB(), metrics, logger)
+
+		_, err := db.FindMoveMapping(ctx, "C000000001", "corr-1")
+		require.NoError(t, err)
+		_, err = db.FindMoveMapping(ctx, "C000000001", "corr-2")
+		require.NoError(t, err)
+		require.NoError(t, db.SaveChannelProcessingState(ctx, types.NewChannelProcessingState("C000000002")))
+
+		assert.InDelta(t, 2, metrics.counter(types.DBCallsMetric, "FindMoveMapping"), 0)
+		assert.InDelta(t, 1, metrics.counter(types.DBCallsMetric, "SaveChannelProcessingState"), 0)
+		assert.Zero(t, metrics.counter(types.DBErrorsMetric, "FindMoveMapping"))
+		assert.Equal(t, 2, metrics.observations(types.DBCallDurationMetric, "FindMoveMapping"))
+
+		require.Len(t, logger.entries, 3)
+		assert.Equal(t, "Database call completed", logger.entries[0].msg)
+		assert.Equal(t, "FindMoveMapping", logger.entries[0].fields["db_method"])
+		assert.Equal(t, "C000000001", logger.entries[0].fields["channel_id"])
+		assert.Equal(t, "corr-1", logger.entries[0].fields["correlation_id"])
+		assert.Equal(t, "C000000002", logger.entries[2].fields["channel_id"])
+	})
+
+	t.Run("errors should be counted and logged", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		metrics := &recordingMetrics{}
+		logger := &recordingLogger{}
+		inner := newFaultyDB(t, &types.FaultyDBOptions{
+			Faults: []types.FaultRule{{Method: "DeleteMoveMapping", Err: errDBUnavailable}},
+		})
+		db := types.InstrumentDB(inner, metrics, logger)
+
+		err := db.DeleteMoveMapping(ctx, "C000000001", "corr-1")
+		require.ErrorIs(t, err, errDBUnavailable)
+
+		assert.InDelta(t, 1, metrics.counter(types.DBCallsMetric, "DeleteMoveMapping"), 0)
+		assert.InDelta(t, 1, metrics.counter(types.DBErrorsMetric, "DeleteMoveMapping"), 0)
+
+		require.Len(t, logger.entries, 1)
+		assert.Equal(t, "Database call failed", logger.entries[0].msg)
+		assert.Equal(t, errDBUnavailable.Error(), logger.entries[0].fields["error"])
+	})
+
+	t.Run("nil arguments should not panic", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		inner := newFaultyDB(t, &types.FaultyDBOptions{Faults: []types.FaultRule{{Err: errDBUnavailable}}})
+		db := types.InstrumentDB(inner, nil, nil)
+
+		assert.NotPanics(t, func() {
+			_ = db.SaveAlert(ctx, nil)
+			_ = db.SaveIssue(ctx, nil)
+			_ = db.MoveIssue(ctx, nil, "C000000001", "C000000002")
+			_ = db.SaveMoveMapping(ctx, nil)
+			_ = db.SaveChannelProcessingState(ctx, nil)
+		})
+	})
+}
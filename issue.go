@@ -33,3 +33,31 @@ type Issue interface {
 	// If the issue has no current post, it returns an empty string.
 	CurrentPostID() string
 }
+
+// VersionedIssue is an Issue with optimistic concurrency control.
+//
+// A VersionedDB only writes a VersionedIssue (with SaveIssue, SaveIssues and MoveIssue) if the version of the stored
+// issue is equal to Version, otherwise a *ConflictError is returned (see ErrConflict). A new issue has version 0,
+// and can only be created if no issue with the same ID exists. After a successful write, the version of the stored
+// issue is Version()+1.
+//
+// Issues that do not implement VersionedIssue are written unconditionally (last write wins), and so are all issues
+// written to a DB that does not implement VersionedDB.
+type VersionedIssue interface {
+	Issue
+
+	// Version returns the version of the stored issue that this issue was loaded from (see StoredIssue.Version),
+	// or 0 if the issue is new.
+	Version() int64
+}
+
+// checkIssueVersion returns a *ConflictError if the issue is a VersionedIssue,
+// and its version does not match the current version of the stored issue (0 if the issue does not exist).
+func checkIssueVersion(issue Issue, currentVersion int64) error {
+	versioned, ok := issue.(VersionedIssue)
+	if !ok || versioned.Version() == currentVersion {
+		return nil
+	}
+
+	return &ConflictError{IssueID: issue.UniqueID(), ExpectedVersion: versioned.Version(), CurrentVersion: currentVersion}
+}
@@ -0,0 +1,347 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//go:generate go run ./cmd/gen-jsonschema -out schema
+
+// JSONSchemaDraft is the JSON Schema dialect used by AlertJSONSchema and WebhookCallbackJSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// AlertJSONSchema returns a JSON Schema (draft 2020-12) describing the Alert wire format, including the
+// Field, Escalation, Webhook, WebhookPlainTextInput, WebhookCheckboxInput and WebhookCheckboxOption types.
+//
+// The schema is generated from the package constants, regexes and enum values, and describes alerts in their
+// cleaned (canonical) form. Values that are automatically truncated by Alert.Clean (such as the header and text)
+// are not length-restricted by the schema.
+func AlertJSONSchema() ([]byte, error) {
+	return marshalJSONSchema("Alert", "Slack Manager alert", alertJSONSchemaDefs())
+}
+
+// WebhookCallbackJSONSchema returns a JSON Schema (draft 2020-12) describing the WebhookCallback wire format,
+// i.e. the body posted to HTTP webhooks when a webhook button is clicked.
+func WebhookCallbackJSONSchema() ([]byte, error) {
+	return marshalJSONSchema("WebhookCallback", "Slack Manager webhook callback", map[string]any{
+		"WebhookCallback": webhookCallbackJSONSchema(),
+	})
+}
+
+func marshalJSONSchema(root, title string, defs map[string]any) ([]byte, error) {
+	schema := map[string]any{
+		"$schema": JSONSchemaDraft,
+		"title":   title,
+		"$ref":    "#/$defs/" + root,
+		"$defs":   defs,
+	}
+
+	body, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON schema: %w", err)
+	}
+
+	return append(body, '\n'), nil
+}
+
+func alertJSONSchemaDefs() map[string]any {
+	return map[string]any{
+		"Alert":                 alertJSONSchema(),
+		"Field":                 fieldJSONSchema(),
+		"Escalation":            escalationJSONSchema(),
+		"Webhook":               webhookJSONSchema(),
+		"WebhookPlainTextInput": webhookPlainTextInputJSONSchema(),
+		"WebhookCheckboxInput":  webhookCheckboxInputJSONSchema(),
+		"WebhookCheckboxOption": webhookCheckboxOptionJSONSchema(),
+	}
+}
+
+func alertJSONSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"timestamp":                 dateTimeSchema("Time when the alert was created. Empty or stale timestamps are replaced with the current time."),
+			"correlationId":             stringSchema("Groups related alerts together in issues. If unset, a hash of header, text, author, host and channel is used.", 0, MaxCorrelationIDLength),
+			"type":                      stringSchema("Type of alert, such as 'compliance' or 'security'. Used for routing. Case-insensitive.", 0, 0),
+			"header":                    truncatedStringSchema("Main header (title) of the alert. Include :status: to have it replaced with the severity emoji.", MaxHeaderLength),
+			"headerWhenResolved":        truncatedStringSchema("Header used when the issue is resolved.", MaxHeaderLength),
+			"text":                      truncatedStringSchema("Main text (body) of the alert. Include :status: to have it replaced with the severity emoji.", MaxTextLength),
+			"textWhenResolved":          truncatedStringSchema("Text used when the issue is resolved.", MaxTextLength),
+			"fallbackText":              truncatedStringSchema("Short plain text summary displayed in Slack notifications.", MaxFallbackTextLength),
+			"author":                    truncatedStringSchema("Author of the alert, displayed as a context block.", MaxAuthorLength),
+			"host":                      truncatedStringSchema("Host on which the alert originated, displayed as a context block.", MaxHostLength),
+			"footer":                    truncatedStringSchema("Footer of the alert, displayed as a context block at the bottom of the Slack post.", MaxFooterLength),
+			"link":                      uriSchema("Link to more information about the alert. Must be an absolute URL if set."),
+			"issueFollowUpEnabled":      boolSchema("Track the alert as an issue, which is automatically resolved after autoResolveSeconds."),
+			"autoResolveSeconds":        intSchema(fmt.Sprintf("Seconds after which the issue is automatically resolved. Must be between %d and %d when issueFollowUpEnabled is true.", MinAutoResolveSeconds, MaxAutoResolveSeconds), nil, nil),
+			"autoResolveAsInconclusive": boolSchema("Resolve the issue as 'inconclusive' instead of 'resolved' when auto-resolving."),
+			"severity":                  enumSchema("Severity of the alert. Empty defaults to 'error'.", ValidSeverities(), true),
+			"slackChannelId":            patternSchema("ID or name of the Slack channel where the alert is posted. Takes precedence over routeKey.", SlackChannelIDOrNameRegex, true),
+			"routeKey":                  stringSchema("Case-insensitive route key, used for routing the alert to a Slack channel.", 0, MaxRouteKeyLength),
+			"username":                  truncatedStringSchema("Username that the alert is posted as in Slack.", MaxUsernameLength),
+			"iconEmoji":                 patternSchema("Emoji that the alert is posted with in Slack, on the format ':emoji:'.", IconRegex, true),
+			"fields":                    arraySchema("Additional key-value fields, displayed in two columns.", refSchema("Field"), MaxFieldCount),
+			"notificationDelaySeconds":  intSchema("Seconds to wait before creating the Slack post. Negative values are replaced with 0.", nil, nil),
+			"archivingDelaySeconds":     intSchema("Seconds to wait before archiving a resolved issue. Negative values are replaced with 0.", nil, nil),
+			"escalation":                arraySchema("Escalation points, sorted by delaySeconds.", refSchema("Escalation"), MaxEscalationCount),
+			"ignoreIfTextContains":      arraySchema("The alert is ignored if the text contains any of these substrings.", stringSchema("", 0, MaxIgnoreIfTextContainsLength), MaxIgnoreIfTextContainsCount),
+			"webhooks":                  arraySchema("Interactive buttons on the Slack post. Webhook IDs must be unique.", refSchema("Webhook"), MaxWebhookCount),
+			"metadata":                  objectSchema("Arbitrary key-value data, passed through to webhook payloads.", 0),
+			"failOnRateLimitError":      deprecatedSchema(boolSchema("No longer in use.")),
+		},
+		"anyOf": []any{
+			nonEmptyPropertySchema("header"),
+			nonEmptyPropertySchema("text"),
+		},
+		"if": map[string]any{
+			"properties": map[string]any{"issueFollowUpEnabled": map[string]any{"const": true}},
+			"required":   []string{"issueFollowUpEnabled"},
+		},
+		"then": map[string]any{
+			"properties": map[string]any{"autoResolveSeconds": intSchema("", ptr(MinAutoResolveSeconds), ptr(MaxAutoResolveSeconds))},
+			"required":   []string{"autoResolveSeconds"},
+		},
+	}
+}
+
+func fieldJSONSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"title": truncatedStringSchema("Title of the field.", MaxFieldTitleLength),
+			"value": truncatedStringSchema("Value of the field.", MaxFieldValueLength),
+		},
+	}
+}
+
+func escalationJSONSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"severity", "delaySeconds"},
+		"properties": map[string]any{
+			"severity":      enumSchema("New severity of the issue when the escalation is triggered.", []string{string(AlertPanic), string(AlertError), string(AlertWarning)}, false),
+			"delaySeconds":  intSchema(fmt.Sprintf("Seconds since the issue was created before the escalation is triggered. Consecutive escalations must be at least %d seconds apart.", MinEscalationDelayDiffSeconds), ptr(MinEscalationDelaySeconds), nil),
+			"slackMentions": arraySchema("Slack mentions added to the post when the escalation is triggered.", patternSchema("", SlackMentionRegex, false), MaxEscalationSlackMentionCount),
+			"moveToChannel": patternSchema("ID or name of the Slack channel to move the issue to when the escalation is triggered.", SlackChannelIDOrNameRegex, true),
+		},
+	}
+}
+
+func webhookJSONSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"id", "url", "buttonText"},
+		"properties": map[string]any{
+			"id":               stringSchema("Identifier of the webhook, unique within the alert.", 1, MaxWebhookIDLength),
+			"url":              stringSchema("Absolute http(s) URL, or the printable ASCII identifier of a custom webhook handler.", 1, MaxWebhookURLLength),
+			"confirmationText": stringSchema("Text displayed in a confirmation dialog before the webhook is triggered.", 0, MaxWebhookConfirmationTextLength),
+			"buttonText":       stringSchema("Label displayed on the button.", 1, MaxWebhookButtonTextLength),
+			"buttonStyle":      enumSchema("Visual style of the button. Empty uses the default Slack style.", ValidWebhookButtonStyles(), true),
+			"accessLevel":      enumSchema("Who can click the button. Empty allows anyone in the channel.", ValidWebhookAccessLevels(), true),
+			"displayMode":      enumSchema("When the button is visible. Empty means always.", ValidWebhookDisplayModes(), true),
+			"payload":          objectSchema("Key-value pairs sent in the webhook body.", MaxWebhookPayloadCount),
+			"plainTextInput":   arraySchema("Text input fields shown in the webhook modal dialog. Input IDs must be unique within the webhook.", refSchema("WebhookPlainTextInput"), MaxWebhookPlainTextInputCount),
+			"checkboxInput":    arraySchema("Checkbox groups shown in the webhook modal dialog. Input IDs must be unique within the webhook.", refSchema("WebhookCheckboxInput"), MaxWebhookCheckboxInputCount),
+		},
+	}
+}
+
+func webhookPlainTextInputJSONSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"id"},
+		"properties": map[string]any{
+			"id":           stringSchema("Identifier of the input, used as key in the webhook callback.", 1, MaxWebhookInputIDLength),
+			"description":  stringSchema("Placeholder text shown in the input field.", 0, MaxWebhookInputDescriptionLength),
+			"minLength":    intSchema("Minimum number of characters required.", ptr(0), ptr(MaxWebhookInputTextLength)),
+			"maxLength":    intSchema("Maximum number of characters allowed. Must be >= minLength.", ptr(0), ptr(MaxWebhookInputTextLength)),
+			"multiline":    boolSchema("Show a multiline text area instead of a single line input."),
+			"initialValue": stringSchema("Text pre-filled in the input field. Must satisfy minLength and maxLength.", 0, MaxWebhookInputTextLength),
+		},
+	}
+}
+
+func webhookCheckboxInputJSONSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"id"},
+		"properties": map[string]any{
+			"id":      stringSchema("Identifier of the checkbox group, used as key in the webhook callback.", 1, MaxWebhookInputIDLength),
+			"label":   stringSchema("Text displayed above the checkbox group.", 0, MaxWebhookInputLabelLength),
+			"options": arraySchema("Checkbox options. Option values must be unique within the group.", refSchema("WebhookCheckboxOption"), MaxWebhookCheckboxOptionCount),
+		},
+	}
+}
+
+func webhookCheckboxOptionJSONSchema() map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"value"},
+		"properties": map[string]any{
+			"value":    stringSchema("Value included in the webhook callback when the option is selected.", 1, MaxCheckboxOptionValueLength),
+			"text":     stringSchema("Label displayed next to the checkbox.", 0, MaxWebhookCheckboxOptionTextLength),
+			"selected": boolSchema("Pre-select the option when the modal opens."),
+		},
+	}
+}
+
+func webhookCallbackJSONSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id":           stringSchema("ID of the webhook that was triggered.", 0, 0),
+			"userId":       stringSchema("Slack user ID of the user who clicked the button.", 0, 0),
+			"userRealName": stringSchema("Real name of the user who clicked the button.", 0, 0),
+			"channelId":    stringSchema("Slack channel ID where the button was clicked.", 0, 0),
+			"messageId":    stringSchema("Slack message ID of the post with the button.", 0, 0),
+			"timestamp":    dateTimeSchema("Time when the button was clicked."),
+			"input": map[string]any{
+				"description":          "Plain text input values, keyed by input ID.",
+				"type":                 []string{"object", "null"},
+				"additionalProperties": map[string]any{"type": "string"},
+			},
+			"checkboxInput": map[string]any{
+				"description":          "Selected checkbox values, keyed by checkbox group ID.",
+				"type":                 []string{"object", "null"},
+				"additionalProperties": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+			"payload": objectSchema("Webhook payload, merged with the alert metadata.", 0),
+		},
+	}
+}
+
+func stringSchema(description string, minLength, maxLength int) map[string]any {
+	schema := map[string]any{"type": "string"}
+
+	if description != "" {
+		schema["description"] = description
+	}
+
+	if minLength > 0 {
+		schema["minLength"] = minLength
+	}
+
+	if maxLength > 0 {
+		schema["maxLength"] = maxLength
+	}
+
+	return schema
+}
+
+// truncatedStringSchema returns a string schema for a value that is truncated by Alert.Clean, rather than rejected.
+func truncatedStringSchema(description string, maxLength int) map[string]any {
+	return stringSchema(fmt.Sprintf("%s Truncated at %d characters.", description, maxLength), 0, 0)
+}
+
+func patternSchema(description string, re *regexp.Regexp, allowEmpty bool) map[string]any {
+	pattern := re.String()
+
+	if allowEmpty {
+		pattern = "^(?:" + strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$") + ")?$"
+	}
+
+	schema := stringSchema(description, 0, 0)
+	schema["pattern"] = pattern
+
+	return schema
+}
+
+func enumSchema(description string, values []string, allowEmpty bool) map[string]any {
+	if allowEmpty {
+		values = append([]string{""}, values...)
+	}
+
+	schema := stringSchema(description, 0, 0)
+	schema["enum"] = values
+
+	return schema
+}
+
+func uriSchema(description string) map[string]any {
+	schema := stringSchema(description, 0, 0)
+	schema["format"] = "uri"
+
+	return schema
+}
+
+func dateTimeSchema(description string) map[string]any {
+	schema := stringSchema(description, 0, 0)
+	schema["format"] = "date-time"
+
+	return schema
+}
+
+func boolSchema(description string) map[string]any {
+	return map[string]any{"type": "boolean", "description": description}
+}
+
+func intSchema(description string, minimum, maximum *int) map[string]any {
+	schema := map[string]any{"type": "integer"}
+
+	if description != "" {
+		schema["description"] = description
+	}
+
+	if minimum != nil {
+		schema["minimum"] = *minimum
+	}
+
+	if maximum != nil {
+		schema["maximum"] = *maximum
+	}
+
+	return schema
+}
+
+// arraySchema returns a schema for a JSON array, which may also be null (as nil Go slices are marshaled as null).
+func arraySchema(description string, items map[string]any, maxItems int) map[string]any {
+	return map[string]any{
+		"description": description,
+		"type":        []string{"array", "null"},
+		"items":       items,
+		"maxItems":    maxItems,
+	}
+}
+
+// objectSchema returns a schema for a free-form JSON object, which may also be null (as nil Go maps are marshaled as null).
+func objectSchema(description string, maxProperties int) map[string]any {
+	schema := map[string]any{
+		"description": description,
+		"type":        []string{"object", "null"},
+	}
+
+	if maxProperties > 0 {
+		schema["maxProperties"] = maxProperties
+	}
+
+	return schema
+}
+
+func refSchema(name string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+func deprecatedSchema(schema map[string]any) map[string]any {
+	schema["deprecated"] = true
+	return schema
+}
+
+func nonEmptyPropertySchema(name string) map[string]any {
+	return map[string]any{
+		"properties": map[string]any{name: map[string]any{"minLength": 1}},
+		"required":   []string{name},
+	}
+}
+
+func ptr(i int) *int {
+	return &i
+}
@@ -0,0 +1,124 @@
+package types_test
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonSchemaDoc struct {
+	Schema string                   `json:"$schema"`
+	Ref    string                   `json:"$ref"`
+	Defs   map[string]jsonSchemaDef `json:"$defs"`
+}
+
+type jsonSchemaDef struct {
+	Properties map[string]jsonSchemaProperty `json:"properties"`
+}
+
+type jsonSchemaProperty struct {
+	Enum      []string `json:"enum"`
+	MaxItems  int      `json:"maxItems"`
+	MaxLength int      `json:"maxLength"`
+}
+
+func TestAlertJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	body, err := types.AlertJSONSchema()
+	require.NoError(t, err)
+
+	var doc jsonSchemaDoc
+	require.NoError(t, json.Unmarshal(body, &doc))
+	assert.Equal(t, types.JSONSchemaDraft, doc.Schema)
+	assert.Equal(t, "#/$defs/Alert", doc.Ref)
+
+	t.Run("properties should match the struct json tags", func(t *testing.T) {
+		t.Parallel()
+
+		for name, v := range map[string]any{
+			"Alert":                 types.Alert{},
+			"Field":                 types.Field{},
+			"Escalation":            types.Escalation{},
+			"Webhook":               types.Webhook{},
+			"WebhookPlainTextInput": types.WebhookPlainTextInput{},
+			"WebhookCheckboxInput":  types.WebhookCheckboxInput{},
+			"WebhookCheckboxOption": types.WebhookCheckboxOption{},
+		} {
+			require.Contains(t, doc.Defs, name)
+			assert.Equal(t, jsonTagNames(v), schemaPropertyNames(doc.Defs[name]), name)
+		}
+	})
+
+	t.Run("enums and limits should come from the package", func(t *testing.T) {
+		t.Parallel()
+
+		alert := doc.Defs["Alert"].Properties
+		assert.Equal(t, append([]string{""}, types.ValidSeverities()...), alert["severity"].Enum)
+		assert.Equal(t, types.MaxWebhookCount, alert["webhooks"].MaxItems)
+
+		webhook := doc.Defs["Webhook"].Properties
+		assert.Equal(t, append([]string{""}, types.ValidWebhookAccessLevels()...), webhook["accessLevel"].Enum)
+		assert.Equal(t, types.MaxWebhookButtonTextLength, webhook["buttonText"].MaxLength)
+	})
+
+	t.Run("committed schema file should be up to date", func(t *testing.T) {
+		t.Parallel()
+
+		committed, err := os.ReadFile("schema/alert.schema.json")
+		require.NoError(t, err)
+		assert.Equal(t, string(body), string(committed), "run 'go generate ./...' to update the schema files")
+	})
+}
+
+func TestWebhookCallbackJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	body, err := types.WebhookCallbackJSONSchema()
+	require.NoError(t, err)
+
+	var doc jsonSchemaDoc
+	require.NoError(t, json.Unmarshal(body, &doc))
+	assert.Equal(t, "#/$defs/WebhookCallback", doc.Ref)
+	assert.Equal(t, jsonTagNames(types.WebhookCallback{}), schemaPropertyNames(doc.Defs["WebhookCallback"]))
+
+	committed, err := os.ReadFile("schema/webhook_callback.schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(body), string(committed), "run 'go generate ./...' to update the schema files")
+}
+
+func jsonTagNames(v any) []string {
+	var names []string
+
+	typ := reflect.TypeOf(v)
+
+	for i := range typ.NumField() {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func schemaPropertyNames(def jsonSchemaDef) []string {
+	names := make([]string, 0, len(def.Properties))
+
+	for name := range def.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,166 @@
+package types
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruCache is a bounded, thread-safe LRU cache with a TTL per entry.
+//
+// Entries can have a tag (such as an issue ID), to remove all entries related to the same object. The generation
+// is incremented on every removal, so that a value read from the database before the removal is not added afterwards.
+type lruCache[V any] struct {
+	maxEntries int
+	ttl        time.Duration
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List
+	tags       map[string]map[string]struct{}
+	generation uint64
+}
+
+type lruCacheEntry[V any] struct {
+	key     string
+	tag     string
+	value   V
+	expires time.Time
+}
+
+// newLRUCache creates a new lruCache. A cache with maxEntries <= 0 is disabled, i.e. it never contains any entries.
+func newLRUCache[V any](maxEntries int, ttl time.Duration) *lruCache[V] {
+	return &lruCache[V]{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		tags:       make(map[string]map[string]struct{}),
+	}
+}
+
+// enabled returns true if the cache can contain entries.
+func (c *lruCache[V]) enabled() bool {
+	return c.maxEntries > 0
+}
+
+// get returns the value with the specified key, if it exists and has not expired.
+func (c *lruCache[V]) get(key string, now time.Time) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+
+	entry, ok := elem.Value.(*lruCacheEntry[V])
+	if !ok || !entry.expires.After(now) {
+		c.removeElementLocked(elem)
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return entry.value, true
+}
+
+// currentGeneration returns the current generation, to be passed to add.
+func (c *lruCache[V]) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// add adds (or replaces) a value, unless entries have been removed since the generation was read.
+// The least recently used entry is evicted if the cache is full.
+func (c *lruCache[V]) add(key, tag string, value V, generation uint64, now time.Time) {
+	if !c.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElementLocked(elem)
+	}
+
+	entry := &lruCacheEntry[V]{key: key, tag: tag, value: value, expires: now.Add(c.ttl)}
+	c.entries[key] = c.order.PushFront(entry)
+
+	if tag != "" {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+
+		c.tags[tag][key] = struct{}{}
+	}
+
+	for c.order.Len() > c.maxEntries {
+		c.removeElementLocked(c.order.Back())
+	}
+}
+
+// remove removes the entries with the specified keys (if any).
+func (c *lruCache[V]) remove(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.removeElementLocked(elem)
+		}
+	}
+}
+
+// removeTag removes all entries with the specified tag.
+func (c *lruCache[V]) removeTag(tag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for key := range c.tags[tag] {
+		if elem, ok := c.entries[key]; ok {
+			c.removeElementLocked(elem)
+		}
+	}
+}
+
+// purge removes all entries.
+func (c *lruCache[V]) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.tags = make(map[string]map[string]struct{})
+}
+
+func (c *lruCache[V]) removeElementLocked(elem *list.Element) {
+	entry, ok := c.order.Remove(elem).(*lruCacheEntry[V])
+	if !ok {
+		return
+	}
+
+	delete(c.entries, entry.key)
+
+	if entry.tag != "" {
+		delete(c.tags[entry.tag], entry.key)
+
+		if len(c.tags[entry.tag]) == 0 {
+			delete(c.tags, entry.tag)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// QueueAttributeTraceParent is the message attribute for the W3C Trace Context 'traceparent' header,
+	// used to continue a trace across the queue.
+	QueueAttributeTraceParent = "traceparent"
+
+	// QueueAttributeTraceState is the message attribute for the W3C Trace Context 'tracestate' header.
+	QueueAttributeTraceState = "tracestate"
+
+	// QueueAttributeContentType is the message attribute for the content type of the message body, such as 'application/json'.
+	QueueAttributeContentType = "content-type"
+
+	// QueueAttributeSchemaVersion is the message attribute for the schema version of the message body.
+	QueueAttributeSchemaVersion = "schema-version"
+
+	// QueueAttributeProducerID is the message attribute identifying the producer of the message.
+	QueueAttributeProducerID = "producer-id"
+
+	// MaxQueueMessageAttributeCount is the maximum number of attributes per queue message (the same as SQS).
+	MaxQueueMessageAttributeCount = 10
+
+	// MaxQueueBatchSize is the maximum number of messages in a batch sent with FifoQueue.SendBatch, or received with
+	// FifoQueue.ReceiveBatch (the same as SQS).
+	MaxQueueBatchSize = 10
+)
+
+// QueueMessage is a message to be sent to a FifoQueue.
+type QueueMessage struct {
+	// SlackChannelID is the ID of the Slack channel to which the message is related. It is used as message group.
+	SlackChannelID string
+
+	// DedupID is the deduplication ID of the message. If empty, content-based deduplication is used.
+	DedupID string
+
+	// Body is the body of the message.
+	Body string
+
+	// Delay is the time the message is hidden from receivers after it has been sent. Zero means no delay.
+	Delay time.Duration
+
+	// Attributes are optional message attributes, such as trace context and content type (see the QueueAttribute*
+	// constants). They are delivered with the message, in FifoQueueItem.Attributes.
+	Attributes map[string]string
+}
+
+// Validate returns an error if the message cannot be sent, i.e. if the message is nil, the delay is negative,
+// or the message has too many attributes or an attribute with an empty name.
+func (m *QueueMessage) Validate() error {
+	if m == nil {
+		return errors.New("queue message is nil")
+	}
+
+	if m.Delay < 0 {
+		return fmt.Errorf("delay %s cannot be negative", m.Delay)
+	}
+
+	if len(m.Attributes) > MaxQueueMessageAttributeCount {
+		return fmt.Errorf("too many message attributes, expected <=%d", MaxQueueMessageAttributeCount)
+	}
+
+	for name := range m.Attributes {
+		if name == "" {
+			return errors.New("message attribute name cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// SendResult is the result of sending a single message in a batch, with FifoQueue.SendBatch.
+type SendResult struct {
+	// MessageID is the ID of the sent message, if it was accepted. For a duplicate message, it is the ID of the
+	// original message.
+	MessageID string
+
+	// Err is the reason the message was not accepted, or nil if it was accepted.
+	Err error
+}
+
+// validateQueueBatchSize returns an error if the batch size is not between 1 and MaxQueueBatchSize.
+func validateQueueBatchSize(n int) error {
+	if n < 1 || n > MaxQueueBatchSize {
+		return fmt.Errorf("batch size %d is out of range, expected 1-%d", n, MaxQueueBatchSize)
+	}
+
+	return nil
+}
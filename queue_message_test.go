@@ -0,0 +1,36 @@
+package types_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueMessageValidate(t *testing.T) {
+	t.Parallel()
+
+	var msg *types.QueueMessage
+	require.ErrorContains(t, msg.Validate(), "nil")
+
+	msg = &types.QueueMessage{SlackChannelID: "C000000001", Body: "body"}
+	require.NoError(t, msg.Validate())
+
+	msg.Delay = -time.Second
+	require.ErrorContains(t, msg.Validate(), "delay -1s cannot be negative")
+
+	msg.Delay = 0
+	msg.Attributes = map[string]string{types.QueueAttributeTraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	require.NoError(t, msg.Validate())
+
+	msg.Attributes[""] = "foo"
+	require.ErrorContains(t, msg.Validate(), "attribute name cannot be empty")
+
+	msg.Attributes = map[string]string{}
+	for i := range types.MaxQueueMessageAttributeCount + 1 {
+		msg.Attributes[fmt.Sprintf("attr-%d", i)] = "value"
+	}
+	require.ErrorContains(t, msg.Validate(), "too many message attributes, expected <=10")
+}
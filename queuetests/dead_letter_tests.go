@@ -0,0 +1,156 @@
+package queuetests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// DeadLetterQueueFactory returns a new, empty dead-letter queue. It is called once for each test.
+type DeadLetterQueueFactory func(t *testing.T) types.DeadLetterQueue
+
+func TestDeadLetterListAndGet(t *testing.T, newDeadLetterQueue DeadLetterQueueFactory) {
+	ctx := context.Background()
+	dlq := newDeadLetterQueue(t)
+	source := types.NewInMemoryFifoQueue("source", 10, time.Second)
+
+	require.NotEmpty(t, dlq.Name(), "dead-letter queue name should not be empty")
+
+	letters, err := dlq.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+
+	require.NoError(t, dlq.Add(ctx, source, newDeadLetter("msg-1")))
+	require.NoError(t, dlq.Add(ctx, source, newDeadLetter("msg-2")))
+
+	letters, err = dlq.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, letters, 2)
+	assert.Equal(t, "msg-1", letters[0].MessageID)
+	assert.Equal(t, "msg-2", letters[1].MessageID)
+
+	letter, err := dlq.Get(ctx, "msg-2")
+	require.NoError(t, err)
+	require.NotNil(t, letter)
+	assert.Equal(t, "source", letter.SourceQueue)
+	assert.Equal(t, "C0ABABABAB", letter.SlackChannelID)
+	assert.Equal(t, "body-msg-2", letter.Body)
+	assert.Equal(t, map[string]string{types.QueueAttributeContentType: "application/json"}, letter.Attributes)
+	assert.Equal(t, 3, letter.ReceiveCount)
+	assert.Equal(t, "processing failed", letter.LastError)
+	assert.False(t, letter.SentTimestamp.IsZero())
+	assert.False(t, letter.DeadLetterTimestamp.IsZero())
+
+	letter, err = dlq.Get(ctx, "msg-3")
+	require.NoError(t, err)
+	assert.Nil(t, letter, "missing dead letter should return nil without an error")
+}
+
+func TestDeadLetterRedrive(t *testing.T, newDeadLetterQueue DeadLetterQueueFactory) {
+	ctx := context.Background()
+	dlq := newDeadLetterQueue(t)
+	source := types.NewInMemoryFifoQueue("source", 10, time.Second)
+
+	require.NoError(t, dlq.Add(ctx, source, newDeadLetter("msg-1")))
+	require.NoError(t, dlq.Add(ctx, source, newDeadLetter("msg-2")))
+
+	require.NoError(t, dlq.Redrive(ctx, "msg-2"))
+	require.Error(t, dlq.Redrive(ctx, "msg-2"), "redriven dead letter should be removed")
+
+	letters, err := dlq.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, "msg-1", letters[0].MessageID)
+
+	r := startReceiver(t, source)
+	defer r.stop(t)
+
+	item := r.next(t)
+	assert.Equal(t, "C0ABABABAB", item.SlackChannelID)
+	assert.Equal(t, "body-msg-2", item.Body)
+	assert.Equal(t, map[string]string{types.QueueAttributeContentType: "application/json"}, item.Attributes)
+	assert.Equal(t, 1, item.ReceiveCount)
+	item.Ack()
+
+	r.expectNothing(t)
+}
+
+func TestDeadLetterRedriveAll(t *testing.T, newDeadLetterQueue DeadLetterQueueFactory) {
+	ctx := context.Background()
+	dlq := newDeadLetterQueue(t)
+	source := types.NewInMemoryFifoQueue("source", 10, time.Second)
+
+	for i := range 3 {
+		require.NoError(t, dlq.Add(ctx, source, newDeadLetter(fmt.Sprintf("msg-%d", i))))
+	}
+
+	count, err := dlq.RedriveAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	letters, err := dlq.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+
+	r := startReceiver(t, source)
+	defer r.stop(t)
+
+	// The dead letters should be redriven in order
+	for i := range 3 {
+		item := r.next(t)
+		assert.Equal(t, fmt.Sprintf("body-msg-%d", i), item.Body)
+		item.Ack()
+	}
+
+	r.expectNothing(t)
+}
+
+func TestDeadLetterDelete(t *testing.T, newDeadLetterQueue DeadLetterQueueFactory) {
+	ctx := context.Background()
+	dlq := newDeadLetterQueue(t)
+	source := types.NewInMemoryFifoQueue("source", 10, time.Second)
+
+	require.NoError(t, dlq.Add(ctx, source, newDeadLetter("msg-1")))
+	require.NoError(t, dlq.Delete(ctx, "msg-1"))
+	require.NoError(t, dlq.Delete(ctx, "msg-1"), "deleting a missing dead letter should not fail")
+
+	letter, err := dlq.Get(ctx, "msg-1")
+	require.NoError(t, err)
+	assert.Nil(t, letter)
+
+	count, err := dlq.RedriveAll(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+// RunAllDeadLetterQueueTests runs all dead-letter queue compliance tests, with a new dead-letter queue for each test.
+// The tests use an InMemoryFifoQueue as source queue.
+func RunAllDeadLetterQueueTests(t *testing.T, newDeadLetterQueue DeadLetterQueueFactory) {
+	t.Helper()
+
+	t.Run("DeadLetterListAndGet", func(t *testing.T) { TestDeadLetterListAndGet(t, newDeadLetterQueue) })
+	t.Run("DeadLetterRedrive", func(t *testing.T) { TestDeadLetterRedrive(t, newDeadLetterQueue) })
+	t.Run("DeadLetterRedriveAll", func(t *testing.T) { TestDeadLetterRedriveAll(t, newDeadLetterQueue) })
+	t.Run("DeadLetterDelete", func(t *testing.T) { TestDeadLetterDelete(t, newDeadLetterQueue) })
+}
+
+func newDeadLetter(messageID string) *types.DeadLetter {
+	now := time.Now()
+
+	return &types.DeadLetter{
+		MessageID:           messageID,
+		SourceQueue:         "source",
+		SlackChannelID:      "C0ABABABAB",
+		Body:                "body-" + messageID,
+		Attributes:          map[string]string{types.QueueAttributeContentType: "application/json"},
+		SentTimestamp:       now.Add(-time.Minute),
+		DeadLetterTimestamp: now,
+		ReceiveCount:        3,
+		LastError:           "processing failed",
+	}
+}
@@ -0,0 +1,612 @@
+package queuetests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Package queuetests provides a test suite for types.FifoQueue implementations.
+//
+// Usage in your queue plugin:
+//
+//	func TestQueueCompliance(t *testing.T) {
+//	    if testing.Short() {
+//	        t.Skip("Skipping integration tests")
+//	    }
+//
+//	    // Run all standard tests, with a new (empty) queue for each test
+//	    queuetests.RunAllTests(t, func(t *testing.T) types.FifoQueue {
+//	        return setupTestQueue(t)
+//	    })
+//	}
+//
+// Or run individual tests:
+//
+//	queuetests.TestSendAndReceive(t, newQueue)
+//	queuetests.TestFifoOrderPerChannel(t, newQueue)
+//	// ... etc
+
+// QueueFactory returns a new, empty queue. It is called once for each test.
+type QueueFactory func(t *testing.T) types.FifoQueue
+
+const (
+	// receiveTimeout is the maximum time to wait for an expected message.
+	receiveTimeout = 5 * time.Second
+
+	// quietPeriod is the time to wait when checking that no (more) messages are delivered.
+	quietPeriod = 50 * time.Millisecond
+)
+
+func TestSendAndReceive(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	require.NotEmpty(t, queue.Name(), "queue name should not be empty")
+
+	err := queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1")
+	require.NoError(t, err)
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	item := r.next(t)
+	assert.NotEmpty(t, item.MessageID)
+	assert.Equal(t, "C0ABABABAB", item.SlackChannelID)
+	assert.Equal(t, "body-1", item.Body)
+	assert.False(t, item.ReceiveTimestamp.IsZero())
+	require.NotNil(t, item.Ack)
+	require.NotNil(t, item.Nack)
+
+	item.Ack()
+}
+
+func TestFifoOrderPerChannel(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	channels := []string{"C0ABABABA1", "C0ABABABA2", "C0ABABABA3"}
+	count := 10
+
+	for i := range count {
+		for _, channel := range channels {
+			err := queue.Send(ctx, channel, fmt.Sprintf("%s-%d", channel, i), fmt.Sprintf("%s-%d", channel, i))
+			require.NoError(t, err)
+		}
+	}
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	received := map[string][]string{}
+
+	for range count * len(channels) {
+		item := r.next(t)
+		received[item.SlackChannelID] = append(received[item.SlackChannelID], item.Body)
+		item.Ack()
+	}
+
+	for _, channel := range channels {
+		expected := make([]string, count)
+		for i := range count {
+			expected[i] = fmt.Sprintf("%s-%d", channel, i)
+		}
+
+		assert.Equal(t, expected, received[channel], "messages in channel %s should be received in order", channel)
+	}
+}
+
+func TestAckedItemsAreNotRedelivered(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1"))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	item := r.next(t)
+	item.Ack()
+
+	r.expectNothing(t)
+}
+
+func TestNackRedelivers(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1"))
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-2", "body-2"))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	item := r.next(t)
+	require.Equal(t, "body-1", item.Body)
+	item.Nack()
+
+	// The nacked message should be redelivered before the next message in the same channel
+	item = r.next(t)
+	require.Equal(t, "body-1", item.Body, "nacked message should be redelivered first")
+	item.Ack()
+
+	item = r.next(t)
+	require.Equal(t, "body-2", item.Body)
+	item.Ack()
+
+	r.expectNothing(t)
+}
+
+func TestAckWithError(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1"))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	item := r.next(t)
+	require.Equal(t, "body-1", item.Body)
+	require.NoError(t, item.AckE())
+
+	r.expectNothing(t)
+}
+
+func TestNackWithDelay(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1"))
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-2", "body-2"))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	item := r.next(t)
+	require.Equal(t, "body-1", item.Body)
+	require.Error(t, item.NackWithDelay(-time.Second), "negative delay should be rejected")
+	require.NoError(t, item.NackWithDelay(2*quietPeriod))
+
+	// Neither the nacked message nor the next message in the same channel should be delivered during the delay
+	r.expectNothing(t)
+
+	item = r.next(t)
+	require.Equal(t, "body-1", item.Body, "nacked message should be redelivered first")
+	item.Ack()
+
+	item = r.next(t)
+	require.Equal(t, "body-2", item.Body)
+	item.Ack()
+}
+
+func TestExtendVisibility(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1"))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	item := r.next(t)
+	require.Error(t, item.ExtendVisibility(0), "zero timeout should be rejected")
+	require.NoError(t, item.ExtendVisibility(time.Minute))
+
+	r.expectNothing(t)
+
+	require.NoError(t, item.AckE())
+}
+
+func TestDeduplication(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	// Same deduplication ID: should be accepted, but delivered once
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1"))
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1"))
+
+	// Different deduplication IDs, same body: should be delivered twice
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-2", "body-2"))
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-3", "body-2"))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	var bodies []string
+
+	for range 3 {
+		item := r.next(t)
+		bodies = append(bodies, item.Body)
+		item.Ack()
+	}
+
+	assert.Equal(t, []string{"body-1", "body-2", "body-2"}, bodies)
+
+	r.expectNothing(t)
+}
+
+func TestContentBasedDeduplication(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	// No deduplication ID, same body: should be accepted, but delivered once
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "", "body-1"))
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "", "body-1"))
+
+	// No deduplication ID, different body: should be delivered
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "", "body-2"))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	var bodies []string
+
+	for range 2 {
+		item := r.next(t)
+		bodies = append(bodies, item.Body)
+		item.Ack()
+	}
+
+	assert.Equal(t, []string{"body-1", "body-2"}, bodies)
+
+	r.expectNothing(t)
+}
+
+func TestSendWithDelay(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	const delay = 100 * time.Millisecond
+
+	start := time.Now()
+
+	require.NoError(t, queue.SendWithDelay(ctx, "C0ABABABAB", "dedup-1", "delayed", delay))
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-2", "immediate"))
+	require.Error(t, queue.SendWithDelay(ctx, "C0ABABABAB", "dedup-3", "negative", -time.Second))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	// The immediate message should not be blocked by the earlier, delayed message in the same group
+	item := r.next(t)
+	assert.Equal(t, "immediate", item.Body)
+	item.Ack()
+
+	item = r.next(t)
+	assert.Equal(t, "delayed", item.Body)
+	assert.GreaterOrEqual(t, time.Since(start), delay, "delayed message was delivered too early")
+	item.Ack()
+
+	r.expectNothing(t)
+}
+
+func TestMessageAttributes(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	attributes := map[string]string{
+		types.QueueAttributeTraceParent:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		types.QueueAttributeContentType:   "application/json",
+		types.QueueAttributeProducerID:    "api-1",
+		types.QueueAttributeSchemaVersion: "1",
+	}
+
+	before := time.Now()
+
+	require.NoError(t, queue.SendMessage(ctx, &types.QueueMessage{
+		SlackChannelID: "C0ABABABAB",
+		DedupID:        "dedup-1",
+		Body:           "body-1",
+		Attributes:     attributes,
+	}))
+	require.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-2", "body-2"))
+	require.Error(t, queue.SendMessage(ctx, nil))
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	item := r.next(t)
+	assert.Equal(t, "body-1", item.Body)
+	assert.Equal(t, attributes, item.Attributes)
+	assert.Equal(t, 1, item.ReceiveCount)
+	// Allow for a coarse timestamp resolution in the implementation
+	assert.False(t, item.SentTimestamp.Before(before.Add(-time.Second)), "sent timestamp is too early")
+	assert.False(t, item.SentTimestamp.After(item.ReceiveTimestamp), "sent timestamp is after receive timestamp")
+	item.Nack()
+
+	// The receive count should be incremented on redelivery, and the attributes should be kept
+	item = r.next(t)
+	assert.Equal(t, "body-1", item.Body)
+	assert.Equal(t, attributes, item.Attributes)
+	assert.Equal(t, 2, item.ReceiveCount)
+	item.Ack()
+
+	item = r.next(t)
+	assert.Equal(t, "body-2", item.Body)
+	assert.Empty(t, item.Attributes)
+	assert.Equal(t, 1, item.ReceiveCount)
+	item.Ack()
+}
+
+func TestSendBatch(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	_, err := queue.SendBatch(ctx, nil)
+	require.Error(t, err)
+
+	_, err = queue.SendBatch(ctx, make([]types.QueueMessage, types.MaxQueueBatchSize+1))
+	require.Error(t, err)
+
+	results, err := queue.SendBatch(ctx, []types.QueueMessage{
+		{SlackChannelID: "C0ABABABAB", DedupID: "dedup-1", Body: "body-1"},
+		{SlackChannelID: "C0ABABABAB", DedupID: "dedup-2", Body: "body-2", Delay: -time.Second},
+		{SlackChannelID: "C0ABABABAB", DedupID: "dedup-3", Body: "body-3"},
+		{SlackChannelID: "C0ABABABAB", DedupID: "dedup-1", Body: "body-1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	// The invalid message should be rejected, without affecting the other messages
+	require.NoError(t, results[0].Err)
+	require.Error(t, results[1].Err)
+	require.NoError(t, results[2].Err)
+	assert.NotEmpty(t, results[0].MessageID)
+	assert.NotEqual(t, results[0].MessageID, results[2].MessageID)
+
+	// The duplicate should be accepted, with the ID of the original message
+	require.NoError(t, results[3].Err)
+	assert.Equal(t, results[0].MessageID, results[3].MessageID)
+
+	r := startReceiver(t, queue)
+	defer r.stop(t)
+
+	for _, expected := range []int{0, 2} {
+		item := r.next(t)
+		assert.Equal(t, results[expected].MessageID, item.MessageID)
+		item.Ack()
+	}
+
+	r.expectNothing(t)
+}
+
+func TestReceiveBatch(t *testing.T, newQueue QueueFactory) {
+	ctx := context.Background()
+	queue := newQueue(t)
+
+	_, err := queue.ReceiveBatch(ctx, 0, 0)
+	require.Error(t, err)
+
+	_, err = queue.ReceiveBatch(ctx, types.MaxQueueBatchSize+1, 0)
+	require.Error(t, err)
+
+	// An empty queue should return an empty batch after the wait duration
+	items, err := queue.ReceiveBatch(ctx, 10, 100*time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	for i := range 3 {
+		require.NoError(t, queue.Send(ctx, fmt.Sprintf("C0ABABAB0%d", i), fmt.Sprintf("dedup-%d", i), fmt.Sprintf("body-%d", i)))
+	}
+
+	require.NoError(t, queue.Send(ctx, "C0ABABAB00", "dedup-3", "body-3"))
+
+	var bodies []string
+
+	deadline := time.Now().Add(receiveTimeout)
+
+	for len(bodies) < 4 && time.Now().Before(deadline) {
+		items, err := queue.ReceiveBatch(ctx, 2, quietPeriod)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(items), 2)
+
+		for _, item := range items {
+			bodies = append(bodies, item.Body)
+			item.Ack()
+		}
+	}
+
+	require.Len(t, bodies, 4)
+	assert.ElementsMatch(t, []string{"body-0", "body-1", "body-2", "body-3"}, bodies)
+	assert.Less(t, slices.Index(bodies, "body-0"), slices.Index(bodies, "body-3"), "messages in a group should be received in order")
+
+	// A waiting ReceiveBatch should return as soon as a message is sent
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, queue.Send(ctx, "C0ABABABAB", "dedup-4", "body-4"))
+	}()
+
+	start := time.Now()
+	items, err = queue.ReceiveBatch(ctx, 1, receiveTimeout)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "body-4", items[0].Body)
+	assert.Less(t, time.Since(start), receiveTimeout)
+	items[0].Ack()
+
+	// A canceled context should return the context error
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = queue.ReceiveBatch(cancelCtx, 1, receiveTimeout)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSendContextCancellation(t *testing.T, newQueue QueueFactory) {
+	queue := newQueue(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := queue.Send(ctx, "C0ABABABAB", "dedup-1", "body-1")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestReceiveContextCancellation(t *testing.T, newQueue QueueFactory) {
+	queue := newQueue(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sinkCh := make(chan *types.FifoQueueItem)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- queue.Receive(ctx, sinkCh)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(receiveTimeout):
+		require.FailNow(t, "Receive did not return after the context was canceled")
+	}
+
+	_, ok := <-sinkCh
+	assert.False(t, ok, "sink channel should be closed when Receive returns")
+}
+
+func TestReceiveContextCancellationWhileWritingToSink(t *testing.T, newQueue QueueFactory) {
+	queue := newQueue(t)
+
+	require.NoError(t, queue.Send(context.Background(), "C0ABABABAB", "dedup-1", "body-1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Nobody reads from the sink channel, so Receive blocks while writing to it
+	sinkCh := make(chan *types.FifoQueueItem)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- queue.Receive(ctx, sinkCh)
+	}()
+
+	time.Sleep(quietPeriod)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(receiveTimeout):
+		require.FailNow(t, "Receive did not return after the context was canceled")
+	}
+}
+
+// RunAllTests runs all queue compliance tests in parallel, with a new queue for each test.
+// This is a convenience function for plugin implementations.
+func RunAllTests(t *testing.T, newQueue QueueFactory) {
+	t.Helper()
+
+	run := func(name string, test func(*testing.T, QueueFactory)) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			test(t, newQueue)
+		})
+	}
+
+	// Core functionality tests
+	run("SendAndReceive", TestSendAndReceive)
+	run("FifoOrderPerChannel", TestFifoOrderPerChannel)
+	run("AckedItemsAreNotRedelivered", TestAckedItemsAreNotRedelivered)
+	run("NackRedelivers", TestNackRedelivers)
+	run("AckWithError", TestAckWithError)
+	run("NackWithDelay", TestNackWithDelay)
+	run("ExtendVisibility", TestExtendVisibility)
+	run("Deduplication", TestDeduplication)
+	run("ContentBasedDeduplication", TestContentBasedDeduplication)
+	run("SendWithDelay", TestSendWithDelay)
+	run("MessageAttributes", TestMessageAttributes)
+	run("SendBatch", TestSendBatch)
+	run("ReceiveBatch", TestReceiveBatch)
+
+	// Context cancellation tests
+	run("SendContextCancellation", TestSendContextCancellation)
+	run("ReceiveContextCancellation", TestReceiveContextCancellation)
+	run("ReceiveContextCancellationWhileWritingToSink", TestReceiveContextCancellationWhileWritingToSink)
+}
+
+// receiver runs Receive in a background goroutine, until stopped.
+type receiver struct {
+	sinkCh chan *types.FifoQueueItem
+	cancel context.CancelFunc
+	errCh  chan error
+	once   sync.Once
+}
+
+func startReceiver(t *testing.T, queue types.FifoQueue) *receiver {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &receiver{
+		sinkCh: make(chan *types.FifoQueueItem),
+		cancel: cancel,
+		errCh:  make(chan error, 1),
+	}
+
+	go func() {
+		r.errCh <- queue.Receive(ctx, r.sinkCh)
+	}()
+
+	return r
+}
+
+// next returns the next received item, and fails the test if no item is received within receiveTimeout.
+func (r *receiver) next(t *testing.T) *types.FifoQueueItem {
+	t.Helper()
+
+	select {
+	case item, ok := <-r.sinkCh:
+		require.True(t, ok, "sink channel was closed unexpectedly")
+		require.NotNil(t, item)
+		return item
+	case <-time.After(receiveTimeout):
+		require.FailNow(t, "timeout while waiting for message")
+		return nil
+	}
+}
+
+// expectNothing fails the test if any item is received within quietPeriod.
+func (r *receiver) expectNothing(t *testing.T) {
+	t.Helper()
+
+	select {
+	case item, ok := <-r.sinkCh:
+		if ok {
+			assert.Fail(t, "unexpected message received", "body: %s", item.Body)
+		}
+	case <-time.After(quietPeriod):
+	}
+}
+
+// stop cancels the receiver, and checks that Receive returns the context error and closes the sink channel.
+func (r *receiver) stop(t *testing.T) {
+	t.Helper()
+
+	r.once.Do(func() {
+		r.cancel()
+
+		// Drain the sink channel until it is closed
+		for {
+			if _, ok := <-r.sinkCh; !ok {
+				break
+			}
+		}
+
+		select {
+		case err := <-r.errCh:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(receiveTimeout):
+			assert.Fail(t, "Receive did not return after the context was canceled")
+		}
+	})
+}
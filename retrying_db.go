@@ -0,0 +1,372 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy defines how WithRetry retries failed DB calls.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per call, including the first one. 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the time to wait before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum time to wait between attempts.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor the backoff is multiplied by after each retry (exponential backoff).
+	Multiplier float64
+
+	// Jitter is the fraction (0-1) of each backoff that is randomized, to avoid synchronized retries from multiple
+	// instances. With a jitter of 0.2, the actual backoff is between 80% and 100% of the computed backoff.
+	Jitter float64
+
+	// IsRetryable returns true if a failed call should be retried, e.g. for DynamoDB throttling or a Postgres
+	// serialization failure. If nil, DefaultIsRetryable is used, which only retries errors marked as temporary or
+	// timeouts. DB implementations should provide a function that recognizes the transient errors of their driver.
+	IsRetryable func(err error) bool
+
+	// RetryNonIdempotent enables retries for methods that are not guaranteed to be idempotent (see WithRetry).
+	RetryNonIdempotent bool
+
+	// OnRetry is called before each retry (optional), e.g. for logging or metrics.
+	// attempt is the number of the failed attempt (starting at 1), and delay is the backoff before the next attempt.
+	OnRetry func(method string, attempt int, err error, delay time.Duration)
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with the default settings: up to 4 attempts, with exponential backoff from
+// 50ms to 2s and 20% jitter, retrying the errors accepted by DefaultIsRetryable.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 50 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// DefaultIsRetryable is the default RetryPolicy.IsRetryable function. It is conservative, since retrying a permanent
+// error (such as an invalid query or a constraint violation) only delays the failure.
+//
+// It returns true if an error in the chain has a Temporary() or Timeout() method returning true, such as a net.Error
+// timeout. It returns false for all other errors, for context cancellation and deadline errors, and for ErrConflict
+// (which requires the issue to be reloaded).
+func DefaultIsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrConflict) {
+		return false
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) && temporary.Temporary() {
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// retryingDB is a DB decorator that retries failed calls. See WithRetry.
+type retryingDB struct {
+	db     DB
+	policy RetryPolicy
+}
+
+// versionedRetryingDB is a retryingDB that wraps a VersionedDB.
+type versionedRetryingDB struct {
+	*retryingDB
+
+	versioned VersionedDB
+}
+
+// WithRetry returns a DB that wraps db, and retries failed calls with exponential backoff and jitter, as long as
+// the error is retryable according to the policy. The last error is returned if all attempts fail.
+//
+// A retry is never started if the context is done, or if the context deadline would pass during the backoff.
+//
+// Zero (or negative) MaxAttempts, InitialBackoff, MaxBackoff and Multiplier (<1) values are replaced by the
+// DefaultRetryPolicy values, and Jitter is limited to 0-1.
+//
+// Only idempotent methods are retried, since a failed call may have succeeded in the database (e.g. if the response
+// was lost). The idempotency rules are:
+//
+//   - Init, DropAllData: idempotent by contract.
+//   - SaveAlert: alerts may be saved multiple times by contract.
+//   - SaveIssue, SaveIssues, SaveMoveMapping, SaveChannelProcessingState: upserts by unique ID, safe to repeat.
+//     A retried write of a VersionedIssue may return a *ConflictError if the failed attempt did succeed.
+//   - DeleteMoveMapping: deleting a missing move mapping is not an error, so it is idempotent.
+//   - FindOpenIssueByCorrelationID, FindIssueBySlackPostID, FindActiveChannels, LoadOpenIssuesInChannel,
+//     FindMoveMapping, FindChannelProcessingState and the VersionedDB lookups: read-only.
+//   - MoveIssue: not retried by default, since a retry after a successful move may not find the issue in the
+//     source channel, depending on the implementation. Set RetryPolicy.RetryNonIdempotent to retry it.
+//
+// The returned DB implements VersionedDB if db does.
+func WithRetry(db DB, policy RetryPolicy) DB { //nolint:ireturn
+	defaults := DefaultRetryPolicy()
+
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaults.InitialBackoff
+	}
+
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaults.MaxBackoff
+	}
+
+	if policy.Multiplier < 1 {
+		policy.Multiplier = defaults.Multiplier
+	}
+
+	policy.Jitter = min(max(policy.Jitter, 0), 1)
+
+	if policy.IsRetryable == nil {
+		policy.IsRetryable = DefaultIsRetryable
+	}
+
+	d := &retryingDB{
+		db:     db,
+		policy: policy,
+	}
+
+	if versioned, ok := db.(VersionedDB); ok {
+		return &versionedRetryingDB{retryingDB: d, versioned: versioned}
+	}
+
+	return d
+}
+
+func (d *retryingDB) Init(ctx context.Context, skipSchemaValidation bool) error {
+	return d.do(ctx, "Init", true, func() error {
+		return d.db.Init(ctx, skipSchemaValidation)
+	})
+}
+
+func (d *retryingDB) SaveAlert(ctx context.Context, alert *Alert) error {
+	return d.do(ctx, "SaveAlert", true, func() error {
+		return d.db.SaveAlert(ctx, alert)
+	})
+}
+
+func (d *retryingDB) SaveIssue(ctx context.Context, issue Issue) error {
+	return d.do(ctx, "SaveIssue", true, func() error {
+		return d.db.SaveIssue(ctx, issue)
+	})
+}
+
+func (d *retryingDB) SaveIssues(ctx context.Context, issues ...Issue) error {
+	return d.do(ctx, "SaveIssues", true, func() error {
+		return d.db.SaveIssues(ctx, issues...)
+	})
+}
+
+func (d *retryingDB) MoveIssue(ctx context.Context, issue Issue, sourceChannelID, targetChannelID string) error {
+	return d.do(ctx, "MoveIssue", false, func() error {
+		return d.db.MoveIssue(ctx, issue, sourceChannelID, targetChannelID)
+	})
+}
+
+func (d *retryingDB) FindOpenIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (string, json.RawMessage, error) {
+	var (
+		id   string
+		body json.RawMessage
+	)
+
+	err := d.do(ctx, "FindOpenIssueByCorrelationID", true, func() error {
+		var err error
+		id, body, err = d.db.FindOpenIssueByCorrelationID(ctx, channelID, correlationID)
+
+		return err
+	})
+
+	return id, body, err
+}
+
+func (d *versionedRetryingDB) FindOpenVersionedIssueByCorrelationID(ctx context.Context, channelID, correlationID string) (*StoredIssue, error) {
+	var issue *StoredIssue
+
+	err := d.do(ctx, "FindOpenVersionedIssueByCorrelationID", true, func() error {
+		var err error
+		issue, err = d.versioned.FindOpenVersionedIssueByCorrelationID(ctx, channelID, correlationID)
+
+		return err
+	})
+
+	return issue, err
+}
+
+func (d *retryingDB) FindIssueBySlackPostID(ctx context.Context, channelID, postID string) (string, json.RawMessage, error) {
+	var (
+		id   string
+		body json.RawMessage
+	)
+
+	err := d.do(ctx, "FindIssueBySlackPostID", true, func() error {
+		var err error
+		id, body, err = d.db.FindIssueBySlackPostID(ctx, channelID, postID)
+
+		return err
+	})
+
+	return id, body, err
+}
+
+func (d *versionedRetryingDB) FindVersionedIssueBySlackPostID(ctx context.Context, channelID, postID string) (*StoredIssue, error) {
+	var issue *StoredIssue
+
+	err := d.do(ctx, "FindVersionedIssueBySlackPostID", true, func() error {
+		var err error
+		issue, err = d.versioned.FindVersionedIssueBySlackPostID(ctx, channelID, postID)
+
+		return err
+	})
+
+	return issue, err
+}
+
+func (d *retryingDB) FindActiveChannels(ctx context.Context) ([]string, error) {
+	var channels []string
+
+	err := d.do(ctx, "FindActiveChannels", true, func() error {
+		var err error
+		channels, err = d.db.FindActiveChannels(ctx)
+
+		return err
+	})
+
+	return channels, err
+}
+
+func (d *retryingDB) LoadOpenIssuesInChannel(ctx context.Context, channelID string) (map[string]json.RawMessage, error) {
+	var issues map[string]json.RawMessage
+
+	err := d.do(ctx, "LoadOpenIssuesInChannel", true, func() error {
+		var err error
+		issues, err = d.db.LoadOpenIssuesInChannel(ctx, channelID)
+
+		return err
+	})
+
+	return issues, err
+}
+
+func (d *versionedRetryingDB) LoadOpenVersionedIssuesInChannel(ctx context.Context, channelID string) (map[string]*StoredIssue, error) {
+	var issues map[string]*StoredIssue
+
+	err := d.do(ctx, "LoadOpenVersionedIssuesInChannel", true, func() error {
+		var err error
+		issues, err = d.versioned.LoadOpenVersionedIssuesInChannel(ctx, channelID)
+
+		return err
+	})
+
+	return issues, err
+}
+
+func (d *retryingDB) SaveMoveMapping(ctx context.Context, moveMapping MoveMapping) error {
+	return d.do(ctx, "SaveMoveMapping", true, func() error {
+		return d.db.SaveMoveMapping(ctx, moveMapping)
+	})
+}
+
+func (d *retryingDB) FindMoveMapping(ctx context.Context, channelID, correlationID string) (json.RawMessage, error) {
+	var body json.RawMessage
+
+	err := d.do(ctx, "FindMoveMapping", true, func() error {
+		var err error
+		body, err = d.db.FindMoveMapping(ctx, channelID, correlationID)
+
+		return err
+	})
+
+	return body, err
+}
+
+func (d *retryingDB) DeleteMoveMapping(ctx context.Context, channelID, correlationID string) error {
+	return d.do(ctx, "DeleteMoveMapping", true, func() error {
+		return d.db.DeleteMoveMapping(ctx, channelID, correlationID)
+	})
+}
+
+func (d *retryingDB) SaveChannelProcessingState(ctx context.Context, state *ChannelProcessingState) error {
+	return d.do(ctx, "SaveChannelProcessingState", true, func() error {
+		return d.db.SaveChannelProcessingState(ctx, state)
+	})
+}
+
+func (d *retryingDB) FindChannelProcessingState(ctx context.Context, channelID string) (*ChannelProcessingState, error) {
+	var state *ChannelProcessingState
+
+	err := d.do(ctx, "FindChannelProcessingState", true, func() error {
+		var err error
+		state, err = d.db.FindChannelProcessingState(ctx, channelID)
+
+		return err
+	})
+
+	return state, err
+}
+
+func (d *retryingDB) DropAllData(ctx context.Context) error {
+	return d.do(ctx, "DropAllData", true, func() error {
+		return d.db.DropAllData(ctx)
+	})
+}
+
+// do calls fn until it succeeds, the error is not retryable, the max attempts are reached, or the context is done.
+// The last error is returned.
+func (d *retryingDB) do(ctx context.Context, method string, idempotent bool, fn func() error) error {
+	backoff := d.policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= d.policy.MaxAttempts || (!idempotent && !d.policy.RetryNonIdempotent) ||
+			ctx.Err() != nil || !d.policy.IsRetryable(err) {
+			return err
+		}
+
+		delay := d.jitter(backoff)
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		if d.policy.OnRetry != nil {
+			d.policy.OnRetry(method, attempt, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff = min(time.Duration(float64(backoff)*d.policy.Multiplier), d.policy.MaxBackoff)
+	}
+}
+
+// jitter returns the backoff, reduced by a random fraction of up to the policy jitter.
+func (d *retryingDB) jitter(backoff time.Duration) time.Duration {
+	backoff = min(backoff, d.policy.MaxBackoff)
+
+	if d.policy.Jitter == 0 {
+		return backoff
+	}
+
+	return time.Duration(float64(backoff) * (1 - d.policy.Jitter*rand.Float64()))
+}
@@ -0,0 +1,187 @@
+package types_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/slackmgr/types"
+	"github.com/slackmgr/types/dbtests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	p := types.DefaultRetryPolicy()
+	assert.Equal(t, 4, p.MaxAttempts)
+	assert.Equal(t, 50*time.Millisecond, p.InitialBackoff)
+	assert.Equal(t, 2*time.Second, p.MaxBackoff)
+	assert.InDelta(t, 2, p.Multiplier, 0)
+	assert.InDelta(t, 0.2, p.Jitter, 0)
+	assert.Nil(t, p.IsRetryable)
+
+	assert.True(t, types.DefaultIsRetryable(errDBUnavailable))
+	assert.True(t, types.DefaultIsRetryable(fmt.Errorf("find issue: %w", errDBUnavailable)))
+	assert.True(t, types.DefaultIsRetryable(&net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}))
+	assert.False(t, types.DefaultIsRetryable(nil))
+	assert.False(t, types.DefaultIsRetryable(errors.New("syntax error")))
+	assert.False(t, types.DefaultIsRetryable(&temporaryError{msg: "permanent", permanent: true}))
+	assert.False(t, types.DefaultIsRetryable(context.Canceled))
+	assert.False(t, types.DefaultIsRetryable(context.DeadlineExceeded))
+	assert.False(t, types.DefaultIsRetryable(&types.ConflictError{IssueID: "issue-1", ExpectedVersion: 1, CurrentVersion: 2}))
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retrying db should pass the db tests", func(t *testing.T) {
+		t.Parallel()
+
+		dbtests.RunAllTests(t, types.WithRetry(types.NewInMemoryDB(), types.DefaultRetryPolicy()))
+	})
+
+	t.Run("transient errors should be retried with exponential backoff", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyDB(t, 2, "FindMoveMapping")
+
+		var delays []time.Duration
+
+		policy := types.RetryPolicy{
+			InitialBackoff: time.Millisecond,
+			Multiplier:     3,
+			OnRetry: func(method string, _ int, err error, delay time.Duration) {
+				assert.Equal(t, "FindMoveMapping", method)
+				require.ErrorIs(t, err, errDBUnavailable)
+				delays = append(delays, delay)
+			},
+		}
+
+		db := types.WithRetry(flaky, policy)
+
+		_, err := db.FindMoveMapping(context.Background(), "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, 3, flaky.Calls("FindMoveMapping"))
+		assert.Equal(t, []time.Duration{time.Millisecond, 3 * time.Millisecond}, delays)
+	})
+
+	t.Run("versioned lookups should be retried", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyDB(t, 1, "FindOpenVersionedIssueByCorrelationID")
+
+		db, ok := types.WithRetry(flaky.DB(), types.RetryPolicy{InitialBackoff: time.Millisecond}).(types.VersionedDB)
+		require.True(t, ok, "retrying db should implement VersionedDB if the wrapped db does")
+
+		_, err := db.FindOpenVersionedIssueByCorrelationID(context.Background(), "C000000001", "corr-1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, flaky.Calls("FindOpenVersionedIssueByCorrelationID"))
+
+		_, ok = types.WithRetry(unversionedDB{types.NewInMemoryDB()}, types.DefaultRetryPolicy()).(types.VersionedDB)
+		assert.False(t, ok, "retrying db should only implement VersionedDB if the wrapped db does")
+	})
+
+	t.Run("last error should be returned after max attempts", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyDB(t, 10, "DeleteMoveMapping")
+		db := types.WithRetry(flaky, types.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+		err := db.DeleteMoveMapping(context.Background(), "C000000001", "corr-1")
+		require.ErrorIs(t, err, errDBUnavailable)
+		assert.Equal(t, 3, flaky.Calls("DeleteMoveMapping"))
+	})
+
+	t.Run("backoff should be limited by max backoff and jitter", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyDB(t, 10, "DeleteMoveMapping")
+
+		var delays []time.Duration
+
+		policy := types.RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: 2 * time.Millisecond,
+			MaxBackoff:     5 * time.Millisecond,
+			Jitter:         0.5,
+			OnRetry: func(_ string, _ int, _ error, delay time.Duration) {
+				delays = append(delays, delay)
+			},
+		}
+
+		_ = types.WithRetry(flaky, policy).DeleteMoveMapping(context.Background(), "C000000001", "corr-1")
+
+		require.Len(t, delays, 4)
+
+		for i, expected := range []time.Duration{2, 4, 5, 5} {
+			assert.LessOrEqual(t, delays[i], expected*time.Millisecond)
+			assert.GreaterOrEqual(t, delays[i], expected*time.Millisecond/2)
+		}
+	})
+
+	t.Run("non-retryable errors should not be retried", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyDB(t, 10, "DeleteMoveMapping")
+		policy := types.DefaultRetryPolicy()
+		policy.IsRetryable = func(err error) bool { return !errors.Is(err, errDBUnavailable) }
+
+		err := types.WithRetry(flaky, policy).DeleteMoveMapping(context.Background(), "C000000001", "corr-1")
+		require.ErrorIs(t, err, errDBUnavailable)
+		assert.Equal(t, 1, flaky.Calls("DeleteMoveMapping"))
+	})
+
+	t.Run("non-idempotent methods should only be retried when enabled", func(t *testing.T) {
+		t.Parallel()
+
+		issue := newTestIssue("issue-1", "C000000002", "corr-1", "")
+		flaky := newFlakyDB(t, 1, "MoveIssue")
+		policy := types.RetryPolicy{InitialBackoff: time.Millisecond}
+
+		err := types.WithRetry(flaky, policy).MoveIssue(context.Background(), issue, "C000000001", "C000000002")
+		require.ErrorIs(t, err, errDBUnavailable)
+		assert.Equal(t, 1, flaky.Calls("MoveIssue"))
+
+		flaky = newFlakyDB(t, 1, "MoveIssue")
+		policy.RetryNonIdempotent = true
+
+		require.NoError(t, types.WithRetry(flaky, policy).MoveIssue(context.Background(), issue, "C000000001", "C000000002"))
+		assert.Equal(t, 2, flaky.Calls("MoveIssue"))
+	})
+
+	t.Run("retry should not be started if the context deadline would pass", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		flaky := newFlakyDB(t, 10, "DeleteMoveMapping")
+		policy := types.RetryPolicy{InitialBackoff: time.Second, Jitter: 0}
+
+		start := time.Now()
+		err := types.WithRetry(flaky, policy).DeleteMoveMapping(ctx, "C000000001", "corr-1")
+		require.ErrorIs(t, err, errDBUnavailable)
+		assert.Equal(t, 1, flaky.Calls("DeleteMoveMapping"))
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("canceled context should stop the backoff", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		flaky := newFlakyDB(t, 10, "DeleteMoveMapping")
+		policy := types.RetryPolicy{InitialBackoff: time.Minute, MaxBackoff: time.Minute}
+
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		err := types.WithRetry(flaky, policy).DeleteMoveMapping(ctx, "C000000001", "corr-1")
+		require.ErrorIs(t, err, errDBUnavailable)
+		assert.Equal(t, 1, flaky.Calls("DeleteMoveMapping"))
+	})
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationErrorCode is a machine-readable code describing why a validation check failed.
+type ValidationErrorCode string
+
+const (
+	// ValidationErrorRequired indicates that a required value is missing (or nil).
+	ValidationErrorRequired ValidationErrorCode = "required"
+
+	// ValidationErrorTooLong indicates that a value exceeds its maximum length.
+	ValidationErrorTooLong ValidationErrorCode = "too_long"
+
+	// ValidationErrorTooShort indicates that a value is shorter than its minimum length.
+	ValidationErrorTooShort ValidationErrorCode = "too_short"
+
+	// ValidationErrorTooMany indicates that a list or map has too many items.
+	ValidationErrorTooMany ValidationErrorCode = "too_many"
+
+	// ValidationErrorNotUnique indicates that a value must be unique, but is used more than once.
+	ValidationErrorNotUnique ValidationErrorCode = "not_unique"
+
+	// ValidationErrorInvalidFormat indicates that a value does not match the expected format (e.g. a URL or a Slack mention).
+	ValidationErrorInvalidFormat ValidationErrorCode = "invalid_format"
+
+	// ValidationErrorInvalidValue indicates that a value is not one of the allowed values (e.g. an unknown severity).
+	ValidationErrorInvalidValue ValidationErrorCode = "invalid_value"
+
+	// ValidationErrorOutOfRange indicates that a numeric value is outside the allowed range.
+	ValidationErrorOutOfRange ValidationErrorCode = "out_of_range"
+)
+
+// ValidationError describes a single failed validation check.
+type ValidationError struct {
+	// Field is a JSON pointer (RFC 6901) to the offending value, based on the JSON field names,
+	// such as '/webhooks/2/checkboxInput/0/options/3/value'. An empty string refers to the alert itself.
+	Field string `json:"field"`
+
+	// Code is the machine-readable reason for the failure.
+	Code ValidationErrorCode `json:"code"`
+
+	// Limit is the limit that was broken, if any (e.g. the maximum length or item count).
+	Limit *int `json:"limit,omitempty"`
+
+	// Message is a human-readable description of the failure.
+	Message string `json:"message"`
+}
+
+// Error returns the human-readable message of the validation error.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors is a list of validation errors, collected in a single validation pass.
+type ValidationErrors []*ValidationError
+
+// Error returns all validation error messages, separated by semicolons.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the individual validation errors, for use with errors.Is and errors.As.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// first returns the first validation error, or nil if the list is empty.
+func (e ValidationErrors) first() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e[0]
+}
+
+// addf appends a validation error without a limit.
+func (e *ValidationErrors) addf(field string, code ValidationErrorCode, format string, args ...any) {
+	*e = append(*e, &ValidationError{
+		Field:   field,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// addLimitf appends a validation error with the limit that was broken.
+func (e *ValidationErrors) addLimitf(field string, code ValidationErrorCode, limit int, format string, args ...any) {
+	*e = append(*e, &ValidationError{
+		Field:   field,
+		Code:    code,
+		Limit:   &limit,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
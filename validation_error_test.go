@@ -0,0 +1,142 @@
+package types_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertValidateAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil alert should return a single error", func(t *testing.T) {
+		t.Parallel()
+
+		var a *types.Alert
+		err := a.ValidateAll()
+		require.Error(t, err)
+
+		var errs types.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, types.ValidationErrorRequired, errs[0].Code)
+	})
+
+	t.Run("valid alert should return nil", func(t *testing.T) {
+		t.Parallel()
+
+		a := &types.Alert{SlackChannelID: "C12345678", Header: "foo"}
+		a.Clean()
+		require.NoError(t, a.ValidateAll())
+	})
+
+	t.Run("all errors should be collected in a single pass", func(t *testing.T) {
+		t.Parallel()
+
+		a := &types.Alert{
+			SlackChannelID: "C12345678",
+			IconEmoji:      "invalid",
+			Link:           "not a url",
+			Severity:       "foo",
+			Webhooks: []*types.Webhook{
+				{ID: "a", URL: "https://example.com", ButtonText: "a"},
+				{ID: "a", URL: "https://example.com", ButtonText: strings.Repeat("b", types.MaxWebhookButtonTextLength+1)},
+				{
+					ID: "c", URL: "https://example.com", ButtonText: "c",
+					CheckboxInput: []*types.WebhookCheckboxInput{
+						{ID: "x", Options: []*types.WebhookCheckboxOption{{Value: "1"}, {Value: "1"}}},
+					},
+				},
+			},
+			Escalation: []*types.Escalation{
+				{Severity: types.AlertPanic, DelaySeconds: 10},
+				{Severity: types.AlertInfo, DelaySeconds: 60},
+			},
+		}
+
+		err := a.ValidateAll()
+		require.Error(t, err)
+
+		var errs types.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+
+		type result struct {
+			field string
+			code  types.ValidationErrorCode
+		}
+
+		results := make([]result, len(errs))
+		for i, e := range errs {
+			results[i] = result{field: e.Field, code: e.Code}
+		}
+
+		assert.Equal(t, []result{
+			{"/header", types.ValidationErrorRequired},
+			{"/iconEmoji", types.ValidationErrorInvalidFormat},
+			{"/link", types.ValidationErrorInvalidFormat},
+			{"/severity", types.ValidationErrorInvalidValue},
+			{"/webhooks/1/id", types.ValidationErrorNotUnique},
+			{"/webhooks/1/buttonText", types.ValidationErrorTooLong},
+			{"/webhooks/2/checkboxInput/0/options/1/value", types.ValidationErrorNotUnique},
+			{"/escalation/0/delaySeconds", types.ValidationErrorOutOfRange},
+			{"/escalation/1/severity", types.ValidationErrorInvalidValue},
+		}, results)
+
+		require.NotNil(t, errs[5].Limit)
+		assert.Equal(t, types.MaxWebhookButtonTextLength, *errs[5].Limit)
+		assert.Nil(t, errs[4].Limit)
+
+		// The first collected error should match the error returned by Validate
+		assert.Equal(t, errs[0].Error(), a.Validate().Error())
+	})
+
+	t.Run("error message should join all messages", func(t *testing.T) {
+		t.Parallel()
+
+		a := &types.Alert{Severity: types.AlertError, IconEmoji: "invalid"}
+		err := a.ValidateAll()
+		require.EqualError(t, err, "header and text cannot both be empty; iconEmoji 'invalid' is not valid")
+	})
+
+	t.Run("errors should marshal to JSON", func(t *testing.T) {
+		t.Parallel()
+
+		a := &types.Alert{Header: "foo", Severity: types.AlertError, Fields: make([]*types.Field, types.MaxFieldCount+1)}
+		err := a.ValidateAll()
+		require.Error(t, err)
+
+		body, jsonErr := json.Marshal(err)
+		require.NoError(t, jsonErr)
+		assert.JSONEq(t, `[{"field":"/fields","code":"too_many","limit":20,"message":"too many fields, expected <=20"}]`, string(body))
+	})
+}
+
+func TestAlertValidateReturnsValidationError(t *testing.T) {
+	t.Parallel()
+
+	a := &types.Alert{
+		Header:   "foo",
+		Severity: types.AlertError,
+		Webhooks: []*types.Webhook{
+			{ID: "a", URL: "https://example.com", ButtonText: "a", PlainTextInput: []*types.WebhookPlainTextInput{{ID: "x", MinLength: 10, MaxLength: 5}}},
+		},
+	}
+
+	err := a.Validate()
+	require.EqualError(t, err, "webhook[0].plainTextInput[0].maxLength cannot be smaller than minLength")
+
+	var validationErr *types.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "/webhooks/0/plainTextInput/0/maxLength", validationErr.Field)
+	assert.Equal(t, types.ValidationErrorOutOfRange, validationErr.Code)
+
+	err = a.ValidateWebhooks()
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "/webhooks/0/plainTextInput/0/maxLength", validationErr.Field)
+
+	require.NoError(t, a.ValidateEscalation())
+}
@@ -0,0 +1,213 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationPolicy defines the limits used when cleaning and validating alerts.
+// Use DefaultValidationPolicy to get a policy with the package default limits (the Max*/Min* constants),
+// and adjust individual limits as needed, for example to only allow a single webhook per alert,
+// or to allow more escalation points than the default.
+//
+// Note that some defaults reflect hard Slack limits (e.g. MaxWebhookButtonTextLength), and loosening them may
+// produce alerts that cannot be rendered by Slack.
+type ValidationPolicy struct {
+	// MaxTimestampAge is the maximum age of an alert timestamp. Older timestamps are replaced with the current time.
+	MaxTimestampAge time.Duration `json:"maxTimestampAge"`
+
+	// Alert field length limits.
+
+	MaxSlackChannelIDLength int `json:"maxSlackChannelIdLength"`
+	MaxRouteKeyLength       int `json:"maxRouteKeyLength"`
+	MaxHeaderLength         int `json:"maxHeaderLength"`
+	MaxFallbackTextLength   int `json:"maxFallbackTextLength"`
+	MaxTextLength           int `json:"maxTextLength"`
+	MaxAuthorLength         int `json:"maxAuthorLength"`
+	MaxHostLength           int `json:"maxHostLength"`
+	MaxFooterLength         int `json:"maxFooterLength"`
+	MaxUsernameLength       int `json:"maxUsernameLength"`
+	MaxFieldTitleLength     int `json:"maxFieldTitleLength"`
+	MaxFieldValueLength     int `json:"maxFieldValueLength"`
+	MaxIconEmojiLength      int `json:"maxIconEmojiLength"`
+	MaxMentionLength        int `json:"maxMentionLength"`
+	MaxCorrelationIDLength  int `json:"maxCorrelationIdLength"`
+
+	// Auto-resolve timing limits.
+
+	MinAutoResolveSeconds int `json:"minAutoResolveSeconds"`
+	MaxAutoResolveSeconds int `json:"maxAutoResolveSeconds"`
+
+	// IgnoreIfTextContains limits.
+
+	MaxIgnoreIfTextContainsLength int `json:"maxIgnoreIfTextContainsLength"`
+	MaxIgnoreIfTextContainsCount  int `json:"maxIgnoreIfTextContainsCount"`
+
+	// MaxFieldCount is the maximum number of fields per alert.
+	MaxFieldCount int `json:"maxFieldCount"`
+
+	// Webhook limits.
+
+	MaxWebhookCount                    int `json:"maxWebhookCount"`
+	MaxWebhookIDLength                 int `json:"maxWebhookIdLength"`
+	MaxWebhookURLLength                int `json:"maxWebhookUrlLength"`
+	MaxWebhookButtonTextLength         int `json:"maxWebhookButtonTextLength"`
+	MaxWebhookConfirmationTextLength   int `json:"maxWebhookConfirmationTextLength"`
+	MaxWebhookPayloadCount             int `json:"maxWebhookPayloadCount"`
+	MaxWebhookPlainTextInputCount      int `json:"maxWebhookPlainTextInputCount"`
+	MaxWebhookCheckboxInputCount       int `json:"maxWebhookCheckboxInputCount"`
+	MaxWebhookInputIDLength            int `json:"maxWebhookInputIdLength"`
+	MaxWebhookInputDescriptionLength   int `json:"maxWebhookInputDescriptionLength"`
+	MaxWebhookInputLabelLength         int `json:"maxWebhookInputLabelLength"`
+	MaxWebhookInputTextLength          int `json:"maxWebhookInputTextLength"`
+	MaxWebhookCheckboxOptionCount      int `json:"maxWebhookCheckboxOptionCount"`
+	MaxWebhookCheckboxOptionTextLength int `json:"maxWebhookCheckboxOptionTextLength"`
+	MaxCheckboxOptionValueLength       int `json:"maxCheckboxOptionValueLength"`
+
+	// Escalation limits.
+
+	MaxEscalationCount             int `json:"maxEscalationCount"`
+	MinEscalationDelaySeconds      int `json:"minEscalationDelaySeconds"`
+	MinEscalationDelayDiffSeconds  int `json:"minEscalationDelayDiffSeconds"`
+	MaxEscalationSlackMentionCount int `json:"maxEscalationSlackMentionCount"`
+}
+
+// DefaultValidationPolicy returns a new ValidationPolicy with the package default limits.
+// This is the policy used by Alert.Clean and Alert.Validate.
+func DefaultValidationPolicy() *ValidationPolicy {
+	return &ValidationPolicy{
+		MaxTimestampAge:                    MaxTimestampAge,
+		MaxSlackChannelIDLength:            MaxSlackChannelIDLength,
+		MaxRouteKeyLength:                  MaxRouteKeyLength,
+		MaxHeaderLength:                    MaxHeaderLength,
+		MaxFallbackTextLength:              MaxFallbackTextLength,
+		MaxTextLength:                      MaxTextLength,
+		MaxAuthorLength:                    MaxAuthorLength,
+		MaxHostLength:                      MaxHostLength,
+		MaxFooterLength:                    MaxFooterLength,
+		MaxUsernameLength:                  MaxUsernameLength,
+		MaxFieldTitleLength:                MaxFieldTitleLength,
+		MaxFieldValueLength:                MaxFieldValueLength,
+		MaxIconEmojiLength:                 MaxIconEmojiLength,
+		MaxMentionLength:                   MaxMentionLength,
+		MaxCorrelationIDLength:             MaxCorrelationIDLength,
+		MinAutoResolveSeconds:              MinAutoResolveSeconds,
+		MaxAutoResolveSeconds:              MaxAutoResolveSeconds,
+		MaxIgnoreIfTextContainsLength:      MaxIgnoreIfTextContainsLength,
+		MaxIgnoreIfTextContainsCount:       MaxIgnoreIfTextContainsCount,
+		MaxFieldCount:                      MaxFieldCount,
+		MaxWebhookCount:                    MaxWebhookCount,
+		MaxWebhookIDLength:                 MaxWebhookIDLength,
+		MaxWebhookURLLength:                MaxWebhookURLLength,
+		MaxWebhookButtonTextLength:         MaxWebhookButtonTextLength,
+		MaxWebhookConfirmationTextLength:   MaxWebhookConfirmationTextLength,
+		MaxWebhookPayloadCount:             MaxWebhookPayloadCount,
+		MaxWebhookPlainTextInputCount:      MaxWebhookPlainTextInputCount,
+		MaxWebhookCheckboxInputCount:       MaxWebhookCheckboxInputCount,
+		MaxWebhookInputIDLength:            MaxWebhookInputIDLength,
+		MaxWebhookInputDescriptionLength:   MaxWebhookInputDescriptionLength,
+		MaxWebhookInputLabelLength:         MaxWebhookInputLabelLength,
+		MaxWebhookInputTextLength:          MaxWebhookInputTextLength,
+		MaxWebhookCheckboxOptionCount:      MaxWebhookCheckboxOptionCount,
+		MaxWebhookCheckboxOptionTextLength: MaxWebhookCheckboxOptionTextLength,
+		MaxCheckboxOptionValueLength:       MaxCheckboxOptionValueLength,
+		MaxEscalationCount:                 MaxEscalationCount,
+		MinEscalationDelaySeconds:          MinEscalationDelaySeconds,
+		MinEscalationDelayDiffSeconds:      MinEscalationDelayDiffSeconds,
+		MaxEscalationSlackMentionCount:     MaxEscalationSlackMentionCount,
+	}
+}
+
+// Validate returns an error if the policy itself is invalid, i.e. if any limit is negative,
+// MaxTimestampAge is not positive, or MinAutoResolveSeconds is larger than MaxAutoResolveSeconds.
+func (p *ValidationPolicy) Validate() error {
+	if p == nil {
+		return errors.New("validation policy is nil")
+	}
+
+	if p.MaxTimestampAge <= 0 {
+		return errors.New("maxTimestampAge must be >0")
+	}
+
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"maxSlackChannelIdLength", p.MaxSlackChannelIDLength},
+		{"maxRouteKeyLength", p.MaxRouteKeyLength},
+		{"maxHeaderLength", p.MaxHeaderLength},
+		{"maxFallbackTextLength", p.MaxFallbackTextLength},
+		{"maxTextLength", p.MaxTextLength},
+		{"maxAuthorLength", p.MaxAuthorLength},
+		{"maxHostLength", p.MaxHostLength},
+		{"maxFooterLength", p.MaxFooterLength},
+		{"maxUsernameLength", p.MaxUsernameLength},
+		{"maxFieldTitleLength", p.MaxFieldTitleLength},
+		{"maxFieldValueLength", p.MaxFieldValueLength},
+		{"maxIconEmojiLength", p.MaxIconEmojiLength},
+		{"maxMentionLength", p.MaxMentionLength},
+		{"maxCorrelationIdLength", p.MaxCorrelationIDLength},
+		{"minAutoResolveSeconds", p.MinAutoResolveSeconds},
+		{"maxAutoResolveSeconds", p.MaxAutoResolveSeconds},
+		{"maxIgnoreIfTextContainsLength", p.MaxIgnoreIfTextContainsLength},
+		{"maxIgnoreIfTextContainsCount", p.MaxIgnoreIfTextContainsCount},
+		{"maxFieldCount", p.MaxFieldCount},
+		{"maxWebhookCount", p.MaxWebhookCount},
+		{"maxWebhookIdLength", p.MaxWebhookIDLength},
+		{"maxWebhookUrlLength", p.MaxWebhookURLLength},
+		{"maxWebhookButtonTextLength", p.MaxWebhookButtonTextLength},
+		{"maxWebhookConfirmationTextLength", p.MaxWebhookConfirmationTextLength},
+		{"maxWebhookPayloadCount", p.MaxWebhookPayloadCount},
+		{"maxWebhookPlainTextInputCount", p.MaxWebhookPlainTextInputCount},
+		{"maxWebhookCheckboxInputCount", p.MaxWebhookCheckboxInputCount},
+		{"maxWebhookInputIdLength", p.MaxWebhookInputIDLength},
+		{"maxWebhookInputDescriptionLength", p.MaxWebhookInputDescriptionLength},
+		{"maxWebhookInputLabelLength", p.MaxWebhookInputLabelLength},
+		{"maxWebhookInputTextLength", p.MaxWebhookInputTextLength},
+		{"maxWebhookCheckboxOptionCount", p.MaxWebhookCheckboxOptionCount},
+		{"maxWebhookCheckboxOptionTextLength", p.MaxWebhookCheckboxOptionTextLength},
+		{"maxCheckboxOptionValueLength", p.MaxCheckboxOptionValueLength},
+		{"maxEscalationCount", p.MaxEscalationCount},
+		{"minEscalationDelaySeconds", p.MinEscalationDelaySeconds},
+		{"minEscalationDelayDiffSeconds", p.MinEscalationDelayDiffSeconds},
+		{"maxEscalationSlackMentionCount", p.MaxEscalationSlackMentionCount},
+	}
+
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return fmt.Errorf("%s must be >=0", limit.name)
+		}
+	}
+
+	if p.MinAutoResolveSeconds > p.MaxAutoResolveSeconds {
+		return errors.New("minAutoResolveSeconds cannot be larger than maxAutoResolveSeconds")
+	}
+
+	return nil
+}
+
+// slackChannelIDOrNameIsValid returns true if s is a valid Slack channel ID or name, within the policy length limit.
+func (p *ValidationPolicy) slackChannelIDOrNameIsValid(s string) bool {
+	return len(s) <= p.MaxSlackChannelIDLength && slackChannelIDOrNamePattern.MatchString(s)
+}
+
+// iconEmojiIsValid returns true if s is a valid Slack icon emoji, within the policy length limit.
+func (p *ValidationPolicy) iconEmojiIsValid(s string) bool {
+	return iconPattern.MatchString(s) && utf8.RuneCountInString(s)-2 <= p.MaxIconEmojiLength
+}
+
+// slackMentionIsValid returns true if s is a valid Slack mention, within the policy length limit.
+func (p *ValidationPolicy) slackMentionIsValid(s string) bool {
+	if !slackMentionPattern.MatchString(s) {
+		return false
+	}
+
+	if strings.HasPrefix(s, "<@") {
+		return utf8.RuneCountInString(s)-3 <= p.MaxMentionLength
+	}
+
+	return true
+}
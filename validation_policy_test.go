@@ -0,0 +1,176 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/slackmgr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultValidationPolicy(t *testing.T) {
+	t.Parallel()
+
+	p := types.DefaultValidationPolicy()
+	require.NoError(t, p.Validate())
+	assert.Equal(t, types.MaxTimestampAge, p.MaxTimestampAge)
+	assert.Equal(t, types.MaxHeaderLength, p.MaxHeaderLength)
+	assert.Equal(t, types.MaxTextLength, p.MaxTextLength)
+	assert.Equal(t, types.MaxWebhookCount, p.MaxWebhookCount)
+	assert.Equal(t, types.MaxEscalationCount, p.MaxEscalationCount)
+	assert.Equal(t, types.MinAutoResolveSeconds, p.MinAutoResolveSeconds)
+	assert.Equal(t, types.MaxEscalationSlackMentionCount, p.MaxEscalationSlackMentionCount)
+
+	// Each call should return a new instance
+	p.MaxWebhookCount = 1
+	assert.Equal(t, types.MaxWebhookCount, types.DefaultValidationPolicy().MaxWebhookCount)
+}
+
+func TestValidationPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	var p *types.ValidationPolicy
+	require.Error(t, p.Validate())
+
+	p = types.DefaultValidationPolicy()
+	p.MaxTimestampAge = 0
+	require.ErrorContains(t, p.Validate(), "maxTimestampAge")
+
+	p = types.DefaultValidationPolicy()
+	p.MaxFieldCount = -1
+	require.ErrorContains(t, p.Validate(), "maxFieldCount")
+
+	p = types.DefaultValidationPolicy()
+	p.MinAutoResolveSeconds = p.MaxAutoResolveSeconds + 1
+	require.ErrorContains(t, p.Validate(), "minAutoResolveSeconds")
+
+	a := &types.Alert{Header: "foo", Severity: types.AlertError}
+	require.ErrorContains(t, a.ValidateWithPolicy(p), "invalid validation policy")
+	require.ErrorContains(t, a.ValidateAllWithPolicy(p), "invalid validation policy")
+}
+
+func TestAlertCleanWithPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("text should be truncated at the policy limit", func(t *testing.T) {
+		t.Parallel()
+
+		p := types.DefaultValidationPolicy()
+		p.MaxTextLength = 3000
+
+		a := &types.Alert{Text: strings.Repeat("a", 5000)}
+		a.CleanWithPolicy(p)
+		assert.Equal(t, 3000, utf8.RuneCountInString(a.Text))
+		assert.True(t, strings.HasSuffix(a.Text, "..."))
+
+		a = &types.Alert{Text: strings.Repeat("a", 5000) + "```"}
+		a.CleanWithPolicy(p)
+		assert.Equal(t, 3000, utf8.RuneCountInString(a.Text))
+		assert.True(t, strings.HasSuffix(a.Text, "...```"))
+	})
+
+	t.Run("header and fields should be truncated at the policy limits", func(t *testing.T) {
+		t.Parallel()
+
+		p := types.DefaultValidationPolicy()
+		p.MaxHeaderLength = 10
+		p.MaxFieldTitleLength = 5
+
+		a := &types.Alert{
+			Header: "this header is too long",
+			Fields: []*types.Field{{Title: "too long title", Value: "v"}},
+		}
+		a.CleanWithPolicy(p)
+		assert.Equal(t, "this he...", a.Header)
+		assert.Equal(t, "to...", a.Fields[0].Title)
+	})
+
+	t.Run("very small limits should not panic", func(t *testing.T) {
+		t.Parallel()
+
+		p := types.DefaultValidationPolicy()
+		p.MaxHeaderLength = 2
+		p.MaxTextLength = 0
+
+		a := &types.Alert{Header: "header", Text: "text```"}
+		a.CleanWithPolicy(p)
+		assert.Equal(t, "he", a.Header)
+		assert.Empty(t, a.Text)
+	})
+
+	t.Run("nil policy should use the default policy", func(t *testing.T) {
+		t.Parallel()
+
+		a := &types.Alert{Header: strings.Repeat("a", types.MaxHeaderLength+10)}
+		a.CleanWithPolicy(nil)
+		assert.Equal(t, types.MaxHeaderLength, utf8.RuneCountInString(a.Header))
+	})
+}
+
+func TestAlertValidateWithPolicy(t *testing.T) {
+	t.Parallel()
+
+	newWebhook := func(id string) *types.Webhook {
+		return &types.Webhook{ID: id, URL: "https://example.com", ButtonText: id}
+	}
+
+	t.Run("tightened webhook count", func(t *testing.T) {
+		t.Parallel()
+
+		p := types.DefaultValidationPolicy()
+		p.MaxWebhookCount = 1
+
+		a := &types.Alert{Header: "foo", Webhooks: []*types.Webhook{newWebhook("a"), newWebhook("b")}}
+		a.CleanWithPolicy(p)
+		require.NoError(t, a.Validate())
+		require.ErrorContains(t, a.ValidateWithPolicy(p), "too many webhooks, expected <=1")
+
+		var errs types.ValidationErrors
+		require.ErrorAs(t, a.ValidateAllWithPolicy(p), &errs)
+		require.Len(t, errs, 1)
+		require.NotNil(t, errs[0].Limit)
+		assert.Equal(t, 1, *errs[0].Limit)
+	})
+
+	t.Run("loosened escalation count", func(t *testing.T) {
+		t.Parallel()
+
+		p := types.DefaultValidationPolicy()
+		p.MaxEscalationCount = 5
+
+		a := &types.Alert{Header: "foo"}
+		for i := range 5 {
+			a.Escalation = append(a.Escalation, &types.Escalation{Severity: types.AlertPanic, DelaySeconds: 60 * (i + 1)})
+		}
+		a.CleanWithPolicy(p)
+		require.ErrorContains(t, a.Validate(), "too many escalation points")
+		require.NoError(t, a.ValidateWithPolicy(p))
+	})
+
+	t.Run("length limits encoded in regexes", func(t *testing.T) {
+		t.Parallel()
+
+		p := types.DefaultValidationPolicy()
+		p.MaxSlackChannelIDLength = 5
+		p.MaxIconEmojiLength = 3
+		p.MaxMentionLength = 4
+
+		a := &types.Alert{Header: "foo", SlackChannelID: "C12345", Severity: types.AlertError}
+		require.NoError(t, a.Validate())
+		require.ErrorContains(t, a.ValidateWithPolicy(p), "slackChannelId")
+
+		a = &types.Alert{Header: "foo", IconEmoji: ":abcd:", Severity: types.AlertError}
+		require.NoError(t, a.Validate())
+		require.ErrorContains(t, a.ValidateWithPolicy(p), "iconEmoji")
+
+		a = &types.Alert{
+			Header:     "foo",
+			Severity:   types.AlertError,
+			Escalation: []*types.Escalation{{Severity: types.AlertPanic, DelaySeconds: 60, SlackMentions: []string{"<!channel>", "<@U1234>"}}},
+		}
+		require.NoError(t, a.Validate())
+		require.ErrorContains(t, a.ValidateWithPolicy(p), "escalation[0].slackMentions[1]")
+	})
+}
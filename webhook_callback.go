@@ -14,6 +14,12 @@ type WebhookCallback struct {
 	Payload       map[string]any      `json:"payload"`
 }
 
+// UniqueID returns a unique and deterministic ID for this callback, for event and storage purposes.
+// The ID is based on the webhook, message, user and timestamp fields, and is base64 encoded to ensure it is safe for use in URLs.
+func (w *WebhookCallback) UniqueID() string {
+	return hash("webhook_callback", w.ChannelID, w.MessageID, w.ID, w.UserID, w.Timestamp.UTC().Format(time.RFC3339Nano))
+}
+
 func (w *WebhookCallback) GetPayloadValue(key string) any {
 	if w == nil || w.Payload == nil {
 		return ""
@@ -2,6 +2,7 @@ package types_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/slackmgr/types"
 	"github.com/stretchr/testify/assert"
@@ -144,3 +145,17 @@ func TestGetCheckboxInputSelectedValues(t *testing.T) {
 	val = w.GetCheckboxInputSelectedValues("invalid")
 	assert.Empty(t, val)
 }
+
+func TestWebhookCallbackUniqueID(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	w := &types.WebhookCallback{ID: "restart", UserID: "U1", ChannelID: "C1", MessageID: "123.456", Timestamp: ts}
+
+	id := w.UniqueID()
+	assert.NotEmpty(t, id)
+	assert.Equal(t, id, (&types.WebhookCallback{ID: "restart", UserID: "U1", ChannelID: "C1", MessageID: "123.456", Timestamp: ts}).UniqueID())
+
+	w.UserID = "U2"
+	assert.NotEqual(t, id, w.UniqueID())
+}